@@ -10,68 +10,48 @@ listen health_check_http_url
     mode http
     monitor-uri /healthz
     option      dontlognull
+{{- range $i := .Replicas}}
 # Check Sentinel and whether they are nominated master
-backend check_if_redis_is_master_0
+backend check_if_redis_is_master_{{$i}}
     mode tcp
     option tcp-check
-    tcp-check connect
-    tcp-check send PING\r\n
-    tcp-check expect string +PONG
-    tcp-check send SENTINEL\ get-master-addr-by-name\ argocd\r\n
-    tcp-check expect string REPLACE_ANNOUNCE0
-    tcp-check send QUIT\r\n
-    tcp-check expect string +OK
-    server R0 {{.ServiceName}}-announce-0:26379 check inter 3s
-    server R1 {{.ServiceName}}-announce-1:26379 check inter 3s
-    server R2 {{.ServiceName}}-announce-2:26379 check inter 3s
-# Check Sentinel and whether they are nominated master
-backend check_if_redis_is_master_1
-    mode tcp
-    option tcp-check
-    tcp-check connect
-    tcp-check send PING\r\n
-    tcp-check expect string +PONG
-    tcp-check send SENTINEL\ get-master-addr-by-name\ argocd\r\n
-    tcp-check expect string REPLACE_ANNOUNCE1
-    tcp-check send QUIT\r\n
+    tcp-check connect{{if $.TLSEnabled}} ssl{{end}}
+{{- if $.AuthEnabled}}
+    tcp-check send AUTH\ REPLACE_AUTH_SECRET\r\n
     tcp-check expect string +OK
-    server R0 {{.ServiceName}}-announce-0:26379 check inter 3s
-    server R1 {{.ServiceName}}-announce-1:26379 check inter 3s
-    server R2 {{.ServiceName}}-announce-2:26379 check inter 3s
-# Check Sentinel and whether they are nominated master
-backend check_if_redis_is_master_2
-    mode tcp
-    option tcp-check
-    tcp-check connect
+{{- end}}
     tcp-check send PING\r\n
     tcp-check expect string +PONG
     tcp-check send SENTINEL\ get-master-addr-by-name\ argocd\r\n
-    tcp-check expect string REPLACE_ANNOUNCE2
+    tcp-check expect string REPLACE_ANNOUNCE{{$i}}
     tcp-check send QUIT\r\n
     tcp-check expect string +OK
-    server R0 {{.ServiceName}}-announce-0:26379 check inter 3s
-    server R1 {{.ServiceName}}-announce-1:26379 check inter 3s
-    server R2 {{.ServiceName}}-announce-2:26379 check inter 3s
+{{- range $j := $.Replicas}}
+    server R{{$j}} {{$.ServiceName}}-announce-{{$j}}:26379 check inter 3s{{if $.TLSEnabled}} check-ssl verify required ca-file {{$.TLSPath}}/ca.crt{{end}}
+{{- end}}
+{{- end}}
 
 # decide redis backend to use
 #master
 frontend ft_redis_master
-    bind *:6379
+    bind *:6379{{if .TLSEnabled}} ssl crt /usr/local/etc/haproxy/redis.pem{{end}}
     use_backend bk_redis_master
 # Check all redis servers to see if they think they are master
 backend bk_redis_master
     mode tcp
     option tcp-check
-    tcp-check connect
+    tcp-check connect{{if .TLSEnabled}} ssl{{end}}
+{{- if .AuthEnabled}}
+    tcp-check send AUTH\ REPLACE_AUTH_SECRET\r\n
+    tcp-check expect string +OK
+{{- end}}
     tcp-check send PING\r\n
     tcp-check expect string +PONG
     tcp-check send info\ replication\r\n
     tcp-check expect string role:master
     tcp-check send QUIT\r\n
     tcp-check expect string +OK
-    use-server R0 if { srv_is_up(R0) } { nbsrv(check_if_redis_is_master_0) ge 2 }
-    server R0 {{.ServiceName}}-announce-0:6379 check inter 3s fall 1 rise 1
-    use-server R1 if { srv_is_up(R1) } { nbsrv(check_if_redis_is_master_1) ge 2 }
-    server R1 {{.ServiceName}}-announce-1:6379 check inter 3s fall 1 rise 1
-    use-server R2 if { srv_is_up(R2) } { nbsrv(check_if_redis_is_master_2) ge 2 }
-    server R2 {{.ServiceName}}-announce-2:6379 check inter 3s fall 1 rise 1
\ No newline at end of file
+{{- range $i := .Replicas}}
+    use-server R{{$i}} if { srv_is_up(R{{$i}}) } { nbsrv(check_if_redis_is_master_{{$i}}) ge {{$.Quorum}} }
+    server R{{$i}} {{$.ServiceName}}-announce-{{$i}}:6379 check inter 3s fall 1 rise 1{{if $.TLSEnabled}} ssl verify required ca-file {{$.TLSPath}}/ca.crt{{end}}
+{{- end}}
\ No newline at end of file
@@ -1,8 +1,12 @@
 HOSTNAME="$(cat /proc/sys/kernel/hostname)"
 INDEX="${HOSTNAME##*-}"
-MASTER="$(redis-cli -h {{.ServiceName}} -p 26379 sentinel get-master-addr-by-name argocd | grep -E '[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}')"
+REDIS_CLI_OPTS="{{if .TLSEnabled}}--tls --cacert {{.TLSPath}}/ca.crt{{end}}"
+if [ "${AUTH:-}" ]; then
+    export REDISCLI_AUTH="$AUTH"
+fi
+MASTER="$(redis-cli $REDIS_CLI_OPTS -h {{.ServiceName}} -p 26379 sentinel get-master-addr-by-name argocd | grep -E '[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}')"
 MASTER_GROUP="argocd"
-QUORUM="2"
+QUORUM="{{.Quorum}}"
 REDIS_CONF=/data/conf/redis.conf
 REDIS_PORT=6379
 SENTINEL_CONF=/data/conf/sentinel.conf
@@ -52,14 +56,14 @@ setup_defaults() {
 
 find_master() {
     echo "Attempting to find master"
-    if [ "$(redis-cli -h "$MASTER" ping)" != "PONG" ]; then
+    if [ "$(redis-cli $REDIS_CLI_OPTS -h "$MASTER" ping)" != "PONG" ]; then
         echo "Can't ping master, attempting to force failover"
-        if redis-cli -h "$SERVICE" -p "$SENTINEL_PORT" sentinel failover "$MASTER_GROUP" | grep -q 'NOGOODSLAVE' ; then
+        if redis-cli $REDIS_CLI_OPTS -h "$SERVICE" -p "$SENTINEL_PORT" sentinel failover "$MASTER_GROUP" | grep -q 'NOGOODSLAVE' ; then
             setup_defaults
             return 0
         fi
         sleep 10
-        MASTER="$(redis-cli -h $SERVICE -p $SENTINEL_PORT sentinel get-master-addr-by-name $MASTER_GROUP | grep -E '[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}')"
+        MASTER="$(redis-cli $REDIS_CLI_OPTS -h $SERVICE -p $SENTINEL_PORT sentinel get-master-addr-by-name $MASTER_GROUP | grep -E '[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}')"
         if [ "$MASTER" ]; then
             sentinel_update "$MASTER"
             redis_update "$MASTER"
@@ -1,5 +1,15 @@
 dir "/data"
+{{- if .TLSEnabled}}
+port 0
+tls-port 6379
+tls-cert-file {{.TLSPath}}/tls.crt
+tls-key-file {{.TLSPath}}/tls.key
+tls-ca-cert-file {{.TLSPath}}/ca.crt
+tls-replication yes
+tls-auth-clients no
+{{- else}}
 port 6379
+{{- end}}
 maxmemory 0
 maxmemory-policy volatile-lru
 min-replicas-max-lag 5
@@ -8,4 +18,11 @@ rdbchecksum yes
 rdbcompression yes
 repl-diskless-sync yes
 save ""
+{{- if .Persistence}}
+appendonly yes
+{{- end}}
 protected-mode no
+{{- if .AuthEnabled}}
+requirepass replace-default-auth
+masterauth replace-default-auth
+{{- end}}
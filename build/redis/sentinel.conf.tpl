@@ -3,3 +3,16 @@ dir "/data"
     sentinel failover-timeout argocd 180000
     maxclients 10000
     sentinel parallel-syncs argocd 5
+{{- if .TLSEnabled}}
+    port 0
+    tls-port 26379
+    tls-cert-file {{.TLSPath}}/tls.crt
+    tls-key-file {{.TLSPath}}/tls.key
+    tls-ca-cert-file {{.TLSPath}}/ca.crt
+    tls-replication yes
+    tls-auth-clients no
+{{- end}}
+{{- if .AuthEnabled}}
+    requirepass replace-default-auth
+    sentinel auth-pass argocd replace-default-auth
+{{- end}}
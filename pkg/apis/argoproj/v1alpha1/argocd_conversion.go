@@ -0,0 +1,418 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+const (
+	// rawFieldAnnotationPrefix is the prefix for the annotations on a v1beta1 ArgoCD that hold the original
+	// v1alpha1 string value of a structured field, so that the exact value can be restored on the way back.
+	rawFieldAnnotationPrefix = "argocd.argoproj.io/v1alpha1."
+
+	// hubFieldsAnnotation is the annotation on a v1alpha1 ArgoCD that holds the v1beta1 fields that have no
+	// v1alpha1 equivalent, so that they survive a round trip through v1alpha1.
+	hubFieldsAnnotation = "argocd.argoproj.io/v1beta1-fields"
+)
+
+// stringField describes a v1alpha1 string field that is a structured field in v1beta1.
+type stringField struct {
+	// name is the json name of the field in both versions.
+	name string
+
+	// alpha returns a pointer to the field in the v1alpha1 spec.
+	alpha func(*ArgoCDSpec) *string
+
+	// beta returns a pointer to the field in the v1beta1 spec.
+	beta func(*v1beta1.ArgoCDSpec) interface{}
+}
+
+// stringFields are the fields that were changed from YAML strings to structured fields in v1beta1.
+var stringFields = []stringField{
+	{
+		name:  "configManagementPlugins",
+		alpha: func(s *ArgoCDSpec) *string { return &s.ConfigManagementPlugins },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.ConfigManagementPlugins },
+	},
+	{
+		name:  "dex.config",
+		alpha: func(s *ArgoCDSpec) *string { return &s.Dex.Config },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.Dex.Config },
+	},
+	{
+		name:  "initialRepositories",
+		alpha: func(s *ArgoCDSpec) *string { return &s.InitialRepositories },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.InitialRepositories },
+	},
+	{
+		name:  "oidcConfig",
+		alpha: func(s *ArgoCDSpec) *string { return &s.OIDCConfig },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.OIDCConfig },
+	},
+	{
+		name:  "repositoryCredentials",
+		alpha: func(s *ArgoCDSpec) *string { return &s.RepositoryCredentials },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.RepositoryCredentials },
+	},
+	{
+		name:  "resourceCustomizations",
+		alpha: func(s *ArgoCDSpec) *string { return &s.ResourceCustomizations },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.ResourceCustomizations },
+	},
+	{
+		name:  "resourceExclusions",
+		alpha: func(s *ArgoCDSpec) *string { return &s.ResourceExclusions },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.ResourceExclusions },
+	},
+	{
+		name:  "resourceInclusions",
+		alpha: func(s *ArgoCDSpec) *string { return &s.ResourceInclusions },
+		beta:  func(s *v1beta1.ArgoCDSpec) interface{} { return &s.ResourceInclusions },
+	},
+}
+
+// versionedFields holds the parts of an ArgoCD that are converted between versions.
+type versionedFields struct {
+	Spec   interface{} `json:"spec,omitempty"`
+	Status interface{} `json:"status,omitempty"`
+}
+
+// ConvertTo converts this ArgoCD to the hub version (v1beta1).
+func (src *ArgoCD) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.ArgoCD)
+	in := src.DeepCopy()
+
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	delete(dst.Annotations, hubFieldsAnnotation)
+	for _, f := range stringFields {
+		delete(dst.Annotations, rawFieldAnnotationPrefix+f.name)
+	}
+
+	// The string fields are converted on their own, everything else has the same shape in both versions.
+	raw := make(map[string]string)
+	for _, f := range stringFields {
+		raw[f.name] = *f.alpha(&in.Spec)
+		*f.alpha(&in.Spec) = ""
+	}
+
+	if err := convertFields(&in.Spec, &in.Status, &dst.Spec, &dst.Status); err != nil {
+		return err
+	}
+
+	for _, f := range stringFields {
+		value := raw[f.name]
+		if value == "" {
+			continue
+		}
+
+		// A value that can't be parsed is kept only in the annotation, the API server accepted it as a string.
+		field := reflect.ValueOf(f.beta(&dst.Spec)).Elem()
+		if parsed, err := parseStringField(field.Type(), value); err == nil {
+			field.Set(parsed)
+		}
+		setAnnotation(&dst.ObjectMeta.Annotations, rawFieldAnnotationPrefix+f.name, value)
+	}
+
+	if hubFields, ok := src.Annotations[hubFieldsAnnotation]; ok {
+		return restoreHubFields(dst, hubFields)
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version.
+func (dst *ArgoCD) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.ArgoCD)
+	in := src.DeepCopy()
+
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	structured := make(map[string]interface{})
+	for _, f := range stringFields {
+		field := reflect.ValueOf(f.beta(&in.Spec)).Elem()
+		structured[f.name] = field.Interface()
+		field.Set(reflect.Zero(field.Type()))
+	}
+
+	if err := convertFields(&in.Spec, &in.Status, &dst.Spec, &dst.Status); err != nil {
+		return err
+	}
+
+	for _, f := range stringFields {
+		key := rawFieldAnnotationPrefix + f.name
+		raw, hasRaw := dst.Annotations[key]
+		delete(dst.Annotations, key)
+
+		value, err := toYAMLString(structured[f.name], raw, hasRaw)
+		if err != nil {
+			return err
+		}
+		*f.alpha(&dst.Spec) = value
+	}
+
+	hubFields, err := lostHubFields(src, dst)
+	if err != nil {
+		return err
+	}
+	if hubFields != "" {
+		setAnnotation(&dst.ObjectMeta.Annotations, hubFieldsAnnotation, hubFields)
+	} else {
+		delete(dst.Annotations, hubFieldsAnnotation)
+	}
+
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	return nil
+}
+
+// ValidateStringFields returns an error for each v1alpha1 string field of the given ArgoCD that could not be
+// converted to its structured v1beta1 field.
+func ValidateStringFields(cr *v1beta1.ArgoCD) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, f := range stringFields {
+		if raw, err := parseRawStringField(cr, f); err != nil {
+			path := field.NewPath("spec", strings.Split(f.name, ".")...)
+			allErrs = append(allErrs, field.Invalid(path, raw, fmt.Sprintf("unable to parse YAML: %v", err)))
+		}
+	}
+	return allErrs
+}
+
+// GetUnparsedStringField returns the original v1alpha1 string of the named field of the given ArgoCD when it could
+// not be converted to its structured v1beta1 field, so that it can be used as is.
+func GetUnparsedStringField(cr *v1beta1.ArgoCD, name string) (string, bool) {
+	for _, f := range stringFields {
+		if f.name != name {
+			continue
+		}
+		if raw, err := parseRawStringField(cr, f); err != nil {
+			return raw, true
+		}
+	}
+	return "", false
+}
+
+// parseRawStringField parses the original v1alpha1 string of the given field, it returns the string and the parse
+// error. The original string is only used when the structured field has not been set since.
+func parseRawStringField(cr *v1beta1.ArgoCD, f stringField) (string, error) {
+	raw, ok := cr.Annotations[rawFieldAnnotationPrefix+f.name]
+	if !ok {
+		return "", nil
+	}
+
+	value := reflect.ValueOf(f.beta(&cr.Spec)).Elem()
+	if !isZero(value.Interface()) {
+		return raw, nil
+	}
+
+	_, err := parseStringField(value.Type(), raw)
+	return raw, err
+}
+
+// parseStringField parses the given v1alpha1 string value into a new value of the given structured type.
+func parseStringField(t reflect.Type, value string) (reflect.Value, error) {
+	parsed := reflect.New(t)
+	if err := yaml.UnmarshalStrict([]byte(value), parsed.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return parsed.Elem(), nil
+}
+
+// convertFields copies the spec and status between versions using their common json representation.
+func convertFields(srcSpec, srcStatus, dstSpec, dstStatus interface{}) error {
+	data, err := json.Marshal(versionedFields{Spec: srcSpec, Status: srcStatus})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &versionedFields{Spec: dstSpec, Status: dstStatus})
+}
+
+// toYAMLString returns the v1alpha1 string value for the given structured value. The original string is
+// returned when it is still an accurate representation of the value, so that formatting and comments survive.
+// An original string that could not be parsed is returned as is, as long as the value was not set since.
+func toYAMLString(value interface{}, raw string, hasRaw bool) (string, error) {
+	if hasRaw {
+		parsed, err := parseStringField(reflect.TypeOf(value), raw)
+		if err != nil {
+			if isZero(value) {
+				return raw, nil
+			}
+		} else if isEquivalent(parsed.Interface(), value) {
+			return raw, nil
+		}
+	}
+
+	if isZero(value) {
+		return "", nil
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// lostHubFields returns the json encoded v1beta1 fields of src that are not represented in dst.
+func lostHubFields(src *v1beta1.ArgoCD, dst *ArgoCD) (string, error) {
+	roundTrip := &v1beta1.ArgoCD{}
+	alpha := dst.DeepCopy()
+	delete(alpha.Annotations, hubFieldsAnnotation)
+	if err := alpha.ConvertTo(roundTrip); err != nil {
+		return "", err
+	}
+
+	want, err := toMap(versionedFields{Spec: src.Spec, Status: src.Status})
+	if err != nil {
+		return "", err
+	}
+
+	got, err := toMap(versionedFields{Spec: roundTrip.Spec, Status: roundTrip.Status})
+	if err != nil {
+		return "", err
+	}
+
+	missing := missingFields(want, got)
+	if len(missing) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(missing)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// restoreHubFields merges the json encoded v1beta1 fields saved by ConvertFrom into dst.
+func restoreHubFields(dst *v1beta1.ArgoCD, hubFields string) error {
+	saved := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(hubFields), &saved); err != nil {
+		return err
+	}
+
+	current, err := toMap(versionedFields{Spec: dst.Spec, Status: dst.Status})
+	if err != nil {
+		return err
+	}
+	mergeFields(current, saved)
+
+	data, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec = v1beta1.ArgoCDSpec{}
+	dst.Status = v1beta1.ArgoCDStatus{}
+	return json.Unmarshal(data, &versionedFields{Spec: &dst.Spec, Status: &dst.Status})
+}
+
+// missingFields returns the fields in want that are not present in got. Nested objects are compared field by
+// field, any other value is considered present when the key exists.
+func missingFields(want, got map[string]interface{}) map[string]interface{} {
+	missing := make(map[string]interface{})
+	for key, wantValue := range want {
+		gotValue, ok := got[key]
+		if !ok {
+			missing[key] = wantValue
+			continue
+		}
+
+		wantMap, wantIsMap := wantValue.(map[string]interface{})
+		gotMap, gotIsMap := gotValue.(map[string]interface{})
+		if wantIsMap && gotIsMap {
+			if m := missingFields(wantMap, gotMap); len(m) > 0 {
+				missing[key] = m
+			}
+		}
+	}
+	return missing
+}
+
+// mergeFields recursively sets the fields from src on dst.
+func mergeFields(dst, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeFields(dstMap, srcMap)
+			continue
+		}
+		dst[key] = srcValue
+	}
+}
+
+// toMap returns the generic json representation of the given value.
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// isEquivalent returns true if both values have the same json representation, ignoring formatting.
+func isEquivalent(a, b interface{}) bool {
+	aData, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	bData, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	var aValue, bValue interface{}
+	if json.Unmarshal(aData, &aValue) != nil || json.Unmarshal(bData, &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// isZero returns true if the given value is nil or empty.
+func isZero(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice:
+		return value.Len() == 0
+	case reflect.Ptr:
+		return value.IsNil()
+	}
+	return !value.IsValid() || value.IsZero()
+}
+
+// setAnnotation sets the given annotation, creating the annotations map when needed.
+func setAnnotation(annotations *map[string]string, key string, value string) {
+	if *annotations == nil {
+		*annotations = make(map[string]string)
+	}
+	(*annotations)[key] = value
+}
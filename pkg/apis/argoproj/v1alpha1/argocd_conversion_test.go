@@ -0,0 +1,289 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+func makeTestArgoCDv1alpha1() *ArgoCD {
+	return &ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "argocd",
+			Namespace:   "argocd",
+			Annotations: map[string]string{"example.com/owner": "team-a"},
+		},
+		Spec: ArgoCDSpec{
+			Dex: ArgoCDDexSpec{
+				Config: `connectors:
+  # GitHub organization login
+  - type: github
+    id: github
+    name: GitHub
+    config:
+      clientID: abc
+      clientSecret: $dex.github.clientSecret
+      orgs:
+      - name: my-org
+`,
+			},
+			InitialRepositories: `- url: https://github.com/argoproj/argocd-example-apps.git
+  usernameSecret:
+    name: repo-secret
+    key: username
+`,
+			OIDCConfig: `name: Okta
+issuer: https://dev-123456.oktapreview.com
+clientID: aaaabbbbccccddddeee
+clientSecret: $oidc.okta.clientSecret
+requestedIDTokenClaims: {"groups": {"essential": true}}
+`,
+			ResourceCustomizations: `admissionregistration.k8s.io/MutatingWebhookConfiguration:
+  ignoreDifferences: |
+    jsonPointers:
+    - /webhooks/0/clientConfig/caBundle
+`,
+			ResourceExclusions: `- apiGroups:
+  - tekton.dev
+  kinds:
+  - TaskRun
+  - PipelineRun
+`,
+			Version: "v1.7.7",
+		},
+		Status: ArgoCDStatus{
+			Phase: "Available",
+		},
+	}
+}
+
+func TestArgoCD_ConvertTo(t *testing.T) {
+	alpha := makeTestArgoCDv1alpha1()
+
+	beta := &v1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(beta))
+
+	assert.Equal(t, beta.Spec.Version, "v1.7.7")
+	assert.Equal(t, beta.Status.Phase, "Available")
+	assert.Equal(t, beta.Annotations["example.com/owner"], "team-a")
+
+	assert.Equal(t, len(beta.Spec.Dex.Config.Connectors), 1)
+	assert.Equal(t, beta.Spec.Dex.Config.Connectors[0].Type, "github")
+	assert.Equal(t, beta.Spec.InitialRepositories[0].UsernameSecret.Name, "repo-secret")
+	assert.Equal(t, beta.Spec.OIDCConfig.Issuer, "https://dev-123456.oktapreview.com")
+	assert.Equal(t, beta.Spec.OIDCConfig.RequestedIDTokenClaims["groups"].Essential, true)
+	assert.Equal(t, beta.Spec.ResourceCustomizations["admissionregistration.k8s.io/MutatingWebhookConfiguration"].IgnoreDifferences,
+		"jsonPointers:\n- /webhooks/0/clientConfig/caBundle\n")
+	assert.DeepEqual(t, beta.Spec.ResourceExclusions, []v1beta1.ArgoCDFilteredResource{{
+		APIGroups: []string{"tekton.dev"},
+		Kinds:     []string{"TaskRun", "PipelineRun"},
+	}})
+}
+
+func TestArgoCD_ConvertTo_invalidString(t *testing.T) {
+	alpha := makeTestArgoCDv1alpha1()
+	alpha.Spec.ResourceInclusions = "testing: testing"
+
+	beta := &v1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(beta))
+	assert.Assert(t, beta.Spec.ResourceInclusions == nil)
+
+	errs := ValidateStringFields(beta)
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Field, "spec.resourceInclusions")
+	assert.ErrorContains(t, errs.ToAggregate(), "unable to parse YAML")
+
+	raw, ok := GetUnparsedStringField(beta, "resourceInclusions")
+	assert.Assert(t, ok)
+	assert.Equal(t, raw, "testing: testing")
+	_, ok = GetUnparsedStringField(beta, "resourceExclusions")
+	assert.Assert(t, !ok)
+
+	roundTrip := &ArgoCD{}
+	assert.NilError(t, roundTrip.ConvertFrom(beta))
+	assert.Equal(t, roundTrip.Spec.ResourceInclusions, "testing: testing")
+
+	// Setting the structured field replaces the invalid string.
+	beta.Spec.ResourceInclusions = []v1beta1.ArgoCDFilteredResource{{Kinds: []string{"Deployment"}}}
+	assert.Equal(t, len(ValidateStringFields(beta)), 0)
+}
+
+func TestArgoCD_ConvertTo_dexOptions(t *testing.T) {
+	alpha := makeTestArgoCDv1alpha1()
+	alpha.Spec.Dex.Config = `logger:
+  level: debug
+staticClients:
+- id: example-app
+  name: Example App
+  redirectURIs:
+  - https://example.com/callback
+connectors:
+- type: github
+  id: github
+  name: GitHub
+`
+
+	beta := &v1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(beta))
+	assert.Assert(t, beta.Spec.Dex.Config != nil)
+	assert.Equal(t, len(beta.Spec.Dex.Config.Connectors), 1)
+	assert.Equal(t, beta.Spec.Dex.Config.Connectors[0].ID, "github")
+	assert.Equal(t, len(beta.Spec.Dex.Config.Options), 2)
+	assert.Equal(t, string(beta.Spec.Dex.Config.Options["logger"].Raw), `{"level":"debug"}`)
+	assert.Equal(t, len(ValidateStringFields(beta)), 0)
+
+	roundTrip := &ArgoCD{}
+	assert.NilError(t, roundTrip.ConvertFrom(beta))
+	assert.Equal(t, roundTrip.Spec.Dex.Config, alpha.Spec.Dex.Config)
+
+	// Unknown connector fields are still rejected, they would be lost.
+	alpha.Spec.Dex.Config = "connectors:\n- type: github\n  id: github\n  name: GitHub\n  clientID: foo\n"
+	beta = &v1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(beta))
+	assert.Assert(t, beta.Spec.Dex.Config == nil)
+	assert.Equal(t, len(ValidateStringFields(beta)), 1)
+}
+
+func TestArgoCD_v1alpha1RoundTrip(t *testing.T) {
+	alpha := makeTestArgoCDv1alpha1()
+
+	beta := &v1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(beta))
+
+	roundTrip := &ArgoCD{}
+	assert.NilError(t, roundTrip.ConvertFrom(beta))
+
+	if diff := cmp.Diff(alpha, roundTrip); diff != "" {
+		t.Fatalf("v1alpha1 round trip failed:\n%s", diff)
+	}
+}
+
+func TestArgoCD_v1beta1RoundTrip(t *testing.T) {
+	beta := &v1beta1.ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd",
+			Namespace: "argocd",
+		},
+		Spec: v1beta1.ArgoCDSpec{
+			ConfigManagementPlugins: []v1beta1.ArgoCDConfigManagementPlugin{{
+				Name: "kasane",
+				Init: &v1beta1.ArgoCDConfigManagementPluginCommand{
+					Command: []string{"kasane", "update"},
+				},
+				Generate: v1beta1.ArgoCDConfigManagementPluginCommand{
+					Command: []string{"kasane", "show"},
+				},
+			}},
+			Dex: v1beta1.ArgoCDDexSpec{
+				Config: &v1beta1.ArgoCDDexConfig{
+					Connectors: []v1beta1.ArgoCDDexConnector{{
+						Type:   "ldap",
+						ID:     "ldap",
+						Name:   "LDAP",
+						Config: &runtime.RawExtension{Raw: []byte(`{"host":"ldap.example.com:636"}`)},
+					}},
+				},
+			},
+			RepositoryCredentials: []v1beta1.ArgoCDRepositorySpec{{
+				URL: "git@github.com:argoproj",
+				SSHPrivateKeySecret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "github-ssh"},
+					Key:                  "sshPrivateKey",
+				},
+			}},
+			ResourceInclusions: []v1beta1.ArgoCDFilteredResource{{
+				APIGroups: []string{"*"},
+				Kinds:     []string{"*"},
+				Clusters:  []string{"https://kubernetes.default.svc"},
+			}},
+		},
+	}
+
+	alpha := &ArgoCD{}
+	assert.NilError(t, alpha.ConvertFrom(beta))
+	assert.Assert(t, alpha.Spec.ConfigManagementPlugins != "")
+	assert.Assert(t, alpha.Spec.Dex.Config != "")
+
+	roundTrip := &v1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(roundTrip))
+
+	// The v1alpha1 strings are kept, so that a later conversion back to v1alpha1 is exact.
+	roundTrip.Annotations = nil
+
+	if diff := cmp.Diff(beta, roundTrip); diff != "" {
+		t.Fatalf("v1beta1 round trip failed:\n%s", diff)
+	}
+}
+
+func TestArgoCD_ConvertTo_updatedString(t *testing.T) {
+	alpha := makeTestArgoCDv1alpha1()
+
+	beta := &v1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(beta))
+
+	// Changing the structured field must win over the original string.
+	beta.Spec.ResourceExclusions = []v1beta1.ArgoCDFilteredResource{{Kinds: []string{"Secret"}}}
+
+	updated := &ArgoCD{}
+	assert.NilError(t, updated.ConvertFrom(beta))
+	assert.Equal(t, updated.Spec.ResourceExclusions, "- kinds:\n  - Secret\n")
+	assert.Equal(t, updated.Spec.Dex.Config, alpha.Spec.Dex.Config)
+}
+
+func TestMissingFields(t *testing.T) {
+	want := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version": "v1.7.7",
+			"server": map[string]interface{}{
+				"host":     "argocd.example.com",
+				"replicas": float64(3),
+			},
+		},
+		"status": map[string]interface{}{
+			"phase": "Available",
+		},
+	}
+	got := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version": "v1.7.7",
+			"server": map[string]interface{}{
+				"host": "argocd.example.com",
+			},
+		},
+	}
+
+	missing := missingFields(want, got)
+	assert.DeepEqual(t, missing, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"server": map[string]interface{}{
+				"replicas": float64(3),
+			},
+		},
+		"status": map[string]interface{}{
+			"phase": "Available",
+		},
+	})
+
+	mergeFields(got, missing)
+	assert.DeepEqual(t, got, want)
+}
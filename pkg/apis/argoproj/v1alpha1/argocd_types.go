@@ -269,6 +269,7 @@ type ArgoCDRepoSpec struct {
 	// AutoTLS specifies the method to use for automatic TLS configuration for the repo server
 	// The value specified here can currently be:
 	// - openshift - Use the OpenShift service CA to request TLS config
+	// - operator - Use the operator CA to issue the TLS config, the clients of the repo server verify it strictly
 	AutoTLS string `json:"autotls,omitempty"`
 }
 
@@ -0,0 +1,1573 @@
+// Copyright 2019 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	routev1 "github.com/openshift/api/route/v1"
+
+	autoscaling "k8s.io/api/autoscaling/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func init() {
+	SchemeBuilder.Register(&ArgoCD{}, &ArgoCDList{})
+}
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ArgoCD is the Schema for the argocds API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ArgoCD struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ArgoCDSpec   `json:"spec,omitempty"`
+	Status ArgoCDStatus `json:"status,omitempty"`
+}
+
+// ArgoCDApplicationControllerProcessorsSpec defines the options for the ArgoCD Application Controller processors.
+type ArgoCDApplicationControllerProcessorsSpec struct {
+	// Operation is the number of application operation processors.
+	Operation int32 `json:"operation,omitempty"`
+
+	// Status is the number of application status processors.
+	Status int32 `json:"status,omitempty"`
+}
+
+// ArgoCDAdminPasswordSpec defines the options for the password of the admin user.
+type ArgoCDAdminPasswordSpec struct {
+	// RotationInterval is the interval at which the operator generates a new admin password. The password is not
+	// rotated when it is not set, nor when the password is read from a Secret.
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+
+	// SecretRef is the key of a Secret in the namespace of the ArgoCD that holds the admin password. The operator
+	// generates the password when it is not set.
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// ArgoCDApplicationControllerSpec defines the options for the ArgoCD Application Controller component.
+type ArgoCDApplicationControllerSpec struct {
+	// Processors contains the options for the Application Controller processors.
+	Processors ArgoCDApplicationControllerProcessorsSpec `json:"processors,omitempty"`
+
+	// PodOverrides defines the scheduling and customization overrides for the Application Controller pods.
+	PodOverrides *ArgoCDPodOverrideSpec `json:"podOverrides,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for the Application Controller.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// AppSync is used to control the sync frequency, by default the ArgoCD
+	// controller polls Git every 3m by default.
+	//
+	// Set this to a duration, e.g. 10m or 600s to control the synchronisation
+	// frequency.
+	// +optional
+	AppSync *metav1.Duration `json:"appSync,omitempty"`
+
+	// Sharding contains the options for sharding the managed clusters across Application Controller replicas.
+	Sharding ArgoCDApplicationControllerShardSpec `json:"sharding,omitempty"`
+}
+
+// ArgoCDApplicationControllerShardSpec defines the options for sharding the ArgoCD Application Controller.
+type ArgoCDApplicationControllerShardSpec struct {
+	// Enabled toggles sharding of the managed clusters across the Application Controller replicas.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Replicas is the number of Application Controller replicas, each replica manages one shard of the clusters.
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas,omitempty"`
+}
+
+// ArgoCDApplicationControllerShardStatus defines the observed state of a single Application Controller shard.
+type ArgoCDApplicationControllerShardStatus struct {
+	// Shard is the index of the shard, it matches the ordinal of the Application Controller replica.
+	Shard int32 `json:"shard"`
+
+	// Clusters are the names of the clusters assigned to the shard.
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// ArgoCDApplicationSet defines whether the Argo CD ApplicationSet controller should be installed.
+type ArgoCDApplicationSet struct {
+
+	// Image is the Argo CD ApplicationSet image (optional)
+	Image string `json:"image,omitempty"`
+
+	// Version is the Argo CD ApplicationSet image tag. (optional)
+	Version string `json:"version,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for ApplicationSet.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Autoscale defines the autoscale options for the ApplicationSet controller.
+	Autoscale ArgoCDAutoscaleSpec `json:"autoscale,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget options for the ApplicationSet controller pods.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// Replicas is the replica count for the ApplicationSet controller Deployment, it is ignored when autoscaling
+	// is enabled.
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// ArgoCDAutoscaleSpec defines the desired state for autoscaling an Argo CD component.
+type ArgoCDAutoscaleSpec struct {
+	// Behavior configures the scaling behavior in the up and down directions.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Behavior *autoscalingv2beta2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+
+	// Enabled will toggle autoscaling support for the component.
+	Enabled bool `json:"enabled"`
+
+	// HPA defines the HorizontalPodAutoscaler options for the component using the autoscaling/v1 API.
+	// MinReplicas, MaxReplicas and Metrics take precedence over the values set here.
+	HPA *autoscaling.HorizontalPodAutoscalerSpec `json:"hpa,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas, defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+
+	// Metrics are the autoscaling/v2beta2 metrics used to calculate the desired replica count, such as memory
+	// or custom metrics. Defaults to 50% average CPU utilization.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Metrics []autoscalingv2beta2.MetricSpec `json:"metrics,omitempty"`
+
+	// MinReplicas is the lower limit for the number of replicas, defaults to 1.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+}
+
+// ArgoCDCASpec defines the CA options for ArgCD.
+type ArgoCDCASpec struct {
+	// ConfigMapName is the name of the ConfigMap containing the CA Certificate.
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Duration is the validity of the CA certificate generated by the operator. Defaults to 8760h (365 days).
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// SecretName is the name of the Secret containing the CA Certificate and Key.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// ArgoCDCertificateSpec defines the options for the ArgoCD certificates.
+type ArgoCDCertificateSpec struct {
+	// SecretName is the name of the Secret containing the Certificate and Key.
+	SecretName string `json:"secretName"`
+}
+
+// ArgoCDCertificateStatus defines the observed state of a certificate used by Argo CD.
+type ArgoCDCertificateStatus struct {
+	// NotAfter is the time at which the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
+
+	// SecretName is the name of the Secret containing the certificate.
+	SecretName string `json:"secretName"`
+}
+
+// ArgoCDCondition describes one aspect of the current state of an ArgoCD. It follows the upstream metav1.Condition
+// type, which is not available in the Kubernetes API version used by the operator.
+type ArgoCDCondition struct {
+	// Type of the condition, one of Available, Degraded, ExportReady, Progressing, Reconciled or SSOReady.
+	Type string `json:"type"`
+
+	// Status of the condition, one of True, False or Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// ObservedGeneration is the .metadata.generation that the condition was set based upon.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Reason is a programmatic identifier in CamelCase indicating the reason for the last transition.
+	Reason string `json:"reason"`
+
+	// Message is a human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// ArgoCDConditionAvailable means all of the Argo CD components are running.
+	ArgoCDConditionAvailable = "Available"
+
+	// ArgoCDConditionDegraded means the operator failed to reconcile the ArgoCD, or used the original value of a
+	// v1alpha1 string field that could not be converted.
+	ArgoCDConditionDegraded = "Degraded"
+
+	// ArgoCDConditionExportReady means the ArgoCDExport referenced by the Import options has completed.
+	ArgoCDConditionExportReady = "ExportReady"
+
+	// ArgoCDConditionProgressing means one or more of the Argo CD components are being rolled out.
+	ArgoCDConditionProgressing = "Progressing"
+
+	// ArgoCDConditionReconciled means the last reconciliation of the ArgoCD succeeded.
+	ArgoCDConditionReconciled = "Reconciled"
+
+	// ArgoCDConditionSSOReady means the configured single sign-on provider is running.
+	ArgoCDConditionSSOReady = "SSOReady"
+)
+
+const (
+	// ArgoCDReasonComponentsPending is the reason used when one or more components are not running.
+	ArgoCDReasonComponentsPending = "ComponentsPending"
+
+	// ArgoCDReasonComponentsRunning is the reason used when all components are running.
+	ArgoCDReasonComponentsRunning = "ComponentsRunning"
+
+	// ArgoCDReasonExportCompleted is the reason used when the ArgoCDExport to import has completed.
+	ArgoCDReasonExportCompleted = "ExportCompleted"
+
+	// ArgoCDReasonExportNotFound is the reason used when the ArgoCDExport to import does not exist.
+	ArgoCDReasonExportNotFound = "ExportNotFound"
+
+	// ArgoCDReasonExportPending is the reason used when the ArgoCDExport to import has not completed.
+	ArgoCDReasonExportPending = "ExportPending"
+
+	// ArgoCDReasonInvalidSpec is the reason used when the ArgoCD spec is not valid.
+	ArgoCDReasonInvalidSpec = "InvalidSpec"
+
+	// ArgoCDReasonInvalidRBACPolicy is the reason used when an RBAC policy fragment is not valid and left out.
+	ArgoCDReasonInvalidRBACPolicy = "InvalidRBACPolicy"
+
+	// ArgoCDReasonInvalidStringField is the reason used when a v1alpha1 string field could not be converted and its
+	// original value is used as is.
+	ArgoCDReasonInvalidStringField = "InvalidStringField"
+
+	// ArgoCDReasonReconcileFailed is the reason used when reconciling the ArgoCD resources failed.
+	ArgoCDReasonReconcileFailed = "ReconcileFailed"
+
+	// ArgoCDReasonReconcileSucceeded is the reason used when reconciling the ArgoCD resources succeeded.
+	ArgoCDReasonReconcileSucceeded = "ReconcileSucceeded"
+
+	// ArgoCDReasonSSOPending is the reason used when the single sign-on provider is not running.
+	ArgoCDReasonSSOPending = "SSOPending"
+
+	// ArgoCDReasonSSORunning is the reason used when the single sign-on provider is running.
+	ArgoCDReasonSSORunning = "SSORunning"
+
+	// ArgoCDReasonSSOUnsupported is the reason used when the single sign-on provider is not supported on the cluster.
+	ArgoCDReasonSSOUnsupported = "SSOUnsupported"
+
+	// ArgoCDReasonServerSecretKeyRotated is the reason used when the operator generated a new server secret key.
+	ArgoCDReasonServerSecretKeyRotated = "ServerSecretKeyRotated"
+
+	// ArgoCDReasonStorageVersionMigrationFailed is the reason used when the ArgoCD could not be rewritten at the
+	// storage version of the CRD.
+	ArgoCDReasonStorageVersionMigrationFailed = "StorageVersionMigrationFailed"
+)
+
+// ArgoCDConfigManagementPlugin defines a config management plugin for Argo CD.
+type ArgoCDConfigManagementPlugin struct {
+	// Name is the name of the plugin, as referenced by Applications.
+	Name string `json:"name"`
+
+	// Init is the command to run before Generate, usually to fetch dependencies.
+	Init *ArgoCDConfigManagementPluginCommand `json:"init,omitempty"`
+
+	// Generate is the command that renders the manifests to standard output.
+	Generate ArgoCDConfigManagementPluginCommand `json:"generate"`
+
+	// LockRepo will prevent the repository from being used concurrently while the plugin runs.
+	LockRepo bool `json:"lockRepo,omitempty"`
+}
+
+// ArgoCDConfigManagementPluginCommand defines a command run by a config management plugin.
+type ArgoCDConfigManagementPluginCommand struct {
+	// Command is the executable and any leading arguments.
+	Command []string `json:"command,omitempty"`
+
+	// Args are the arguments passed to the Command.
+	Args []string `json:"args,omitempty"`
+}
+
+// ArgoCDDexConfig defines the Dex configuration for Argo CD.
+type ArgoCDDexConfig struct {
+	// Connectors is the list of Dex connectors to configure.
+	Connectors []ArgoCDDexConnector `json:"connectors,omitempty"`
+
+	// Options are the other top level Dex configuration options, e.g. staticClients or logger, passed to Dex as is.
+	Options map[string]runtime.RawExtension `json:"-"`
+}
+
+// MarshalJSON returns the Dex configuration with the options inlined next to the connectors.
+func (c ArgoCDDexConfig) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(c.Options)+1)
+	for key, value := range c.Options {
+		fields[key] = value
+	}
+	if len(c.Connectors) > 0 {
+		fields["connectors"] = c.Connectors
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON reads the connectors of the Dex configuration and keeps every other key as an option.
+func (c *ArgoCDDexConfig) UnmarshalJSON(data []byte) error {
+	fields := make(map[string]runtime.RawExtension)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	config := ArgoCDDexConfig{}
+	if connectors, ok := fields["connectors"]; ok {
+		decoder := json.NewDecoder(bytes.NewReader(connectors.Raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config.Connectors); err != nil {
+			return err
+		}
+		delete(fields, "connectors")
+	}
+	if len(fields) > 0 {
+		config.Options = fields
+	}
+
+	*c = config
+	return nil
+}
+
+// ArgoCDDexConnector defines a single Dex connector.
+type ArgoCDDexConnector struct {
+	// Config is the connector specific configuration, passed to Dex as is.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *runtime.RawExtension `json:"config,omitempty"`
+
+	// ID is the unique identifier for the connector.
+	ID string `json:"id"`
+
+	// Name is the display name for the connector.
+	Name string `json:"name"`
+
+	// Type is the Dex connector type, e.g. github, ldap or oidc.
+	Type string `json:"type"`
+}
+
+// ArgoCDDexConnectorSpec defines a Dex connector with typed options, exactly one of the connector types must be set.
+// The options use the field names of the Dex connector configuration.
+type ArgoCDDexConnectorSpec struct {
+	// GitHub defines the options for a GitHub connector.
+	GitHub *ArgoCDDexGitHubConnectorSpec `json:"github,omitempty"`
+
+	// GitLab defines the options for a GitLab connector.
+	GitLab *ArgoCDDexGitLabConnectorSpec `json:"gitlab,omitempty"`
+
+	// ID is the unique identifier for the connector.
+	ID string `json:"id"`
+
+	// LDAP defines the options for an LDAP connector.
+	LDAP *ArgoCDDexLDAPConnectorSpec `json:"ldap,omitempty"`
+
+	// Microsoft defines the options for a Microsoft connector.
+	Microsoft *ArgoCDDexMicrosoftConnectorSpec `json:"microsoft,omitempty"`
+
+	// Name is the display name for the connector.
+	Name string `json:"name"`
+
+	// OIDC defines the options for an OpenID Connect connector.
+	OIDC *ArgoCDDexOIDCConnectorSpec `json:"oidc,omitempty"`
+
+	// SAML defines the options for a SAML 2.0 connector.
+	SAML *ArgoCDDexSAMLConnectorSpec `json:"saml,omitempty"`
+}
+
+// ArgoCDDexGitHubConnectorSpec defines the options for a Dex GitHub connector.
+type ArgoCDDexGitHubConnectorSpec struct {
+	// ClientID is the client ID of the GitHub OAuth application.
+	ClientID string `json:"clientID"`
+
+	// ClientSecretRef is the key of the Secret that holds the client secret of the GitHub OAuth application.
+	ClientSecretRef corev1.SecretKeySelector `json:"clientSecretRef"`
+
+	// HostName is the host name of a GitHub Enterprise server, github.com is used when it is not set.
+	HostName string `json:"hostName,omitempty"`
+
+	// LoadAllGroups will include all the teams of the user in the groups claim, rather than only those of the Orgs.
+	LoadAllGroups bool `json:"loadAllGroups,omitempty"`
+
+	// Orgs is the list of organizations, and optionally teams, a user must be a member of to log in.
+	Orgs []ArgoCDDexGitHubOrgSpec `json:"orgs,omitempty"`
+
+	// TeamNameField is the team field used in the groups claim, one of name, slug or both.
+	TeamNameField string `json:"teamNameField,omitempty"`
+
+	// UseLoginAsID will use the GitHub login of the user as the user ID, rather than the numeric ID.
+	UseLoginAsID bool `json:"useLoginAsID,omitempty"`
+}
+
+// ArgoCDDexGitHubOrgSpec defines a GitHub organization a user must be a member of.
+type ArgoCDDexGitHubOrgSpec struct {
+	// Name is the name of the organization.
+	Name string `json:"name"`
+
+	// Teams is the list of teams in the organization a user must be a member of, any team when it is empty.
+	Teams []string `json:"teams,omitempty"`
+}
+
+// ArgoCDDexGitLabConnectorSpec defines the options for a Dex GitLab connector.
+type ArgoCDDexGitLabConnectorSpec struct {
+	// BaseURL is the URL of the GitLab server, https://gitlab.com is used when it is not set.
+	BaseURL string `json:"baseURL,omitempty"`
+
+	// ClientID is the application ID of the GitLab application.
+	ClientID string `json:"clientID"`
+
+	// ClientSecretRef is the key of the Secret that holds the secret of the GitLab application.
+	ClientSecretRef corev1.SecretKeySelector `json:"clientSecretRef"`
+
+	// Groups is the list of groups a user must be a member of to log in, any group when it is empty.
+	Groups []string `json:"groups,omitempty"`
+
+	// UseLoginAsID will use the GitLab username of the user as the user ID, rather than the numeric ID.
+	UseLoginAsID bool `json:"useLoginAsID,omitempty"`
+}
+
+// ArgoCDDexLDAPConnectorSpec defines the options for a Dex LDAP connector.
+type ArgoCDDexLDAPConnectorSpec struct {
+	// BindDN is the DN used to search the directory, the directory is searched anonymously when it is not set.
+	BindDN string `json:"bindDN,omitempty"`
+
+	// BindPasswordRef is the key of the Secret that holds the password of the BindDN.
+	BindPasswordRef *corev1.SecretKeySelector `json:"bindPasswordRef,omitempty"`
+
+	// GroupSearch defines how the groups of a user are found, no groups are returned when it is not set.
+	GroupSearch *ArgoCDDexLDAPGroupSearchSpec `json:"groupSearch,omitempty"`
+
+	// Host is the host and optional port of the LDAP server.
+	Host string `json:"host"`
+
+	// InsecureNoSSL will connect to the LDAP server without TLS.
+	InsecureNoSSL bool `json:"insecureNoSSL,omitempty"`
+
+	// InsecureSkipVerify will connect to the LDAP server without verifying its certificate.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// RootCAData is the PEM encoded CA bundle used to verify the certificate of the LDAP server.
+	RootCAData []byte `json:"rootCAData,omitempty"`
+
+	// StartTLS will connect to the LDAP server without TLS and upgrade the connection with StartTLS.
+	StartTLS bool `json:"startTLS,omitempty"`
+
+	// UserSearch defines how a user is found by the name entered at login.
+	UserSearch ArgoCDDexLDAPUserSearchSpec `json:"userSearch"`
+
+	// UsernamePrompt is the label of the username field on the login page.
+	UsernamePrompt string `json:"usernamePrompt,omitempty"`
+}
+
+// ArgoCDDexLDAPGroupSearchSpec defines how the groups of an LDAP user are found.
+type ArgoCDDexLDAPGroupSearchSpec struct {
+	// BaseDN is the DN to start the group search from.
+	BaseDN string `json:"baseDN"`
+
+	// Filter is an optional filter applied to the group search.
+	Filter string `json:"filter,omitempty"`
+
+	// GroupAttr is the attribute of the group that matches the UserAttr of the user.
+	GroupAttr string `json:"groupAttr"`
+
+	// NameAttr is the attribute of the group used as the group name.
+	NameAttr string `json:"nameAttr"`
+
+	// UserAttr is the attribute of the user that matches the GroupAttr of the group.
+	UserAttr string `json:"userAttr"`
+}
+
+// ArgoCDDexLDAPUserSearchSpec defines how an LDAP user is found by the name entered at login.
+type ArgoCDDexLDAPUserSearchSpec struct {
+	// BaseDN is the DN to start the user search from.
+	BaseDN string `json:"baseDN"`
+
+	// EmailAttr is the attribute of the user used as the email address.
+	EmailAttr string `json:"emailAttr,omitempty"`
+
+	// Filter is an optional filter applied to the user search.
+	Filter string `json:"filter,omitempty"`
+
+	// IDAttr is the attribute of the user used as the user ID.
+	IDAttr string `json:"idAttr,omitempty"`
+
+	// NameAttr is the attribute of the user used as the display name.
+	NameAttr string `json:"nameAttr,omitempty"`
+
+	// Username is the attribute of the user that matches the name entered at login.
+	Username string `json:"username"`
+}
+
+// ArgoCDDexMicrosoftConnectorSpec defines the options for a Dex Microsoft connector.
+type ArgoCDDexMicrosoftConnectorSpec struct {
+	// ClientID is the application ID of the Azure AD application.
+	ClientID string `json:"clientID"`
+
+	// ClientSecretRef is the key of the Secret that holds the client secret of the Azure AD application.
+	ClientSecretRef corev1.SecretKeySelector `json:"clientSecretRef"`
+
+	// Groups is the list of groups a user must be a member of to log in, any group when it is empty.
+	Groups []string `json:"groups,omitempty"`
+
+	// OnlySecurityGroups will only include the security groups of the user in the groups claim.
+	OnlySecurityGroups bool `json:"onlySecurityGroups,omitempty"`
+
+	// Tenant is the ID or domain of the Azure AD tenant, or one of common, consumers or organizations.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// ArgoCDDexOIDCConnectorSpec defines the options for a Dex OpenID Connect connector.
+type ArgoCDDexOIDCConnectorSpec struct {
+	// ClientID is the client ID registered with the provider.
+	ClientID string `json:"clientID"`
+
+	// ClientSecretRef is the key of the Secret that holds the client secret registered with the provider.
+	ClientSecretRef corev1.SecretKeySelector `json:"clientSecretRef"`
+
+	// GetUserInfo will read the claims of the user from the UserInfo endpoint of the provider.
+	GetUserInfo bool `json:"getUserInfo,omitempty"`
+
+	// InsecureEnableGroups will pass the groups claim of the provider on.
+	InsecureEnableGroups bool `json:"insecureEnableGroups,omitempty"`
+
+	// InsecureSkipEmailVerified will accept users whose email address was not verified by the provider.
+	InsecureSkipEmailVerified bool `json:"insecureSkipEmailVerified,omitempty"`
+
+	// Issuer is the URL of the provider.
+	Issuer string `json:"issuer"`
+
+	// Scopes is the list of scopes requested from the provider, profile and email when it is empty.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// UserIDKey is the claim used as the user ID, sub when it is not set.
+	UserIDKey string `json:"userIDKey,omitempty"`
+
+	// UserNameKey is the claim used as the user name, name when it is not set.
+	UserNameKey string `json:"userNameKey,omitempty"`
+}
+
+// ArgoCDDexSAMLConnectorSpec defines the options for a Dex SAML 2.0 connector.
+type ArgoCDDexSAMLConnectorSpec struct {
+	// CAData is the PEM encoded CA bundle used to verify the signature of the SAML responses.
+	CAData []byte `json:"caData,omitempty"`
+
+	// EmailAttr is the attribute of the assertion used as the email address.
+	EmailAttr string `json:"emailAttr"`
+
+	// EntityIssuer is the issuer of the SAML requests, the callback URL is used when it is not set.
+	EntityIssuer string `json:"entityIssuer,omitempty"`
+
+	// GroupsAttr is the attribute of the assertion used as the groups.
+	GroupsAttr string `json:"groupsAttr,omitempty"`
+
+	// InsecureSkipSignatureValidation will accept SAML responses without validating their signature.
+	InsecureSkipSignatureValidation bool `json:"insecureSkipSignatureValidation,omitempty"`
+
+	// NameIDPolicyFormat is the format of the NameID requested from the provider.
+	NameIDPolicyFormat string `json:"nameIDPolicyFormat,omitempty"`
+
+	// SSOIssuer is the expected issuer of the SAML responses.
+	SSOIssuer string `json:"ssoIssuer,omitempty"`
+
+	// SSOURL is the URL of the provider the SAML requests are sent to.
+	SSOURL string `json:"ssoURL"`
+
+	// UsernameAttr is the attribute of the assertion used as the user name.
+	UsernameAttr string `json:"usernameAttr"`
+}
+
+// ArgoCDDexSpec defines the desired state for the Dex server component.
+type ArgoCDDexSpec struct {
+	// Config is the dex connector configuration.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *ArgoCDDexConfig `json:"config,omitempty"`
+
+	// Connectors is the list of Dex connectors with typed options, added to the connectors in Config. The secrets
+	// they reference are copied to the argocd-secret Secret.
+	Connectors []ArgoCDDexConnectorSpec `json:"connectors,omitempty"`
+
+	// Image is the Dex container image.
+	Image string `json:"image,omitempty"`
+
+	// OpenShiftOAuth enables OpenShift OAuth authentication for the Dex server.
+	OpenShiftOAuth bool `json:"openShiftOAuth,omitempty"`
+
+	// OpenShiftOAuthCA is the CA bundle Dex uses to verify the OpenShift API and OAuth servers, either ServiceAccount
+	// for the CA mounted with the service account token or ServiceCA for the OpenShift service CA bundle. Defaults to
+	// ServiceAccount.
+	// +kubebuilder:validation:Enum=ServiceAccount;ServiceCA
+	OpenShiftOAuthCA string `json:"openShiftOAuthCA,omitempty"`
+
+	// OpenShiftOAuthIssuer is the URL of the OpenShift API server used as the issuer by Dex. The internal API server
+	// URL of the cluster is used when not set.
+	OpenShiftOAuthIssuer string `json:"openShiftOAuthIssuer,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget options for the Dex pods.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// PodOverrides defines the scheduling and customization overrides for the Dex pods.
+	PodOverrides *ArgoCDPodOverrideSpec `json:"podOverrides,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for Dex.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Version is the Dex container image tag.
+	Version string `json:"version,omitempty"`
+}
+
+// ArgoCDDexOAuthSpec defines the desired state for the Dex OAuth configuration.
+type ArgoCDDexOAuthSpec struct {
+	// Enabled will toggle OAuth support for the Dex server.
+	Enabled bool `json:"enabled"`
+}
+
+// ArgoCDFilteredResource defines a group of resources to include in or exclude from reconciliation.
+type ArgoCDFilteredResource struct {
+	// APIGroups is the list of API groups to match, "*" matches all groups.
+	APIGroups []string `json:"apiGroups,omitempty"`
+
+	// Kinds is the list of kinds to match, "*" matches all kinds.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Clusters is the list of cluster URLs to match, "*" matches all clusters.
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// ArgoCDGrafanaSpec defines the desired state for the Grafana component.
+type ArgoCDGrafanaSpec struct {
+	// Enabled will toggle Grafana support globally for ArgoCD.
+	Enabled bool `json:"enabled"`
+
+	// Host is the hostname to use for Ingress/Route resources.
+	Host string `json:"host,omitempty"`
+
+	// Image is the Grafana container image.
+	Image string `json:"image,omitempty"`
+
+	// Ingress defines the desired state for an Ingress for the Grafana component.
+	Ingress ArgoCDIngressSpec `json:"ingress,omitempty"`
+
+	// PodOverrides defines the scheduling and customization overrides for the Grafana pods.
+	PodOverrides *ArgoCDPodOverrideSpec `json:"podOverrides,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for Grafana.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Route defines the desired state for an OpenShift Route for the Grafana component.
+	Route ArgoCDRouteSpec `json:"route,omitempty"`
+
+	// Size is the replica count for the Grafana Deployment.
+	Size *int32 `json:"size,omitempty"`
+
+	// Version is the Grafana container image tag.
+	Version string `json:"version,omitempty"`
+}
+
+// ArgoCDHASpec defines the desired state for High Availability support for Argo CD.
+type ArgoCDHASpec struct {
+	// ComponentReplicas is the replica count for the Argo CD server, repo server, Dex and ApplicationSet controller
+	// Deployments that do not set their own replica count, defaults to 2.
+	ComponentReplicas *int32 `json:"componentReplicas,omitempty"`
+
+	// Enabled will toggle HA support globally for Argo CD.
+	Enabled bool `json:"enabled"`
+
+	// PodOverrides defines the scheduling and customization overrides for the Redis HA pods.
+	PodOverrides *ArgoCDPodOverrideSpec `json:"podOverrides,omitempty"`
+
+	// RedisProxyImage is the Redis HAProxy container image.
+	RedisProxyImage string `json:"redisProxyImage,omitempty"`
+
+	// RedisProxyReplicas is the replica count for the Redis HAProxy Deployment.
+	RedisProxyReplicas *int32 `json:"redisProxyReplicas,omitempty"`
+
+	// RedisProxyVersion is the Redis HAProxy container image tag.
+	RedisProxyVersion string `json:"redisProxyVersion,omitempty"`
+
+	// Replicas is the replica count for the Redis HA StatefulSet, each replica runs a Redis server and a Sentinel.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for HA.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SentinelQuorum is the number of Sentinels that must agree that the Redis master is unreachable to start a
+	// failover, a majority of the replicas by default.
+	SentinelQuorum *int32 `json:"sentinelQuorum,omitempty"`
+
+	// SpreadAcrossZones spreads the Redis HA server and HAProxy pods evenly across the zones of the nodes.
+	SpreadAcrossZones bool `json:"spreadAcrossZones,omitempty"`
+
+	// VolumeClaimTemplate is the spec of the PersistentVolumeClaim created for the data of each Redis HA replica. The
+	// data is kept in an emptyDir volume when it is not set.
+	VolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"volumeClaimTemplate,omitempty"`
+}
+
+// ArgoCDImportSpec defines the desired state for the ArgoCD import/restore process.
+type ArgoCDImportSpec struct {
+	// Name of an ArgoCDExport from which to import data.
+	Name string `json:"name"`
+
+	// Namespace for the ArgoCDExport, defaults to the same namespace as the ArgoCD.
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// ArgoCDIngressSpec defines the desired state for the Ingress resources.
+type ArgoCDIngressSpec struct {
+	// Annotations is the map of annotations to apply to the Ingress.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Enabled will toggle the creation of the Ingress.
+	Enabled bool `json:"enabled"`
+
+	// Path used for the Ingress resource.
+	Path string `json:"path,omitempty"`
+
+	// TLS configuration. Currently the Ingress only supports a single TLS
+	// port, 443. If multiple members of this list specify different hosts, they
+	// will be multiplexed on the same port according to the hostname specified
+	// through the SNI TLS extension, if the ingress controller fulfilling the
+	// ingress supports SNI.
+	// +optional
+	TLS []extv1beta1.IngressTLS `json:"tls,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ArgoCDList contains a list of ArgoCD
+type ArgoCDList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ArgoCD `json:"items"`
+}
+
+// ArgoCDKnownTypeField defines the type of a field within a custom resource.
+type ArgoCDKnownTypeField struct {
+	// Field is the JSON path of the field, e.g. spec.template.
+	Field string `json:"field,omitempty"`
+
+	// Type is the known Kubernetes type of the field, e.g. core/v1/PodSpec.
+	Type string `json:"type,omitempty"`
+}
+
+// ArgoCDOIDCClaim defines a requested ID token claim.
+type ArgoCDOIDCClaim struct {
+	// Essential marks the claim as required.
+	Essential bool `json:"essential,omitempty"`
+
+	// Value is the requested value of the claim.
+	Value string `json:"value,omitempty"`
+
+	// Values is the list of requested values of the claim.
+	Values []string `json:"values,omitempty"`
+}
+
+// ArgoCDNetworkPolicySpec defines the NetworkPolicies generated for the Argo CD components.
+type ArgoCDNetworkPolicySpec struct {
+	// Enabled will toggle the creation of NetworkPolicies that only allow the traffic between the Argo CD components.
+	Enabled bool `json:"enabled"`
+
+	// ExtraPeers are the additional peers allowed to reach a component on any port, keyed by the component. The
+	// components are application-controller, dex-server, redis, redis-ha, repo-server and server.
+	ExtraPeers map[string][]networkingv1.NetworkPolicyPeer `json:"extraPeers,omitempty"`
+
+	// IngressControllerPeers are the peers allowed to reach the Argo CD server, such as the ingress controller pods.
+	// The Argo CD server can be reached from any source when no peers are given.
+	IngressControllerPeers []networkingv1.NetworkPolicyPeer `json:"ingressControllerPeers,omitempty"`
+
+	// MetricsPeers are the peers allowed to scrape the metrics ports, in addition to the Prometheus deployed for the
+	// ArgoCD.
+	MetricsPeers []networkingv1.NetworkPolicyPeer `json:"metricsPeers,omitempty"`
+}
+
+// ArgoCDOIDCConfig defines the OIDC configuration for Argo CD as an alternative to Dex.
+type ArgoCDOIDCConfig struct {
+	// Name is the display name for the OIDC provider.
+	Name string `json:"name,omitempty"`
+
+	// Issuer is the URL of the OIDC issuer.
+	Issuer string `json:"issuer,omitempty"`
+
+	// ClientID is the client ID registered with the OIDC provider.
+	ClientID string `json:"clientID,omitempty"`
+
+	// ClientSecret is the client secret, or a reference to a key in argocd-secret in the form $key.
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// CLIClientID is an optional client ID to use for the Argo CD CLI.
+	CLIClientID string `json:"cliClientID,omitempty"`
+
+	// RequestedScopes is the list of scopes to request, defaults to openid, profile and email.
+	RequestedScopes []string `json:"requestedScopes,omitempty"`
+
+	// RequestedIDTokenClaims is the map of ID token claims to request.
+	RequestedIDTokenClaims map[string]*ArgoCDOIDCClaim `json:"requestedIDTokenClaims,omitempty"`
+
+	// LogoutURL is the URL used to log out of the OIDC provider.
+	LogoutURL string `json:"logoutURL,omitempty"`
+
+	// RootCA is the PEM encoded certificate used to verify the OIDC provider.
+	RootCA string `json:"rootCA,omitempty"`
+}
+
+// ArgoCDPodDisruptionBudgetSpec defines the PodDisruptionBudget options for the pods of an Argo CD component. At most
+// one of MaxUnavailable and MinAvailable may be set, one pod may be unavailable when neither is set.
+type ArgoCDPodDisruptionBudgetSpec struct {
+	// MaxUnavailable is the number or percentage of the pods that may be unavailable after an eviction.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MinAvailable is the number or percentage of the pods that must still be available after an eviction.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// ArgoCDPodOverrideSpec defines the scheduling and customization overrides that are merged into the pod template
+// generated for an Argo CD component.
+type ArgoCDPodOverrideSpec struct {
+	// Affinity is the scheduling affinity for the pods, it replaces any affinity set by the operator.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Annotations are added to the pod template.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ContainerSecurityContext is the security context for every container and init container in the pods.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// Env is the list of environment variables to set on the containers, replacing variables with the same name.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Labels are added to the pod template, labels set by the operator take precedence.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// NodeSelector is the node selector for the pods.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass for the pods.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// SecurityContext is the pod level security context.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// Tolerations is the list of tolerations for the pods.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints describes how the pods are spread across topology domains.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// VolumeMounts is the list of volume mounts to add to the containers, replacing mounts with the same path.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// Volumes is the list of volumes to add to the pods, replacing volumes with the same name.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+}
+
+// ArgoCDPrometheusSpec defines the desired state for the Prometheus component.
+type ArgoCDPrometheusSpec struct {
+	// Enabled will toggle Prometheus support globally for ArgoCD.
+	Enabled bool `json:"enabled"`
+
+	// Host is the hostname to use for Ingress/Route resources.
+	Host string `json:"host,omitempty"`
+
+	// Ingress defines the desired state for an Ingress for the Prometheus component.
+	Ingress ArgoCDIngressSpec `json:"ingress,omitempty"`
+
+	// Route defines the desired state for an OpenShift Route for the Prometheus component.
+	Route ArgoCDRouteSpec `json:"route,omitempty"`
+
+	// Size is the replica count for the Prometheus StatefulSet.
+	Size *int32 `json:"size,omitempty"`
+}
+
+// ArgoCDRBACGroupBindingSpec binds an SSO group to an Argo CD role.
+type ArgoCDRBACGroupBindingSpec struct {
+	// Group is the name of the SSO group.
+	Group string `json:"group"`
+
+	// Role is the name of the role granted to the members of the group, one of the Roles or a built-in role, admin
+	// or readonly.
+	Role string `json:"role"`
+}
+
+// ArgoCDRBACGroupMappingSpec binds the cluster groups to an Argo CD role. On OpenShift, only the named or selected
+// Groups that exist and have members are bound.
+type ArgoCDRBACGroupMappingSpec struct {
+	// Groups are the names of the groups, e.g. cluster-admins. Without the OpenShift user API the groups are bound
+	// as they are named.
+	Groups []string `json:"groups,omitempty"`
+
+	// Role is the name of the role granted to the members of the groups, one of the Roles or a built-in role, admin
+	// or readonly.
+	Role string `json:"role"`
+
+	// Selector selects the OpenShift Groups by label.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ArgoCDRBACPermissionSpec defines a permission of an Argo CD role.
+type ArgoCDRBACPermissionSpec struct {
+	// Action is the action on the resource, e.g. get, create, sync or *.
+	Action string `json:"action"`
+
+	// Effect is either allow or deny. Defaults to allow.
+	// +kubebuilder:validation:Enum=allow;deny
+	Effect string `json:"effect,omitempty"`
+
+	// Object is the object of the resource the permission applies to, e.g. <project>/<application> for
+	// applications, or * for all objects.
+	Object string `json:"object"`
+
+	// Resource is the Argo CD resource type.
+	// +kubebuilder:validation:Enum=accounts;applications;certificates;clusters;gpgkeys;projects;repositories
+	Resource string `json:"resource"`
+}
+
+// ArgoCDRBACRoleSpec defines an Argo CD role and its permissions.
+type ArgoCDRBACRoleSpec struct {
+	// Name is the name of the role, the role is referenced as role:<name> in the policy.
+	Name string `json:"name"`
+
+	// Permissions are the permissions of the role.
+	Permissions []ArgoCDRBACPermissionSpec `json:"permissions,omitempty"`
+}
+
+// ArgoCDRBACSpec defines the desired state for the Argo CD RBAC configuration.
+type ArgoCDRBACSpec struct {
+	// DefaultPolicy is the name of the default role which Argo CD will falls back to, when
+	// authorizing API requests (optional). If omitted or empty, users may be still be able to login,
+	// but will see no apps, projects, etc...
+	DefaultPolicy *string `json:"defaultPolicy,omitempty"`
+
+	// GroupBindings binds SSO groups to Argo CD roles, rendered to the policy after the Roles.
+	GroupBindings []ArgoCDRBACGroupBindingSpec `json:"groupBindings,omitempty"`
+
+	// GroupMappings binds the cluster groups to Argo CD roles, rendered to the policy after the GroupBindings. The
+	// policy is updated when the mapped OpenShift Groups change.
+	GroupMappings []ArgoCDRBACGroupMappingSpec `json:"groupMappings,omitempty"`
+
+	// Policy is CSV containing user-defined RBAC policies and role definitions.
+	// Policy rules are in the form:
+	//   p, subject, resource, action, object, effect
+	// Role definitions and bindings are in the form:
+	//   g, subject, inherited-subject
+	// See https://github.com/argoproj/argo-cd/blob/master/docs/operator-manual/rbac.md for additional information.
+	Policy *string `json:"policy,omitempty"`
+
+	// PolicyConfigMapSelector selects the ConfigMaps in the namespace of the ArgoCD that hold policy fragments in
+	// their policy.csv key. The fragments are appended to the policy in the order of the ConfigMap names, a fragment
+	// that is not valid is left out.
+	PolicyConfigMapSelector *metav1.LabelSelector `json:"policyConfigMapSelector,omitempty"`
+
+	// Roles are the Argo CD roles, rendered to the policy after the Policy.
+	Roles []ArgoCDRBACRoleSpec `json:"roles,omitempty"`
+
+	// Scopes controls which OIDC scopes to examine during rbac enforcement (in addition to `sub` scope).
+	// If omitted, defaults to: '[groups]'.
+	Scopes *string `json:"scopes,omitempty"`
+}
+
+// ArgoCDRedisExternalSpec defines the connection to an external Redis.
+type ArgoCDRedisExternalSpec struct {
+	// Host is the host name or IP address of the Redis server, or of the Redis Sentinel when SentinelMaster is set.
+	Host string `json:"host"`
+
+	// PasswordSecretRef is the key of the Secret that holds the Redis password, Redis is used without a password
+	// when it is not set.
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// Port is the port of the Redis server, 6379 by default, or of the Redis Sentinel, 26379 by default.
+	Port int32 `json:"port,omitempty"`
+
+	// SentinelMaster is the name of the master group monitored by the Redis Sentinel at the host and port. The
+	// Argo CD components connect to the Redis server directly when it is not set.
+	SentinelMaster string `json:"sentinelMaster,omitempty"`
+
+	// TLSSecretRef is the Secret that holds the CA certificate in the ca.crt key to verify the Redis server
+	// certificate when TLS is enabled. The system CA certificates are used when it is not set.
+	TLSSecretRef *corev1.LocalObjectReference `json:"tlsSecretRef,omitempty"`
+}
+
+// ArgoCDRedisSpec defines the desired state for the Redis server component.
+type ArgoCDRedisSpec struct {
+	// DisableAuth disables the password authentication of Redis. Redis requires the password in the
+	// <argocd-name>-redis secret by default, the operator generates it when the secret is not present.
+	DisableAuth bool `json:"disableAuth,omitempty"`
+
+	// DisableTLSVerification disables the verification of the Redis certificate by the Argo CD components when TLS
+	// is enabled.
+	DisableTLSVerification bool `json:"disableTLSVerification,omitempty"`
+
+	// EnableTLS enables TLS for Redis, Sentinel and HAProxy with the certificate in the argocd-operator-redis-tls
+	// secret. The certificate is issued by cert-manager when an issuer is set, by the operator CA otherwise.
+	EnableTLS bool `json:"enableTLS,omitempty"`
+
+	// External configures the Argo CD components to use an external Redis. The operator does not deploy Redis when
+	// it is set, the HA options are ignored.
+	External *ArgoCDRedisExternalSpec `json:"external,omitempty"`
+
+	// Image is the Redis container image.
+	Image string `json:"image,omitempty"`
+
+	// PodOverrides defines the scheduling and customization overrides for the Redis pods.
+	PodOverrides *ArgoCDPodOverrideSpec `json:"podOverrides,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for Redis.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Version is the Redis container image tag.
+	Version string `json:"version,omitempty"`
+}
+
+// ArgoCDRepoSpec defines the desired state for the Argo CD repo server component.
+type ArgoCDRepoSpec struct {
+	// Autoscale defines the autoscale options for the Argo CD repo server component.
+	Autoscale ArgoCDAutoscaleSpec `json:"autoscale,omitempty"`
+
+	// MountSAToken describes whether you would like to have the Repo server mount the service account token
+	MountSAToken bool `json:"mountsatoken,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget options for the repo server pods.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// PodOverrides defines the scheduling and customization overrides for the repo server pods.
+	PodOverrides *ArgoCDPodOverrideSpec `json:"podOverrides,omitempty"`
+
+	// Replicas is the replica count for the Argo CD repo server Deployment, it is ignored when autoscaling is enabled.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for Redis.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ServiceAccount defines the ServiceAccount user that you would like the Repo server to use
+	ServiceAccount string `json:"serviceaccount,omitempty"`
+
+	// VerifyTLS defines whether repo server API should be accessed using strict TLS validation
+	VerifyTLS bool `json:"verifytls,omitempty"`
+
+	// AutoTLS specifies the method to use for automatic TLS configuration for the repo server
+	// The value specified here can currently be:
+	// - openshift - Use the OpenShift service CA to request TLS config
+	// - operator - Use the operator CA to issue the TLS config, the clients of the repo server verify it strictly
+	AutoTLS string `json:"autotls,omitempty"`
+}
+
+// ArgoCDRepositoryGitHubAppSpec defines the GitHub App used to access the repositories.
+type ArgoCDRepositoryGitHubAppSpec struct {
+	// EnterpriseBaseURL is the base URL of the GitHub Enterprise API, GitHub is used when empty.
+	EnterpriseBaseURL string `json:"enterpriseBaseUrl,omitempty"`
+
+	// ID is the ID of the GitHub App.
+	ID int64 `json:"id"`
+
+	// InstallationID is the ID of the installation of the GitHub App.
+	InstallationID int64 `json:"installationId"`
+
+	// PrivateKeySecret references the key of a Secret that holds the private key of the GitHub App.
+	PrivateKeySecret corev1.SecretKeySelector `json:"privateKeySecret"`
+}
+
+// ArgoCDRepositorySpec defines a Git or Helm repository, or a credential template for the repositories with a URL
+// prefix.
+type ArgoCDRepositorySpec struct {
+	// EnableLFS enables Git LFS support for the repository.
+	EnableLFS bool `json:"enableLfs,omitempty"`
+
+	// GitHubApp defines the GitHub App used to access the repository. Only supported for the repositories and
+	// credential templates rendered to Secrets.
+	GitHubApp *ArgoCDRepositoryGitHubAppSpec `json:"githubApp,omitempty"`
+
+	// Insecure disables TLS verification of the repository server.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// InsecureIgnoreHostKey disables SSH host key verification.
+	InsecureIgnoreHostKey bool `json:"insecureIgnoreHostKey,omitempty"`
+
+	// Name is the name of the repository, required for Helm repositories. Not supported for credential templates.
+	Name string `json:"name,omitempty"`
+
+	// PasswordSecret references the key of a Secret that holds the password.
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
+
+	// Project is the Argo CD project the repository is scoped to. Only supported for the repositories rendered to
+	// Secrets.
+	Project string `json:"project,omitempty"`
+
+	// SSHPrivateKeySecret references the key of a Secret that holds the SSH private key.
+	SSHPrivateKeySecret *corev1.SecretKeySelector `json:"sshPrivateKeySecret,omitempty"`
+
+	// TLSClientCertDataSecret references the key of a Secret that holds the TLS client certificate.
+	TLSClientCertDataSecret *corev1.SecretKeySelector `json:"tlsClientCertDataSecret,omitempty"`
+
+	// TLSClientCertKeySecret references the key of a Secret that holds the TLS client key.
+	TLSClientCertKeySecret *corev1.SecretKeySelector `json:"tlsClientCertKeySecret,omitempty"`
+
+	// Type is the type of the repository, either git (the default) or helm.
+	// +kubebuilder:validation:Enum=git;helm
+	Type string `json:"type,omitempty"`
+
+	// URL is the URL of the repository, or the URL prefix for credential templates.
+	URL string `json:"url"`
+
+	// UsernameSecret references the key of a Secret that holds the username.
+	UsernameSecret *corev1.SecretKeySelector `json:"usernameSecret,omitempty"`
+}
+
+// ArgoCDResourceCustomization defines the customized behavior for a resource group/kind.
+type ArgoCDResourceCustomization struct {
+	// HealthLua is a Lua script used to assess the health of the resource.
+	HealthLua string `json:"health.lua,omitempty"`
+
+	// Actions is the Lua based resource actions definition, as YAML.
+	Actions string `json:"actions,omitempty"`
+
+	// IgnoreDifferences are the diffing customizations for the resource, as YAML.
+	IgnoreDifferences string `json:"ignoreDifferences,omitempty"`
+
+	// KnownTypeFields describes the types of fields embedded in the resource, used for diffing.
+	KnownTypeFields []ArgoCDKnownTypeField `json:"knownTypeFields,omitempty"`
+}
+
+// ArgoCDRouteSpec defines the desired state for an OpenShift Route.
+type ArgoCDRouteSpec struct {
+	// Annotations is the map of annotations to use for the Route resource.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Enabled will toggle the creation of the OpenShift Route.
+	Enabled bool `json:"enabled"`
+
+	// Path the router watches for, to route traffic for to the service.
+	Path string `json:"path,omitempty"`
+
+	// TLS provides the ability to configure certificates and termination for the Route.
+	TLS *routev1.TLSConfig `json:"tls,omitempty"`
+
+	// WildcardPolicy if any for the route. Currently only 'Subdomain' or 'None' is allowed.
+	WildcardPolicy *routev1.WildcardPolicyType `json:"wildcardPolicy,omitempty"`
+}
+
+// ArgoCDServerGRPCSpec defines the desired state for the Argo CD Server GRPC options.
+type ArgoCDServerGRPCSpec struct {
+	// Host is the hostname to use for Ingress/Route resources.
+	Host string `json:"host,omitempty"`
+
+	// Ingress defines the desired state for the Argo CD Server GRPC Ingress.
+	Ingress ArgoCDIngressSpec `json:"ingress,omitempty"`
+}
+
+// ArgoCDServerSpec defines the options for the ArgoCD Server component.
+type ArgoCDServerSpec struct {
+	// Autoscale defines the autoscale options for the Argo CD Server component.
+	Autoscale ArgoCDAutoscaleSpec `json:"autoscale,omitempty"`
+
+	// GRPC defines the state for the Argo CD Server GRPC options.
+	GRPC ArgoCDServerGRPCSpec `json:"grpc,omitempty"`
+
+	// Host is the hostname to use for Ingress/Route resources.
+	Host string `json:"host,omitempty"`
+
+	// Ingress defines the desired state for an Ingress for the Argo CD Server component.
+	Ingress ArgoCDIngressSpec `json:"ingress,omitempty"`
+
+	// Insecure toggles the insecure flag.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget options for the Argo CD Server pods.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// PodOverrides defines the scheduling and customization overrides for the Argo CD Server pods.
+	PodOverrides *ArgoCDPodOverrideSpec `json:"podOverrides,omitempty"`
+
+	// Replicas is the replica count for the Argo CD Server Deployment, it is ignored when autoscaling is enabled.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources defines the Compute Resources required by the container for the Argo CD server component.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Route defines the desired state for an OpenShift Route for the Argo CD Server component.
+	Route ArgoCDRouteSpec `json:"route,omitempty"`
+
+	// SecretKey defines the options for the key used by the Argo CD Server to sign the session tokens.
+	SecretKey ArgoCDServerSecretKeySpec `json:"secretKey,omitempty"`
+
+	// Service defines the options for the Service backing the ArgoCD Server component.
+	Service ArgoCDServerServiceSpec `json:"service,omitempty"`
+}
+
+// ArgoCDServerSecretKeySpec defines the options for the key used by the Argo CD Server to sign the session tokens.
+type ArgoCDServerSecretKeySpec struct {
+	// RotationInterval is the interval at which the operator generates a new server secret key. The key is not rotated
+	// when it is not set.
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+}
+
+// ArgoCDServerServiceSpec defines the Service options for Argo CD Server component.
+type ArgoCDServerServiceSpec struct {
+	// Type is the ServiceType to use for the Service resource.
+	Type corev1.ServiceType `json:"type"`
+}
+
+// SSOProviderType string defines the type of SSO provider.
+type SSOProviderType string
+
+const (
+	// SSOProviderTypeKeycloak means keycloak will be Installed and Integrated with Argo CD. A new realm with name argocd
+	// will be created in this keycloak. This realm will have a client with name argocd that uses OpenShift v4 as Identity Provider.
+	SSOProviderTypeKeycloak SSOProviderType = "keycloak"
+)
+
+// ArgoCDSSOSpec defines SSO provider.
+type ArgoCDSSOSpec struct {
+	// Provider installs and configures the given SSO Provider with Argo CD.
+	Provider SSOProviderType `json:"provider,omitempty"`
+	// VerifyTLS set to false disables strict TLS validation.
+	VerifyTLS *bool `json:"verifyTLS,omitempty"`
+}
+
+// ArgoCDSpec defines the desired state of ArgoCD
+// +k8s:openapi-gen=true
+type ArgoCDSpec struct {
+
+	// AdminPassword defines the password options for the admin user.
+	AdminPassword *ArgoCDAdminPasswordSpec `json:"adminPassword,omitempty"`
+
+	// ArgoCDApplicationSet defines whether the Argo CD ApplicationSet controller should be installed.
+	ApplicationSet *ArgoCDApplicationSet `json:"applicationSet,omitempty"`
+
+	// ApplicationInstanceLabelKey is the key name where Argo CD injects the app name as a tracking label.
+	ApplicationInstanceLabelKey string `json:"applicationInstanceLabelKey,omitempty"`
+
+	// ConfigManagementPlugins is used to specify additional config management plugins.
+	ConfigManagementPlugins []ArgoCDConfigManagementPlugin `json:"configManagementPlugins,omitempty"`
+
+	// Controller defines the Application Controller options for ArgoCD.
+	Controller ArgoCDApplicationControllerSpec `json:"controller,omitempty"`
+
+	// Dex defines the Dex server options for ArgoCD.
+	Dex ArgoCDDexSpec `json:"dex,omitempty"`
+
+	// DisableAdmin will disable the admin user.
+	DisableAdmin bool `json:"disableAdmin,omitempty"`
+
+	// GATrackingID is the google analytics tracking ID to use.
+	GATrackingID string `json:"gaTrackingID,omitempty"`
+
+	// GAAnonymizeUsers toggles user IDs being hashed before sending to google analytics.
+	GAAnonymizeUsers bool `json:"gaAnonymizeUsers,omitempty"`
+
+	// Grafana defines the Grafana server options for ArgoCD.
+	Grafana ArgoCDGrafanaSpec `json:"grafana,omitempty"`
+
+	// HA options for High Availability support for Argo CD.
+	HA ArgoCDHASpec `json:"ha,omitempty"`
+
+	// HelpChatURL is the URL for getting chat help, this will typically be your Slack channel for support.
+	HelpChatURL string `json:"helpChatURL,omitempty"`
+
+	// HelpChatText is the text for getting chat help, defaults to "Chat now!"
+	HelpChatText string `json:"helpChatText,omitempty"`
+
+	// Image is the ArgoCD container image for all ArgoCD components.
+	Image string `json:"image,omitempty"`
+
+	// Import is the import/restore options for ArgoCD.
+	Import *ArgoCDImportSpec `json:"import,omitempty"`
+
+	// InitialRepositories to configure Argo CD with upon creation of the cluster.
+	InitialRepositories []ArgoCDRepositorySpec `json:"initialRepositories,omitempty"`
+
+	// InitialSSHKnownHosts defines the SSH known hosts data upon creation of the cluster for connecting Git repositories via SSH.
+	InitialSSHKnownHosts SSHHostsSpec `json:"initialSSHKnownHosts,omitempty"`
+
+	// KustomizeBuildOptions is used to specify build options/parameters to use with `kustomize build`.
+	KustomizeBuildOptions string `json:"kustomizeBuildOptions,omitempty"`
+
+	// NetworkPolicy defines the NetworkPolicies generated for the Argo CD components.
+	NetworkPolicy ArgoCDNetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// OIDCConfig is the OIDC configuration as an alternative to dex.
+	OIDCConfig *ArgoCDOIDCConfig `json:"oidcConfig,omitempty"`
+
+	// Prometheus defines the Prometheus server options for ArgoCD.
+	Prometheus ArgoCDPrometheusSpec `json:"prometheus,omitempty"`
+
+	// RBAC defines the RBAC configuration for Argo CD.
+	RBAC ArgoCDRBACSpec `json:"rbac,omitempty"`
+
+	// Redis defines the Redis server options for ArgoCD.
+	Redis ArgoCDRedisSpec `json:"redis,omitempty"`
+
+	// Repo defines the repo server options for Argo CD.
+	Repo ArgoCDRepoSpec `json:"repo,omitempty"`
+
+	// Repositories are the repositories rendered to Argo CD repository Secrets. The operator keeps the Secrets in
+	// sync with the Secrets they reference.
+	Repositories []ArgoCDRepositorySpec `json:"repositories,omitempty"`
+
+	// RepositoryCredentialTemplates are the credential templates rendered to Argo CD repo-creds Secrets, used for the
+	// repositories with a URL that starts with the URL of a template.
+	RepositoryCredentialTemplates []ArgoCDRepositorySpec `json:"repositoryCredentialTemplates,omitempty"`
+
+	// RepositoryCredentials are the Git pull credentials to configure Argo CD with upon creation of the cluster.
+	RepositoryCredentials []ArgoCDRepositorySpec `json:"repositoryCredentials,omitempty"`
+
+	// ResourceCustomizations customizes resource behavior. Keys are in the form: group/Kind.
+	ResourceCustomizations map[string]ArgoCDResourceCustomization `json:"resourceCustomizations,omitempty"`
+
+	// ResourceExclusions is used to completely ignore entire classes of resource group/kinds.
+	ResourceExclusions []ArgoCDFilteredResource `json:"resourceExclusions,omitempty"`
+
+	// ResourceInclusions is used to only include specific group/kinds in the
+	// reconciliation process.
+	ResourceInclusions []ArgoCDFilteredResource `json:"resourceInclusions,omitempty"`
+
+	// Server defines the options for the ArgoCD Server component.
+	Server ArgoCDServerSpec `json:"server,omitempty"`
+
+	// SSO defines the Single Sign-on configuration for Argo CD
+	SSO *ArgoCDSSOSpec `json:"sso,omitempty"`
+
+	// StatusBadgeEnabled toggles application status badge feature.
+	StatusBadgeEnabled bool `json:"statusBadgeEnabled,omitempty"`
+
+	// TLS defines the TLS options for ArgoCD.
+	TLS ArgoCDTLSSpec `json:"tls,omitempty"`
+
+	// Users defines the local user accounts managed by the operator.
+	Users []ArgoCDUserSpec `json:"users,omitempty"`
+
+	// UsersAnonymousEnabled toggles anonymous user access.
+	// The anonymous users get default role permissions specified argocd-rbac-cm.
+	UsersAnonymousEnabled bool `json:"usersAnonymousEnabled,omitempty"`
+
+	// Version is the tag to use with the ArgoCD container image for all ArgoCD components.
+	Version string `json:"version,omitempty"`
+}
+
+// ArgoCDStatus defines the observed state of ArgoCD
+// +k8s:openapi-gen=true
+type ArgoCDStatus struct {
+	// AdminPasswordRotationTime is the last time the operator generated a new admin password, or copied it from the
+	// admin password Secret.
+	AdminPasswordRotationTime *metav1.Time `json:"adminPasswordRotationTime,omitempty"`
+
+	// ApplicationController is a simple, high-level summary of where the Argo CD application controller component is in its lifecycle.
+	// There are five possible ApplicationController values:
+	// Pending: The Argo CD application controller component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
+	// Running: All of the required Pods for the Argo CD application controller component are in a Ready state.
+	// Failed: At least one of the  Argo CD application controller component Pods had a failure.
+	// Unknown: For some reason the state of the Argo CD application controller component could not be obtained.
+	ApplicationController string `json:"applicationController,omitempty"`
+
+	// ApplicationControllerShards is the distribution of the clusters across the Application Controller shards.
+	// It is only reported when sharding is enabled.
+	ApplicationControllerShards []ArgoCDApplicationControllerShardStatus `json:"applicationControllerShards,omitempty"`
+
+	// ApplicationControllerUnassignedClusters are the names of the clusters with a shard that is not valid or out of
+	// range, they are not managed by any Application Controller replica. It is only reported when sharding is enabled.
+	ApplicationControllerUnassignedClusters []string `json:"applicationControllerUnassignedClusters,omitempty"`
+
+	// Certificates is the expiry of the certificates in the TLS Secrets used by Argo CD.
+	Certificates []ArgoCDCertificateStatus `json:"certificates,omitempty"`
+
+	// Conditions describe the current state of the ArgoCD, see the ArgoCDCondition* constants for the types.
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []ArgoCDCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Dex is a simple, high-level summary of where the Argo CD Dex component is in its lifecycle.
+	// There are five possible dex values:
+	// Pending: The Argo CD Dex component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
+	// Running: All of the required Pods for the Argo CD Dex component are in a Ready state.
+	// Failed: At least one of the  Argo CD Dex component Pods had a failure.
+	// Unknown: For some reason the state of the Argo CD Dex component could not be obtained.
+	Dex string `json:"dex,omitempty"`
+
+	// ObservedGeneration is the most recent .metadata.generation of the ArgoCD observed by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase is a simple, high-level summary of where the ArgoCD is in its lifecycle.
+	// There are five possible phase values:
+	// Pending: The ArgoCD has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
+	// Available: All of the resources for the ArgoCD are ready.
+	// Failed: At least one resource has experienced a failure.
+	// Unknown: For some reason the state of the ArgoCD phase could not be obtained.
+	Phase string `json:"phase,omitempty"`
+
+	// Redis is a simple, high-level summary of where the Argo CD Redis component is in its lifecycle.
+	// There are five possible redis values:
+	// Pending: The Argo CD Redis component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
+	// Running: All of the required Pods for the Argo CD Redis component are in a Ready state.
+	// Failed: At least one of the  Argo CD Redis component Pods had a failure.
+	// Unknown: For some reason the state of the Argo CD Redis component could not be obtained.
+	Redis string `json:"redis,omitempty"`
+
+	// Repo is a simple, high-level summary of where the Argo CD Repo component is in its lifecycle.
+	// There are five possible repo values:
+	// Pending: The Argo CD Repo component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
+	// Running: All of the required Pods for the Argo CD Repo component are in a Ready state.
+	// Failed: At least one of the  Argo CD Repo component Pods had a failure.
+	// Unknown: For some reason the state of the Argo CD Repo component could not be obtained.
+	Repo string `json:"repo,omitempty"`
+
+	// Server is a simple, high-level summary of where the Argo CD server component is in its lifecycle.
+	// There are five possible server values:
+	// Pending: The Argo CD server component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
+	// Running: All of the required Pods for the Argo CD server component are in a Ready state.
+	// Failed: At least one of the  Argo CD server component Pods had a failure.
+	// Unknown: For some reason the state of the Argo CD server component could not be obtained.
+	Server string `json:"server,omitempty"`
+
+	// ServerSecretKeyRotationTime is the last time the operator generated a new server secret key.
+	ServerSecretKeyRotationTime *metav1.Time `json:"serverSecretKeyRotationTime,omitempty"`
+
+	// RedisTLSChecksum contains the SHA256 checksum of the latest known state of tls.crt and tls.key in the argocd-operator-redis-tls secret.
+	RedisTLSChecksum string `json:"redisTLSChecksum,omitempty"`
+
+	// RepoTLSChecksum contains the SHA256 checksum of the latest known state of tls.crt and tls.key in the argocd-repo-server-tls secret.
+	RepoTLSChecksum string `json:"repoTLSChecksum,omitempty"`
+}
+
+// ArgoCDTLSSpec defines the TLS options for ArgCD.
+type ArgoCDTLSSpec struct {
+	// CA defines the CA options.
+	CA ArgoCDCASpec `json:"ca,omitempty"`
+
+	// Duration is the validity of the certificates issued by the operator CA. Defaults to 8760h (365 days).
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// InitialCerts defines custom TLS certificates upon creation of the cluster for connecting Git repositories via HTTPS.
+	InitialCerts map[string]string `json:"initialCerts,omitempty"`
+
+	// Issuer references the cert-manager Issuer or ClusterIssuer that issues the certificates for Argo CD. When set,
+	// cert-manager Certificates are used instead of the certificates signed by the operator CA.
+	Issuer *ArgoCDTLSIssuerSpec `json:"issuer,omitempty"`
+
+	// PrivateKey defines the private keys generated by the operator for the CA and the certificates it issues.
+	PrivateKey *ArgoCDTLSPrivateKeySpec `json:"privateKey,omitempty"`
+
+	// RenewBefore is how long before expiry the CA and the certificates issued by the operator are renewed.
+	// Defaults to 720h (30 days).
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// Subject defines the subject of the CA and the certificates issued by the operator.
+	Subject *ArgoCDTLSSubjectSpec `json:"subject,omitempty"`
+}
+
+// ArgoCDTLSIssuerSpec defines the cert-manager issuer for the Argo CD certificates.
+type ArgoCDTLSIssuerSpec struct {
+	// Group is the API group of the issuer. Defaults to cert-manager.io.
+	Group string `json:"group,omitempty"`
+
+	// Kind is the kind of the issuer, either Issuer or ClusterIssuer. Defaults to Issuer.
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the issuer.
+	Name string `json:"name"`
+}
+
+// ArgoCDTLSPrivateKeySpec defines the private keys generated by the operator.
+type ArgoCDTLSPrivateKeySpec struct {
+	// Algorithm is the private key algorithm, one of RSA, ECDSA or Ed25519. Defaults to RSA.
+	// +kubebuilder:validation:Enum=RSA;ECDSA;Ed25519
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Size is the size of the private key in bits. RSA keys may be 2048, 3072 or 4096 bits and default to 2048, ECDSA
+	// keys may be 256, 384 or 521 bits and default to 256. The size is ignored for Ed25519 keys.
+	Size int `json:"size,omitempty"`
+}
+
+// ArgoCDTLSSubjectSpec defines the subject of the certificates generated by the operator.
+type ArgoCDTLSSubjectSpec struct {
+	// Organizations are the organizations of the certificates. The certificates issued by the operator CA default to
+	// the namespace of the ArgoCD.
+	Organizations []string `json:"organizations,omitempty"`
+}
+
+// ArgoCDUserSpec defines a local user account for Argo CD.
+type ArgoCDUserSpec struct {
+	// Capabilities are the capabilities of the account, login and/or apiKey. Defaults to apiKey.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Enabled toggles the account. Defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// GeneratePassword will generate a password for the account when the user Secret does not hold one.
+	GeneratePassword bool `json:"generatePassword,omitempty"`
+
+	// Name is the name of the account.
+	Name string `json:"name"`
+
+	// Token defines the API token generated for the account. No token is generated when not set.
+	Token *ArgoCDUserTokenSpec `json:"token,omitempty"`
+}
+
+// ArgoCDUserTokenSpec defines the API token generated for a local user account.
+type ArgoCDUserTokenSpec struct {
+	// ExpiresIn is the validity of the token. The token does not expire when not set.
+	ExpiresIn *metav1.Duration `json:"expiresIn,omitempty"`
+
+	// RenewBefore is how long before expiry the token is rotated. Defaults to a third of the validity.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+type SSHHostsSpec struct {
+	// ExcludeDefaultHosts describes whether you would like to include the default
+	// list of SSH Known Hosts provided by ArgoCD.
+	ExcludeDefaultHosts bool `json:"excludedefaulthosts,omitempty"`
+
+	// Keys describes a custom set of SSH Known Hosts that you would like to
+	// have included in your ArgoCD server.
+	Keys string `json:"keys,omitempty"`
+}
+
+// Hub marks this type as the conversion hub.
+func (*ArgoCD) Hub() {}
+
+// IsDeletionFinalizerPresent checks if the instance has deletion finalizer
+func (argocd *ArgoCD) IsDeletionFinalizerPresent() bool {
+	for _, finalizer := range argocd.GetFinalizers() {
+		if finalizer == common.ArgoCDDeletionFinalizer {
+			return true
+		}
+	}
+	return false
+}
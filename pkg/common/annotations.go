@@ -8,4 +8,12 @@ const (
 	// AnnotationNamespace is the annotation on child resources that specifies which ArgoCD instance
 	// namespace a specific object is associated with
 	AnnotationNamespace = "argocds.argoproj.io/namespace"
+
+	// AnnotationResetAdminPassword is the annotation on an ArgoCD that requests a new admin password, the operator
+	// removes it once the password is reset
+	AnnotationResetAdminPassword = "argocds.argoproj.io/reset-admin-password"
+
+	// AnnotationRotateServerSecretKey is the annotation on an ArgoCD that requests a new server secret key, the
+	// operator removes it once the key is rotated
+	AnnotationRotateServerSecretKey = "argocds.argoproj.io/rotate-server-secret-key"
 )
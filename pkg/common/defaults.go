@@ -14,6 +14,8 @@
 
 package common
 
+import "time"
+
 const (
 	// ArgoCDApplicationControllerComponent is the name of the application controller  control plane component
 	ArgoCDApplicationControllerComponent = "argocd-application-controller"
@@ -36,6 +38,9 @@ const (
 	// ArgoCDDefaultAdminPasswordNumSymbols is the number of symbols to use for the generated default admin password.
 	ArgoCDDefaultAdminPasswordNumSymbols = 0
 
+	// ArgoCDDefaultApplicationControllerMetricsPort is the default listen port for the Application Controller metrics.
+	ArgoCDDefaultApplicationControllerMetricsPort = 8082
+
 	// ArgoCDDefaultApplicationSetImage is the Argo CD Application Set container image to use when not specified.
 	ArgoCDDefaultApplicationSetImage = "quay.io/argocdapplicationset/argocd-applicationset"
 
@@ -51,6 +56,16 @@ const (
 	// ArgoCDDefaultArgoVersion is the Argo CD container image digest to use when version not specified.
 	ArgoCDDefaultArgoVersion = "sha256:8d1d58ef963f615da97e0b2c54dbe243801d5e7198b98393ab36b7a5768f72a4" // v2.0.0
 
+	// ArgoCDDefaultAutoscaleMaxReplicas is the default maximum replica count for autoscaled components.
+	ArgoCDDefaultAutoscaleMaxReplicas = int32(3)
+
+	// ArgoCDDefaultAutoscaleMinReplicas is the default minimum replica count for autoscaled components.
+	ArgoCDDefaultAutoscaleMinReplicas = int32(1)
+
+	// ArgoCDDefaultAutoscaleTargetCPUUtilization is the default average CPU utilization percentage for autoscaled
+	// components.
+	ArgoCDDefaultAutoscaleTargetCPUUtilization = int32(50)
+
 	// ArgoCDDefaultBackupKeyLength is the length of the generated default backup key.
 	ArgoCDDefaultBackupKeyLength = 32
 
@@ -60,9 +75,26 @@ const (
 	// ArgoCDDefaultBackupKeyNumSymbols is the number of symbols to use for the generated default backup key.
 	ArgoCDDefaultBackupKeyNumSymbols = 5
 
+	// ArgoCDDefaultCertificateDuration is the validity of the CA and the certificates issued by the operator when not
+	// specified.
+	ArgoCDDefaultCertificateDuration = ArgoCDDuration365Days
+
+	// ArgoCDDefaultCertificateIssuerGroup is the API group of the cert-manager issuer when not specified.
+	ArgoCDDefaultCertificateIssuerGroup = "cert-manager.io"
+
+	// ArgoCDDefaultCertificateIssuerKind is the kind of the cert-manager issuer when not specified.
+	ArgoCDDefaultCertificateIssuerKind = "Issuer"
+
+	// ArgoCDDefaultCertificateRenewBefore is how long before expiry the operator-issued certificates are renewed when
+	// not specified.
+	ArgoCDDefaultCertificateRenewBefore = time.Hour * 24 * 30
+
 	// ArgoCDDefaultConfigManagementPlugins is the default configuration value for the config management plugins.
 	ArgoCDDefaultConfigManagementPlugins = ""
 
+	// ArgoCDDefaultControllerReplicas is the default Application Controller replica count.
+	ArgoCDDefaultControllerReplicas = int32(1)
+
 	// ArgoCDDefaultControllerResourceLimitCPU is the default CPU limit when not specified for the Argo CD application
 	// controller contianer.
 	ArgoCDDefaultControllerResourceLimitCPU = "1000m"
@@ -88,6 +120,10 @@ const (
 	// ArgoCDDefaultDexOAuthRedirectPath is the default path to use for the OAuth Redirect URI.
 	ArgoCDDefaultDexOAuthRedirectPath = "/api/dex/callback"
 
+	// ArgoCDDefaultDexOpenShiftIssuer is the issuer for the OpenShift Dex connector when the API server URL of the
+	// cluster is unknown.
+	ArgoCDDefaultDexOpenShiftIssuer = "https://kubernetes.default.svc"
+
 	// ArgoCDDefaultDexGRPCPort is the default GRPC listen port for Dex.
 	ArgoCDDefaultDexGRPCPort = 5557
 
@@ -100,6 +136,9 @@ const (
 	// ArgoCDDefaultDexVersion is the Dex container image tag to use when not specified.
 	ArgoCDDefaultDexVersion = "sha256:77bfea96e8d8f3e4197b9f6020c8f5dedbb701245c19afd69a15747ae4bf2804" // v2.28.0
 
+	// ArgoCDDefaultECDSAKeySize is the default ECDSA key size, the size of the P-256 curve, when not specified.
+	ArgoCDDefaultECDSAKeySize = 256
+
 	// ArgoCDDefaultExportJobImage is the export job container image to use when not specified.
 	ArgoCDDefaultExportJobImage = "quay.io/jmckind/argocd-operator-util"
 
@@ -148,6 +187,9 @@ const (
 	// ArgoCDDefaultGrafanaVersion is the Grafana container image tag to use when not specified.
 	ArgoCDDefaultGrafanaVersion = "sha256:afef23a1b4cf159ec3180aac3ad693c10e560657313bfe3ec81f344ace6d2f05" // 6.7.2
 
+	// ArgoCDDefaultHAComponentReplicas is the default replica count for the stateless Argo CD components in HA mode.
+	ArgoCDDefaultHAComponentReplicas = int32(2)
+
 	// ArgoCDDefaultHelpChatURL is the default help chat URL.
 	ArgoCDDefaultHelpChatURL = "https://mycorp.slack.com/argo-cd"
 
@@ -196,6 +238,19 @@ const (
 	// ArgoCDDefaultRedisImage is the Redis container image to use when not specified.
 	ArgoCDDefaultRedisImage = "redis"
 
+	// ArgoCDDefaultRedisPasswordLength is the length of the generated Redis password.
+	ArgoCDDefaultRedisPasswordLength = 32
+
+	// ArgoCDDefaultRedisPasswordNumDigits is the number of digits to use for the generated Redis password.
+	ArgoCDDefaultRedisPasswordNumDigits = 5
+
+	// ArgoCDDefaultRedisPasswordNumSymbols is the number of symbols to use for the generated Redis password, none
+	// as the password is substituted in the Redis configuration by the Redis HA init scripts.
+	ArgoCDDefaultRedisPasswordNumSymbols = 0
+
+	// ArgoCDDefaultRedisPingTimeout is the timeout of the connectivity check for an external Redis.
+	ArgoCDDefaultRedisPingTimeout = 5 * time.Second
+
 	// ArgoCDDefaultRedisPort is the default listen port for Redis.
 	ArgoCDDefaultRedisPort = 6379
 
@@ -205,6 +260,9 @@ const (
 	//ArgoCDDefaultRedisSuffix is the default suffix to use for Redis resources.
 	ArgoCDDefaultRedisSuffix = "redis"
 
+	// ArgoCDDefaultRedisTLSPath is the path where the Redis TLS secret is mounted in the Redis and Argo CD containers.
+	ArgoCDDefaultRedisTLSPath = "/app/config/redis/tls"
+
 	// ArgoCDDefaultRedisVersion is the Redis container image tag to use when not specified.
 	ArgoCDDefaultRedisVersion = "sha256:4be7fdb131e76a6c6231e820c60b8b12938cf1ff3d437da4871b9b2440f4e385" // 5.0.3
 
@@ -235,6 +293,12 @@ const (
 	// ArgoCDDefaultRSAKeySize is the default RSA key size when not specified.
 	ArgoCDDefaultRSAKeySize = 2048
 
+	// ArgoCDDefaultTLSKeyAlgorithm is the algorithm of the private keys generated by the operator when not specified.
+	ArgoCDDefaultTLSKeyAlgorithm = ArgoCDTLSKeyAlgorithmRSA
+
+	// ArgoCDDefaultServerMetricsPort is the default listen port for the Argo CD server metrics.
+	ArgoCDDefaultServerMetricsPort = 8083
+
 	// ArgoCDDefaultServerOperationProcessors is the number of ArgoCD Server Operation Processors to use when not specified.
 	ArgoCDDefaultServerOperationProcessors = int32(10)
 
@@ -253,6 +317,9 @@ const (
 	// ArgoCDDefaultServerResourceRequestMemory is the default memory requested when not specified for the Argo CD server contianer.
 	ArgoCDDefaultServerResourceRequestMemory = "64Mi"
 
+	// ArgoCDDefaultServerPort is the default listen port for the Argo CD server HTTP and gRPC API.
+	ArgoCDDefaultServerPort = 8080
+
 	// ArgoCDDefaultServerSessionKeyLength is the length of the generated default server signature key.
 	ArgoCDDefaultServerSessionKeyLength = 20
 
@@ -20,6 +20,22 @@ import (
 )
 
 const (
+	// ArgoCDKeyAccounts is the configuration key prefix for the local user accounts.
+	ArgoCDKeyAccounts = "accounts"
+
+	// ArgoCDKeyAccountEnabled is the configuration key suffix for the enabled setting of a local user account.
+	ArgoCDKeyAccountEnabled = "enabled"
+
+	// ArgoCDKeyAccountPassword is the Argo CD Secret key suffix for the password hash of a local user account.
+	ArgoCDKeyAccountPassword = "password"
+
+	// ArgoCDKeyAccountPasswordMTime is the Argo CD Secret key suffix for the password modification time of a local
+	// user account.
+	ArgoCDKeyAccountPasswordMTime = "passwordMtime"
+
+	// ArgoCDKeyAccountTokens is the Argo CD Secret key suffix for the API tokens of a local user account.
+	ArgoCDKeyAccountTokens = "tokens"
+
 	// ArgoCDKeyAdminEnabled is the configuration key for the admin enabled setting..
 	ArgoCDKeyAdminEnabled = "admin.enabled"
 
@@ -35,6 +51,9 @@ const (
 	// ArgoCDKeyBackupKey is the "backup key" key for ConfigMaps.
 	ArgoCDKeyBackupKey = "backup.key"
 
+	// ArgoCDKeyClusterShard is the cluster Secret key for the application controller shard that manages the cluster.
+	ArgoCDKeyClusterShard = "shard"
+
 	// ArgoCDKeyConfigManagementPlugins is the configuration key for config management plugins.
 	ArgoCDKeyConfigManagementPlugins = "configManagementPlugins"
 
@@ -47,6 +66,10 @@ const (
 	// ArgoCDKeyDexConfig is the key for dex configuration.
 	ArgoCDKeyDexConfig = "dex.config"
 
+	// ArgoCDKeyDexConnectorSecretPrefix is the prefix of the keys in the argocd-secret Secret that hold the secrets
+	// referenced by the typed Dex connectors.
+	ArgoCDKeyDexConnectorSecretPrefix = "dex.connectors."
+
 	// ArgoCDKeyFailureDomainZone is the failure-domain zone key for labels.
 	ArgoCDKeyFailureDomainZone = "failure-domain.beta.kubernetes.io/zone"
 
@@ -74,6 +97,9 @@ const (
 	// ArgoCDKeyHostname is the resource hostname key for labels.
 	ArgoCDKeyHostname = "kubernetes.io/hostname"
 
+	// ArgoCDKeyInjectCABundle is the annotation key that requests the OpenShift service CA bundle for a ConfigMap.
+	ArgoCDKeyInjectCABundle = "service.beta.openshift.io/inject-cabundle"
+
 	// ArgoCDKeyIngressBackendProtocol is the backend-protocol key for labels.
 	ArgoCDKeyIngressBackendProtocol = "nginx.ingress.kubernetes.io/backend-protocol"
 
@@ -119,6 +145,13 @@ const (
 	// ArgoCDKeyRBACScopes is the configuration key for the Argo CD RBAC scopes.
 	ArgoCDKeyRBACScopes = "scopes"
 
+	// ArgoCDKeyRedisAuth is the key for the Redis password in the Redis auth Secret.
+	ArgoCDKeyRedisAuth = "auth"
+
+	// ArgoCDKeyRedisHAConfigChecksum is the annotation key for the checksum of the Redis HA configuration on the
+	// Redis HA and HAProxy pods.
+	ArgoCDKeyRedisHAConfigChecksum = "checksum/init-config"
+
 	// ArgoCDKeyRelease is the prometheus release key for labels.
 	ArgoCDKeyRelease = "release"
 
@@ -143,6 +176,9 @@ const (
 	// ArgoCDKeyServerURL is the key for server url.
 	ArgoCDKeyServerURL = "url"
 
+	// ArgoCDKeyServiceCABundle is the key of the OpenShift service CA bundle in an injected ConfigMap.
+	ArgoCDKeyServiceCABundle = "service-ca.crt"
+
 	// ArgoCDKeySSHKnownHosts is the resource ssh_known_hosts key for labels.
 	ArgoCDKeySSHKnownHosts = "ssh_known_hosts"
 
@@ -161,6 +197,12 @@ const (
 	// ArgoCDKeyTolerateUnreadyEndpounts is the resource tolerate unready endpoints key for labels.
 	ArgoCDKeyTolerateUnreadyEndpounts = "service.alpha.kubernetes.io/tolerate-unready-endpoints"
 
+	// ArgoCDKeyUserPassword is the user Secret key for the password of a local user account.
+	ArgoCDKeyUserPassword = "password"
+
+	// ArgoCDKeyUserToken is the user Secret key for the API token of a local user account.
+	ArgoCDKeyUserToken = "token"
+
 	// ArgoCDKeyUsersAnonymousEnabled is the configuration key for anonymous user access.
 	ArgoCDKeyUsersAnonymousEnabled = "users.anonymous.enabled"
 
@@ -168,6 +210,10 @@ const (
 	// for the ApplicationSet controller
 	ArgoCDApplicationSetEnvName = "ARGOCD_APPLICATIONSET_IMAGE"
 
+	// ArgoCDControllerReplicasEnvName is the environment variable used to tell the application controller
+	// the number of replicas the clusters are sharded across.
+	ArgoCDControllerReplicasEnvName = "ARGOCD_CONTROLLER_REPLICAS"
+
 	// ArgoCDDexImageEnvName is the environment variable used to get the image
 	// to used for the Dex container.
 	ArgoCDDexImageEnvName = "ARGOCD_DEX_IMAGE"
@@ -184,10 +230,18 @@ const (
 	// to used for the the Redis container in HA mode.
 	ArgoCDRedisHAImageEnvName = "ARGOCD_REDIS_HA_IMAGE"
 
+	// ArgoCDRedisHAAuthEnvName is the environment variable used by the Redis HA init scripts
+	// to set the Redis password in the configuration.
+	ArgoCDRedisHAAuthEnvName = "AUTH"
+
 	// ArgoCDRedisImageEnvName is the environment variable used to get the image
 	// to used for the Redis container.
 	ArgoCDRedisImageEnvName = "ARGOCD_REDIS_IMAGE"
 
+	// ArgoCDRedisPasswordEnvName is the environment variable used to tell Redis and the Argo CD
+	// components the Redis password.
+	ArgoCDRedisPasswordEnvName = "REDIS_PASSWORD"
+
 	// ArgoCDGrafanaImageEnvName is the environment variable used to get the image
 	// to used for the Grafana container.
 	ArgoCDGrafanaImageEnvName = "ARGOCD_GRAFANA_IMAGE"
@@ -198,6 +252,9 @@ const (
 	// ArgoCDDefaultServer is the default server address
 	ArgoCDDefaultServer = "https://kubernetes.default.svc"
 
+	// ArgoCDInClusterName is the name Argo CD gives the cluster it runs in when it has no cluster Secret.
+	ArgoCDInClusterName = "in-cluster"
+
 	// ArgoCDSecretTypeLabel is needed for cluster secrets
 	ArgoCDSecretTypeLabel = "argocd.argoproj.io/secret-type"
 )
@@ -23,9 +23,36 @@ const (
 	// ArgoCDCASuffix is the name suffix for ArgoCD CA resources.
 	ArgoCDCASuffix = "ca"
 
+	// ArgoCDCertManagerGroup is the API group of the cert-manager resources.
+	ArgoCDCertManagerGroup = "cert-manager.io"
+
+	// ArgoCDCertManagerVersion is the API version of the cert-manager resources.
+	ArgoCDCertManagerVersion = "v1"
+
 	// ArgoCDConfigMapName is the upstream hard-coded ArgoCD ConfigMap name.
 	ArgoCDConfigMapName = "argocd-cm"
 
+	// ArgoCDConversionWebhookPath is the path for the ArgoCD conversion webhook served by the operator.
+	ArgoCDConversionWebhookPath = "/convert"
+
+	// ArgoCDCRDName is the name of the ArgoCD CustomResourceDefinition.
+	ArgoCDCRDName = "argocds.argoproj.io"
+
+	// ArgoCDDexOpenShiftConnectorID is the ID of the Dex connector for the OpenShift OAuth server.
+	ArgoCDDexOpenShiftConnectorID = "openshift"
+
+	// ArgoCDDexOpenShiftCAServiceAccount is the value for the CA bundle mounted with the service account token.
+	ArgoCDDexOpenShiftCAServiceAccount = "ServiceAccount"
+
+	// ArgoCDDexOpenShiftCAServiceCA is the value for the OpenShift service CA bundle.
+	ArgoCDDexOpenShiftCAServiceCA = "ServiceCA"
+
+	// ArgoCDDexServiceCAConfigMapSuffix is the name suffix for the ConfigMap holding the OpenShift service CA bundle.
+	ArgoCDDexServiceCAConfigMapSuffix = "dex-service-ca"
+
+	// ArgoCDDexServiceCAPath is the path where the OpenShift service CA bundle is mounted in the Dex container.
+	ArgoCDDexServiceCAPath = "/app/config/dex/service-ca"
+
 	// ArgoCDGPGKeysConfigMapName is the upstream hard-coded ArgoCD gpg-keys ConfigMap name.
 	ArgoCDGPGKeysConfigMapName = "argocd-gpg-keys-cm"
 
@@ -47,33 +74,113 @@ const (
 	// ArgoCDExportStorageBackendLocal is the value for the local storage backend.
 	ArgoCDExportStorageBackendLocal = "local"
 
+	// ArgoCDExportValidatingWebhookPath is the path for the ArgoCDExport validating webhook served by the operator.
+	ArgoCDExportValidatingWebhookPath = "/validate-argoproj-io-v1alpha1-argocdexport"
+
 	// ArgoCDGrafanaConfigMapSuffix is the default suffix for the Grafana configuration ConfigMap.
 	ArgoCDGrafanaConfigMapSuffix = "grafana-config"
 
 	// ArgoCDGrafanaDashboardConfigMapSuffix is the default suffix for the Grafana dashboards ConfigMap.
 	ArgoCDGrafanaDashboardConfigMapSuffix = "grafana-dashboards"
 
+	// ArgoCDFieldManager is the field manager used by the operator when applying resources with server-side apply.
+	ArgoCDFieldManager = "argocd-operator"
+
 	// ArgoCDKnownHostsConfigMapName is the upstream hard-coded SSH known hosts data ConfigMap name.
 	ArgoCDKnownHostsConfigMapName = "argocd-ssh-known-hosts-cm"
 
+	// ArgoCDOpenShiftConfigGroup is the API group of the OpenShift cluster configuration resources.
+	ArgoCDOpenShiftConfigGroup = "config.openshift.io"
+
+	// ArgoCDOpenShiftConfigVersion is the API version of the OpenShift cluster configuration resources.
+	ArgoCDOpenShiftConfigVersion = "v1"
+
+	// ArgoCDOpenShiftInfrastructureName is the name of the OpenShift Infrastructure resource of the cluster.
+	ArgoCDOpenShiftInfrastructureName = "cluster"
+
 	// ArgoCDRedisHAConfigMapName is the upstream ArgoCD Redis HA ConfigMap name.
 	ArgoCDRedisHAConfigMapName = "argocd-redis-ha-configmap"
 
 	// ArgoCDRedisProbesConfigMapName is the upstream ArgoCD Redis Probes ConfigMap name.
 	ArgoCDRedisProbesConfigMapName = "argocd-redis-ha-probes"
 
+	// ArgoCDRedisServerTLSSecretName is the name of the TLS secret for the Redis server.
+	ArgoCDRedisServerTLSSecretName = "argocd-operator-redis-tls"
+
 	// ArgoCDRBACConfigMapName is the upstream hard-coded RBAC ConfigMap name.
 	ArgoCDRBACConfigMapName = "argocd-rbac-cm"
 
+	// ArgoCDRBACEffectAllow is the effect of the RBAC permissions that allow an action.
+	ArgoCDRBACEffectAllow = "allow"
+
+	// ArgoCDRBACEffectDeny is the effect of the RBAC permissions that deny an action.
+	ArgoCDRBACEffectDeny = "deny"
+
+	// ArgoCDRBACRoleAdmin is the name of the built-in Argo CD admin role.
+	ArgoCDRBACRoleAdmin = "admin"
+
+	// ArgoCDRBACRolePrefix is the prefix of the role subjects in the Argo CD RBAC policy.
+	ArgoCDRBACRolePrefix = "role:"
+
+	// ArgoCDRBACRoleReadonly is the name of the built-in Argo CD read-only role.
+	ArgoCDRBACRoleReadonly = "readonly"
+
+	// ArgoCDRepositoryTypeGit is the type of Git repositories.
+	ArgoCDRepositoryTypeGit = "git"
+
+	// ArgoCDRepositoryTypeHelm is the type of Helm repositories.
+	ArgoCDRepositoryTypeHelm = "helm"
+
 	// ArgoCDSecretName is the upstream hard-coded ArgoCD Secret name.
 	ArgoCDSecretName = "argocd-secret"
 
+	// ArgoCDSecretTypeRepository is the secret type label value of the Secrets holding an Argo CD repository.
+	ArgoCDSecretTypeRepository = "repository"
+
+	// ArgoCDSecretTypeRepositoryCredentials is the secret type label value of the Secrets holding an Argo CD
+	// credential template.
+	ArgoCDSecretTypeRepositoryCredentials = "repo-creds"
+
+	// ArgoCDServiceAccountCAPath is the path of the CA bundle mounted with the service account token.
+	ArgoCDServiceAccountCAPath = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
 	// ArgoCDStatusCompleted is the completed status value.
 	ArgoCDStatusCompleted = "Completed"
 
 	// ArgoCDTLSCertsConfigMapName is the upstream hard-coded TLS certificate data ConfigMap name.
 	ArgoCDTLSCertsConfigMapName = "argocd-tls-certs-cm"
 
+	// ArgoCDTLSKeyAlgorithmECDSA is the value for ECDSA private keys.
+	ArgoCDTLSKeyAlgorithmECDSA = "ECDSA"
+
+	// ArgoCDTLSKeyAlgorithmEd25519 is the value for Ed25519 private keys.
+	ArgoCDTLSKeyAlgorithmEd25519 = "Ed25519"
+
+	// ArgoCDTLSKeyAlgorithmRSA is the value for RSA private keys.
+	ArgoCDTLSKeyAlgorithmRSA = "RSA"
+
+	// ArgoCDTokenIssuer is the issuer of the API tokens signed by the Argo CD server.
+	ArgoCDTokenIssuer = "argocd"
+
+	// ArgoCDUserCapabilityAPIKey is the capability value for local user accounts that may use API tokens.
+	ArgoCDUserCapabilityAPIKey = "apiKey"
+
+	// ArgoCDUserCapabilityLogin is the capability value for local user accounts that may log in.
+	ArgoCDUserCapabilityLogin = "login"
+
+	// ArgoCDUserComponent is the component label value for the local user account Secrets.
+	ArgoCDUserComponent = "user"
+
+	// ArgoCDValidatingWebhookPath is the path for the ArgoCD validating webhook served by the operator.
+	ArgoCDValidatingWebhookPath = "/validate-argoproj-io-v1beta1-argocd"
+
+	// ArgoCDRepoServerAutoTLSOpenShift is the AutoTLS value for repo server certificates issued by the OpenShift
+	// service CA.
+	ArgoCDRepoServerAutoTLSOpenShift = "openshift"
+
+	// ArgoCDRepoServerAutoTLSOperator is the AutoTLS value for repo server certificates issued by the operator CA.
+	ArgoCDRepoServerAutoTLSOperator = "operator"
+
 	// ArgoCDRepoServerTLSSecretName is the name of the TLS secret for the repo-server
 	ArgoCDRepoServerTLSSecretName = "argocd-repo-server-tls"
 )
@@ -18,9 +18,8 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"reflect"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	corev1 "k8s.io/api/core/v1"
@@ -31,7 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func (r *ReconcileArgoCD) reconcileApplicationSetController(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileApplicationSetController(cr *argoprojv1b1.ArgoCD) error {
 
 	log.Info("reconciling applicationset serviceaccounts")
 	sa, err := r.reconcileApplicationSetServiceAccount(cr)
@@ -55,16 +54,28 @@ func (r *ReconcileArgoCD) reconcileApplicationSetController(cr *argoprojv1a1.Arg
 		return err
 	}
 
+	log.Info("reconciling applicationset pod disruption budgets")
+	if err := r.reconcileComponentPodDisruptionBudget("applicationset-controller", true, cr.Spec.ApplicationSet.PodDisruptionBudget, cr); err != nil {
+		return err
+	}
+
+	log.Info("reconciling applicationset autoscalers")
+	if err := r.reconcileApplicationSetHPA(cr); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // reconcileApplicationControllerDeployment will ensure the Deployment resource is present for the ArgoCD Application Controller component.
-func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.ArgoCD, sa *corev1.ServiceAccount) error {
+func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1b1.ArgoCD, sa *corev1.ServiceAccount) error {
 	deploy := newDeploymentWithSuffix("applicationset-controller", "controller", cr)
 
 	setAppSetLabels(&deploy.ObjectMeta)
+	deploy.Spec.Replicas = getComponentReplicas(cr.Spec.ApplicationSet.Replicas, cr.Spec.ApplicationSet.Autoscale, cr)
 
 	podSpec := &deploy.Spec.Template.Spec
+	podSpec.Affinity = getComponentAffinity(deploy.Name, cr)
 
 	podSpec.ServiceAccountName = sa.ObjectMeta.Name
 
@@ -117,7 +128,7 @@ func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.Arg
 	}
 
 	podSpec.Containers = []corev1.Container{{
-		Command: []string{"applicationset-controller", "--argocd-repo-server", getRepoServerAddress(cr)},
+		Command: getApplicationSetCommand(cr),
 		Env: []corev1.EnvVar{{
 			Name: "NAMESPACE",
 			ValueFrom: &corev1.EnvVarSource{
@@ -154,25 +165,10 @@ func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.Arg
 		},
 	}}
 
-	if existing := newDeploymentWithSuffix("applicationset-controller", "controller", cr); argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-
-		// If the Deployment already exists, make sure the containers are up-to-date
-		actualContainers := existing.Spec.Template.Spec.Containers[0]
-		if !reflect.DeepEqual(actualContainers, podSpec.Containers) {
-			existing.Spec.Template.Spec.Containers = podSpec.Containers
-			return r.client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
-	}
-
-	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), deploy)
-
+	return r.applyObject(cr, deploy)
 }
 
-func (r *ReconcileArgoCD) reconcileApplicationSetServiceAccount(cr *argoprojv1a1.ArgoCD) (*corev1.ServiceAccount, error) {
+func (r *ReconcileArgoCD) reconcileApplicationSetServiceAccount(cr *argoprojv1b1.ArgoCD) (*corev1.ServiceAccount, error) {
 
 	sa := newServiceAccountWithName("applicationset-controller", cr)
 	setAppSetLabels(&sa.ObjectMeta)
@@ -201,7 +197,7 @@ func (r *ReconcileArgoCD) reconcileApplicationSetServiceAccount(cr *argoprojv1a1
 	return sa, err
 }
 
-func (r *ReconcileArgoCD) reconcileApplicationSetRole(cr *argoprojv1a1.ArgoCD) (*v1.Role, error) {
+func (r *ReconcileArgoCD) reconcileApplicationSetRole(cr *argoprojv1b1.ArgoCD) (*v1.Role, error) {
 
 	policyRules := []v1.PolicyRule{
 
@@ -268,6 +264,30 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRole(cr *argoprojv1a1.ArgoCD) (
 			},
 		},
 
+		// Leader election
+		{
+			APIGroups: []string{""},
+			Resources: []string{
+				"configmaps",
+			},
+			Verbs: []string{
+				"create",
+				"get",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{"coordination.k8s.io"},
+			Resources: []string{
+				"leases",
+			},
+			Verbs: []string{
+				"create",
+				"get",
+				"update",
+			},
+		},
+
 		// Read Deployments
 		{
 			APIGroups: []string{"apps", "extensions"},
@@ -299,7 +319,7 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRole(cr *argoprojv1a1.ArgoCD) (
 	return role, r.client.Update(context.TODO(), role)
 }
 
-func (r *ReconcileArgoCD) reconcileApplicationSetRoleBinding(cr *argoprojv1a1.ArgoCD, role *v1.Role, sa *corev1.ServiceAccount) error {
+func (r *ReconcileArgoCD) reconcileApplicationSetRoleBinding(cr *argoprojv1b1.ArgoCD, role *v1.Role, sa *corev1.ServiceAccount) error {
 
 	name := "applicationset-controller"
 
@@ -342,7 +362,31 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRoleBinding(cr *argoprojv1a1.Ar
 	return r.client.Create(context.TODO(), roleBinding)
 }
 
-func getApplicationSetContainerImage(cr *argoprojv1a1.ArgoCD) string {
+// getApplicationSetCommand will return the command for the ApplicationSet controller.
+func getApplicationSetCommand(cr *argoprojv1b1.ArgoCD) []string {
+	cmd := []string{"applicationset-controller", "--argocd-repo-server", getRepoServerAddress(cr)}
+	if isRepoServerTLSVerificationRequested(cr) {
+		cmd = append(cmd, "--repo-server-strict-tls")
+	}
+	if isApplicationSetLeaderElectionEnabled(cr) {
+		cmd = append(cmd, "--enable-leader-election")
+	}
+	return cmd
+}
+
+// isApplicationSetLeaderElectionEnabled returns true if more than one ApplicationSet controller replica may run for
+// the given ArgoCD, only the elected leader reconciles the ApplicationSets.
+func isApplicationSetLeaderElectionEnabled(cr *argoprojv1b1.ArgoCD) bool {
+	if cr.Spec.ApplicationSet == nil {
+		return false
+	}
+	if cr.Spec.HA.Enabled || cr.Spec.ApplicationSet.Autoscale.Enabled {
+		return true
+	}
+	return cr.Spec.ApplicationSet.Replicas != nil && *cr.Spec.ApplicationSet.Replicas > 1
+}
+
+func getApplicationSetContainerImage(cr *argoprojv1b1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 
 	img := ""
@@ -372,7 +416,7 @@ func getApplicationSetContainerImage(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getApplicationSetResources will return the ResourceRequirements for the Application Sets container.
-func getApplicationSetResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getApplicationSetResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	// Allow override of resource requirements from CR
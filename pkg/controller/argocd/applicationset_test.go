@@ -20,7 +20,7 @@ import (
 	"sort"
 	"testing"
 
-	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	"github.com/google/go-cmp/cmp"
@@ -37,7 +37,7 @@ import (
 func TestReconcileApplicationSet_Deployments(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
-	a.Spec.ApplicationSet = &v1alpha1.ArgoCDApplicationSet{}
+	a.Spec.ApplicationSet = &v1beta1.ArgoCDApplicationSet{}
 
 	r := makeTestReconciler(t, a)
 
@@ -78,6 +78,32 @@ func TestReconcileApplicationSet_Deployments(t *testing.T) {
 	}
 }
 
+func Test_getApplicationSetCommand(t *testing.T) {
+	a := makeTestArgoCD()
+	a.Spec.Repo.AutoTLS = common.ArgoCDRepoServerAutoTLSOperator
+
+	want := []string{
+		"applicationset-controller",
+		"--argocd-repo-server", "argocd-repo-server.argocd.svc.cluster.local:8081",
+		"--repo-server-strict-tls",
+	}
+	assert.DeepEqual(t, getApplicationSetCommand(a), want)
+}
+
+func Test_getApplicationSetCommand_leaderElection(t *testing.T) {
+	a := makeTestArgoCD()
+	a.Spec.ApplicationSet = &v1beta1.ArgoCDApplicationSet{}
+	assert.DeepEqual(t, getApplicationSetCommand(a), []string{"applicationset-controller", "--argocd-repo-server", getRepoServerAddress(a)})
+
+	// Leader election is enabled when more than one replica may run.
+	a.Spec.HA.Enabled = true
+	assert.Equal(t, getApplicationSetCommand(a)[3], "--enable-leader-election")
+
+	a.Spec.HA.Enabled = false
+	a.Spec.ApplicationSet.Replicas = int32Ptr(2)
+	assert.Equal(t, getApplicationSetCommand(a)[3], "--enable-leader-election")
+}
+
 func TestReconcileApplicationSet_Deployments_resourceRequirements(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCDWithResources()
@@ -142,18 +168,18 @@ func TestReconcileApplicationSet_Deployments_SpecOverride(t *testing.T) {
 
 	tests := []struct {
 		name                   string
-		appSetField            *v1alpha1.ArgoCDApplicationSet
+		appSetField            *v1beta1.ArgoCDApplicationSet
 		envVars                map[string]string
 		expectedContainerImage string
 	}{
 		{
 			name:                   "unspecified fields should use default",
-			appSetField:            &v1alpha1.ArgoCDApplicationSet{},
+			appSetField:            &v1beta1.ArgoCDApplicationSet{},
 			expectedContainerImage: argoutil.CombineImageTag(common.ArgoCDDefaultApplicationSetImage, common.ArgoCDDefaultApplicationSetVersion),
 		},
 		{
 			name: "ensure that sha hashes are formatted correctly",
-			appSetField: &v1alpha1.ArgoCDApplicationSet{
+			appSetField: &v1beta1.ArgoCDApplicationSet{
 				Image:   "custom-image",
 				Version: "sha256:b835999eb5cf75d01a2678cd971095926d9c2566c9ffe746d04b83a6a0a2849f",
 			},
@@ -161,7 +187,7 @@ func TestReconcileApplicationSet_Deployments_SpecOverride(t *testing.T) {
 		},
 		{
 			name: "custom image should properly substitute",
-			appSetField: &v1alpha1.ArgoCDApplicationSet{
+			appSetField: &v1beta1.ArgoCDApplicationSet{
 				Image:   "custom-image",
 				Version: "custom-version",
 			},
@@ -169,14 +195,14 @@ func TestReconcileApplicationSet_Deployments_SpecOverride(t *testing.T) {
 		},
 		{
 			name:                   "verify env var substitution overrides default",
-			appSetField:            &v1alpha1.ArgoCDApplicationSet{},
+			appSetField:            &v1beta1.ArgoCDApplicationSet{},
 			envVars:                map[string]string{common.ArgoCDApplicationSetEnvName: "custom-env-image"},
 			expectedContainerImage: "custom-env-image",
 		},
 
 		{
 			name: "env var should not override spec fields",
-			appSetField: &v1alpha1.ArgoCDApplicationSet{
+			appSetField: &v1beta1.ArgoCDApplicationSet{
 				Image:   "custom-image",
 				Version: "custom-version",
 			},
@@ -263,6 +289,8 @@ func TestReconcileApplicationSet_Role(t *testing.T) {
 		"deployments",
 		"secrets",
 		"configmaps",
+		"configmaps",
+		"leases",
 		"events",
 		"applicationsets/status",
 		"applications",
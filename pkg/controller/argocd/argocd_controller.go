@@ -20,13 +20,15 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 
-	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/validation"
 )
 
 // blank assignment to verify that ReconcileArgoCD implements reconcile.Reconciler
@@ -38,6 +40,9 @@ type ReconcileArgoCD struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+
+	// recorder emits Events for the ArgoCD being reconciled.
+	recorder record.EventRecorder
 }
 
 var log = logf.Log.WithName("controller_argocd")
@@ -57,7 +62,7 @@ func add(mgr manager.Manager, r *ReconcileArgoCD) error {
 	}
 
 	// Register watches for all controller resources
-	if err := watchResources(c, r.clusterResourceMapper, r.tlsSecretMapper); err != nil {
+	if err := watchResources(c, r.clusterResourceMapper, r.tlsSecretMapper, r.secretReferenceMapper, r.rbacPolicyConfigMapMapper, r.groupMappingMapper); err != nil {
 		return err
 	}
 
@@ -67,8 +72,9 @@ func add(mgr manager.Manager, r *ReconcileArgoCD) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) *ReconcileArgoCD {
 	return &ReconcileArgoCD{
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor("argocd-operator"),
 	}
 }
 
@@ -118,11 +124,34 @@ func (r *ReconcileArgoCD) Reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, err
 	}
 
+	if err := validation.ValidateArgoCDSpec(argocd, IsRouteAPIAvailable()); err != nil {
+		// Resources created before the validating webhook was installed are not guaranteed to be valid.
+		err = fmt.Errorf("invalid ArgoCD: %w", err)
+		if statusErr := r.reconcileStatusConditions(argocd, argoproj.ArgoCDReasonInvalidSpec, err); statusErr != nil {
+			log.Error(statusErr, "unable to update status conditions")
+		}
+		return reconcile.Result{}, err
+	}
+
 	if err := r.reconcileResources(argocd); err != nil {
+		if statusErr := r.reconcileStatusConditions(argocd, argoproj.ArgoCDReasonReconcileFailed, err); statusErr != nil {
+			log.Error(statusErr, "unable to update status conditions")
+		}
 		// Error reconciling ArgoCD sub-resources - requeue the request.
 		return reconcile.Result{}, err
 	}
 
-	// Return and don't requeue
-	return reconcile.Result{}, nil
+	if err := r.reconcileStatusConditions(argocd, argoproj.ArgoCDReasonReconcileSucceeded, nil); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Requeue to renew the certificates and user API tokens and rotate the admin password and server secret key in
+	// time, no other event may occur before these are due.
+	return reconcile.Result{
+		RequeueAfter: getRequeueAfter(
+			getCertificateRenewalRequeue(argocd),
+			getAdminPasswordRotationRequeue(argocd),
+			getServerSecretKeyRotationRequeue(argocd),
+			r.getUserTokenRenewalRequeue(argocd)),
+	}, nil
 }
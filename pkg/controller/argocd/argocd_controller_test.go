@@ -17,20 +17,24 @@ package argocd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 
 	argov1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argov1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 )
@@ -95,10 +99,135 @@ func TestReconcileArgoCD_Reconcile(t *testing.T) {
 	}, deployment); err != nil {
 		t.Fatalf("failed to find the redis deployment: %#v\n", err)
 	}
+
+	assert.NilError(t, r.client.Get(context.TODO(), req.NamespacedName, a))
+	assert.Equal(t, a.Status.ObservedGeneration, a.Generation)
+	assertCondition(t, a, argov1beta1.ArgoCDConditionReconciled, corev1.ConditionTrue, argov1beta1.ArgoCDReasonReconcileSucceeded)
+	assertCondition(t, a, argov1beta1.ArgoCDConditionDegraded, corev1.ConditionFalse, argov1beta1.ArgoCDReasonReconcileSucceeded)
+	assertCondition(t, a, argov1beta1.ArgoCDConditionAvailable, corev1.ConditionFalse, argov1beta1.ArgoCDReasonComponentsPending)
+	assertCondition(t, a, argov1beta1.ArgoCDConditionProgressing, corev1.ConditionTrue, argov1beta1.ArgoCDReasonComponentsPending)
+	assert.Assert(t, argoutil.FindCondition(a.Status.Conditions, argov1beta1.ArgoCDConditionSSOReady) == nil)
+	assert.Assert(t, argoutil.FindCondition(a.Status.Conditions, argov1beta1.ArgoCDConditionExportReady) == nil)
+}
+
+func TestReconcileArgoCD_Reconcile_invalid(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	policy := "p, role:admin, applications, *, */*"
+	a := makeTestArgoCD(func(a *argov1beta1.ArgoCD) {
+		a.Spec.RBAC.Policy = &policy
+	})
+
+	r := makeTestReconciler(t, a)
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      a.Name,
+			Namespace: a.Namespace,
+		},
+	}
+
+	_, err := r.Reconcile(req)
+	assert.ErrorContains(t, err, "invalid ArgoCD: spec.rbac.policy")
+
+	assert.NilError(t, r.client.Get(context.TODO(), req.NamespacedName, a))
+	assertCondition(t, a, argov1beta1.ArgoCDConditionReconciled, corev1.ConditionFalse, argov1beta1.ArgoCDReasonInvalidSpec)
+	assertCondition(t, a, argov1beta1.ArgoCDConditionDegraded, corev1.ConditionTrue, argov1beta1.ArgoCDReasonInvalidSpec)
+	assert.Assert(t, strings.Contains(argoutil.FindCondition(a.Status.Conditions, argov1beta1.ArgoCDConditionDegraded).Message, "spec.rbac.policy"))
+
+	deployment := &appsv1.Deployment{}
+	assert.Assert(t, apierrors.IsNotFound(r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-redis",
+		Namespace: testNamespace,
+	}, deployment)))
+}
+
+func TestReconcileArgoCD_Reconcile_invalidStringField(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	alpha := &argov1alpha1.ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testArgoCDName,
+			Namespace: testNamespace,
+		},
+		Spec: argov1alpha1.ArgoCDSpec{
+			ResourceExclusions: "testing: testing",
+		},
+	}
+	a := &argov1beta1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(a))
+
+	r := makeTestReconciler(t, a)
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      a.Name,
+			Namespace: a.Namespace,
+		},
+	}
+
+	_, err := r.Reconcile(req)
+	assert.NilError(t, err)
+
+	assert.NilError(t, r.client.Get(context.TODO(), req.NamespacedName, a))
+	assertCondition(t, a, argov1beta1.ArgoCDConditionReconciled, corev1.ConditionTrue, argov1beta1.ArgoCDReasonReconcileSucceeded)
+	assertCondition(t, a, argov1beta1.ArgoCDConditionDegraded, corev1.ConditionTrue, argov1beta1.ArgoCDReasonInvalidStringField)
+	assert.Assert(t, strings.Contains(argoutil.FindCondition(a.Status.Conditions, argov1beta1.ArgoCDConditionDegraded).Message, "spec.resourceExclusions"))
+
+	// The field is reported once in the condition, not with an Event on every reconciliation.
+	recorder := r.recorder.(*record.FakeRecorder)
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		assert.Assert(t, !strings.Contains(event, argov1beta1.ArgoCDReasonInvalidStringField), event)
+	}
+
+	// The other resources are still reconciled, with the original value in the Argo CD ConfigMap.
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyResourceExclusions], "testing: testing")
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      "argocd-redis",
+		Namespace: testNamespace,
+	}, &appsv1.Deployment{}))
+}
+
+func TestReconcileArgoCD_reconcileStatusConditions_exportReady(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argov1beta1.ArgoCD) {
+		a.Spec.Import = &argov1beta1.ArgoCDImportSpec{Name: "test-export"}
+	})
+
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileStatusConditions(a, argov1beta1.ArgoCDReasonReconcileSucceeded, nil))
+	assertCondition(t, a, argov1beta1.ArgoCDConditionExportReady, corev1.ConditionFalse, argov1beta1.ArgoCDReasonExportNotFound)
+
+	export := &argov1alpha1.ArgoCDExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-export",
+			Namespace: a.Namespace,
+		},
+		Status: argov1alpha1.ArgoCDExportStatus{
+			Phase: common.ArgoCDStatusCompleted,
+		},
+	}
+	assert.NilError(t, r.client.Create(context.TODO(), export))
+
+	assert.NilError(t, r.reconcileStatusConditions(a, argov1beta1.ArgoCDReasonReconcileSucceeded, nil))
+	assertCondition(t, a, argov1beta1.ArgoCDConditionExportReady, corev1.ConditionTrue, argov1beta1.ArgoCDReasonExportCompleted)
+}
+
+func assertCondition(t *testing.T, a *argov1beta1.ArgoCD, conditionType string, status corev1.ConditionStatus, reason string) {
+	t.Helper()
+	c := argoutil.FindCondition(a.Status.Conditions, conditionType)
+	assert.Assert(t, c != nil, "condition %s not found", conditionType)
+	assert.Equal(t, c.Status, status)
+	assert.Equal(t, c.Reason, reason)
 }
 
 func deletedAt(now time.Time) argoCDOpt {
-	return func(a *argov1alpha1.ArgoCD) {
+	return func(a *argov1beta1.ArgoCD) {
 		wrapped := metav1.NewTime(now)
 		a.ObjectMeta.DeletionTimestamp = &wrapped
 	}
@@ -157,12 +286,12 @@ func TestReconcileArgoCD_CleanUp(t *testing.T) {
 }
 
 func addFinalizer(finalizer string) argoCDOpt {
-	return func(a *argov1alpha1.ArgoCD) {
+	return func(a *argov1beta1.ArgoCD) {
 		a.Finalizers = append(a.Finalizers, finalizer)
 	}
 }
 
-func clusterResources(argocd *argov1alpha1.ArgoCD) []runtime.Object {
+func clusterResources(argocd *argov1beta1.ArgoCD) []runtime.Object {
 	return []runtime.Object{
 		newClusterRole(common.ArgoCDApplicationControllerComponent, []v1.PolicyRule{}, argocd),
 		newClusterRole(common.ArgoCDServerComponent, []v1.PolicyRule{}, argocd),
@@ -0,0 +1,269 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+var certManagerAPIFound = false
+
+// certificateGVK is the GroupVersionKind of the cert-manager Certificate resource.
+var certificateGVK = schema.GroupVersionKind{
+	Group:   common.ArgoCDCertManagerGroup,
+	Version: common.ArgoCDCertManagerVersion,
+	Kind:    "Certificate",
+}
+
+// IsCertManagerAPIAvailable returns true if the cert-manager API is present.
+func IsCertManagerAPIAvailable() bool {
+	return certManagerAPIFound
+}
+
+// verifyCertManagerAPI will verify that the cert-manager API is present.
+func verifyCertManagerAPI() error {
+	found, err := argoutil.VerifyAPI(common.ArgoCDCertManagerGroup, common.ArgoCDCertManagerVersion)
+	if err != nil {
+		return err
+	}
+	certManagerAPIFound = found
+	return nil
+}
+
+// useCertManager returns true if the certificates for the given ArgoCD are issued by cert-manager.
+func useCertManager(cr *argoprojv1b1.ArgoCD) bool {
+	return cr.Spec.TLS.Issuer != nil && IsCertManagerAPIAvailable()
+}
+
+// newCertificate returns a new cert-manager Certificate instance with the given name for the given ArgoCD.
+func newCertificate(name string, cr *argoprojv1b1.ArgoCD) *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(certificateGVK)
+	cert.SetName(name)
+	cert.SetNamespace(cr.Namespace)
+	cert.SetLabels(labelsForCluster(cr))
+	return cert
+}
+
+// newCertificateList returns a new list for cert-manager Certificate instances.
+func newCertificateList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(certificateGVK.GroupVersion().WithKind(certificateGVK.Kind + "List"))
+	return list
+}
+
+// getCertificateIssuerRef will return the cert-manager issuer reference for the given ArgoCD.
+func getCertificateIssuerRef(cr *argoprojv1b1.ArgoCD) map[string]interface{} {
+	group := common.ArgoCDDefaultCertificateIssuerGroup
+	if len(cr.Spec.TLS.Issuer.Group) > 0 {
+		group = cr.Spec.TLS.Issuer.Group
+	}
+
+	kind := common.ArgoCDDefaultCertificateIssuerKind
+	if len(cr.Spec.TLS.Issuer.Kind) > 0 {
+		kind = cr.Spec.TLS.Issuer.Kind
+	}
+
+	return map[string]interface{}{
+		"group": group,
+		"kind":  kind,
+		"name":  cr.Spec.TLS.Issuer.Name,
+	}
+}
+
+// newIssuedCertificate returns a cert-manager Certificate that stores a certificate for the given DNS names, issued by
+// the issuer of the given ArgoCD, in the secret with the given name.
+func newIssuedCertificate(secretName string, dnsNames []string, cr *argoprojv1b1.ArgoCD) *unstructured.Unstructured {
+	cert := newCertificate(secretName, cr)
+
+	names := make([]interface{}, 0, len(dnsNames))
+	for _, name := range dnsNames {
+		names = append(names, name)
+	}
+
+	cert.Object["spec"] = map[string]interface{}{
+		"dnsNames":   names,
+		"issuerRef":  getCertificateIssuerRef(cr),
+		"secretName": secretName,
+		// The annotation maps events on the issued secret back to the ArgoCD, see tlsSecretMapper.
+		"secretTemplate": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				common.AnnotationName: cr.Name,
+			},
+		},
+		"usages": []interface{}{
+			"digital signature",
+			"key encipherment",
+			"server auth",
+			"client auth",
+		},
+	}
+	return cert
+}
+
+// getRepoServerDNSNames will return the DNS names for the repo server certificate.
+func getRepoServerDNSNames(cr *argoprojv1b1.ArgoCD) []string {
+	name := nameWithSuffix("repo-server", cr)
+	return []string{
+		name,
+		fmt.Sprintf("%s.%s.svc", name, cr.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, cr.Namespace),
+	}
+}
+
+// getRedisDNSNames will return the DNS names for the Redis server certificate.
+func getRedisDNSNames(cr *argoprojv1b1.ArgoCD) []string {
+	services := []string{nameWithSuffix("redis", cr)}
+	if cr.Spec.HA.Enabled {
+		services = []string{nameWithSuffix("redis-ha", cr), nameWithSuffix("redis-ha-haproxy", cr)}
+	}
+
+	dnsNames := []string{}
+	for _, svc := range services {
+		dnsNames = append(dnsNames,
+			svc,
+			fmt.Sprintf("%s.%s.svc", svc, cr.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", svc, cr.Namespace))
+	}
+	return dnsNames
+}
+
+// getIngressTLSHosts will return the hosts for each secret referenced by the TLS options of the enabled Ingresses for
+// the given ArgoCD. The Argo CD secret used by default is left out, it is kept in sync with the server certificate.
+func getIngressTLSHosts(cr *argoprojv1b1.ArgoCD) map[string][]string {
+	tls := []extv1beta1.IngressTLS{}
+	if cr.Spec.Server.Ingress.Enabled {
+		tls = append(tls, cr.Spec.Server.Ingress.TLS...)
+	}
+	if cr.Spec.Server.GRPC.Ingress.Enabled {
+		tls = append(tls, cr.Spec.Server.GRPC.Ingress.TLS...)
+	}
+	if cr.Spec.Grafana.Enabled && cr.Spec.Grafana.Ingress.Enabled {
+		tls = append(tls, cr.Spec.Grafana.Ingress.TLS...)
+	}
+	if cr.Spec.Prometheus.Enabled && cr.Spec.Prometheus.Ingress.Enabled {
+		tls = append(tls, cr.Spec.Prometheus.Ingress.TLS...)
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, t := range tls {
+		if len(t.SecretName) == 0 || t.SecretName == common.ArgoCDSecretName || len(t.Hosts) == 0 {
+			continue
+		}
+		if seen[t.SecretName] == nil {
+			seen[t.SecretName] = make(map[string]bool)
+		}
+		for _, host := range t.Hosts {
+			seen[t.SecretName][host] = true
+		}
+	}
+
+	hosts := make(map[string][]string)
+	for secretName, names := range seen {
+		for host := range names {
+			hosts[secretName] = append(hosts[secretName], host)
+		}
+		sort.Strings(hosts[secretName])
+	}
+	return hosts
+}
+
+// getCertificates will return the cert-manager Certificates for the given ArgoCD, keyed by name.
+func getCertificates(cr *argoprojv1b1.ArgoCD) map[string]*unstructured.Unstructured {
+	certs := make(map[string]*unstructured.Unstructured)
+	if cr.Spec.TLS.Issuer == nil {
+		return certs
+	}
+
+	add := func(secretName string, dnsNames []string) {
+		certs[secretName] = newIssuedCertificate(secretName, dnsNames, cr)
+	}
+
+	add(nameWithSuffix("tls", cr), getArgoServerDNSNames(cr))
+	if !isRedisExternal(cr) {
+		add(common.ArgoCDRedisServerTLSSecretName, getRedisDNSNames(cr))
+	}
+
+	// The OpenShift service CA or the operator CA takes precedence for the repo server certificate when requested.
+	if len(cr.Spec.Repo.AutoTLS) == 0 {
+		add(common.ArgoCDRepoServerTLSSecretName, getRepoServerDNSNames(cr))
+	}
+
+	for secretName, hosts := range getIngressTLSHosts(cr) {
+		if _, ok := certs[secretName]; !ok {
+			add(secretName, hosts)
+		}
+	}
+	return certs
+}
+
+// reconcileCertificates will ensure that the cert-manager Certificates are present for the given ArgoCD when an
+// issuer is configured, and removes the Certificates that are no longer needed.
+func (r *ReconcileArgoCD) reconcileCertificates(cr *argoprojv1b1.ArgoCD) error {
+	if !IsCertManagerAPIAvailable() {
+		if cr.Spec.TLS.Issuer != nil {
+			log.Info("cert-manager API not available, using the operator CA for the Argo CD certificates")
+		}
+		return nil
+	}
+
+	certs := getCertificates(cr)
+
+	names := make([]string, 0, len(certs))
+	for name := range certs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := r.applyObject(cr, certs[name]); err != nil {
+			return err
+		}
+	}
+
+	existing := newCertificateList()
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labelsForCluster(cr)),
+		Namespace:     cr.Namespace,
+	}
+	if err := r.client.List(context.TODO(), existing, opts); err != nil {
+		return err
+	}
+
+	for i := range existing.Items {
+		cert := &existing.Items[i]
+		if _, ok := certs[cert.GetName()]; ok || !metav1.IsControlledBy(cert, cr) {
+			continue
+		}
+		log.Info(fmt.Sprintf("deleting certificate [%s], it is no longer needed", cert.GetName()))
+		if err := r.client.Delete(context.TODO(), cert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
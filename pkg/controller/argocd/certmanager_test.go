@@ -0,0 +1,149 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// enableCertManagerAPI marks the cert-manager API as available and registers the Certificate kinds with the test
+// scheme, so that the fake client can list them.
+func enableCertManagerAPI(t *testing.T) {
+	certManagerAPIFound = true
+	scheme.Scheme.AddKnownTypeWithName(certificateGVK, &unstructured.Unstructured{})
+	scheme.Scheme.AddKnownTypeWithName(newCertificateList().GroupVersionKind(), &unstructured.UnstructuredList{})
+	t.Cleanup(func() {
+		certManagerAPIFound = false
+	})
+}
+
+func withIssuer(name string, kind string) argoCDOpt {
+	return func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.TLS.Issuer = &argoprojv1beta1.ArgoCDTLSIssuerSpec{
+			Kind: kind,
+			Name: name,
+		}
+	}
+}
+
+func getTestCertificate(t *testing.T, r *ReconcileArgoCD, name string) (*unstructured.Unstructured, error) {
+	t.Helper()
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(certificateGVK)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, cert)
+	return cert, err
+}
+
+func Test_getIngressTLSHosts(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Server.Ingress.Enabled = true
+		a.Spec.Server.Ingress.TLS = []extv1beta1.IngressTLS{
+			{Hosts: []string{"server.example.com"}, SecretName: "server-ingress-tls"},
+			{Hosts: []string{"default.example.com"}, SecretName: common.ArgoCDSecretName},
+		}
+		a.Spec.Server.GRPC.Ingress.Enabled = true
+		a.Spec.Server.GRPC.Ingress.TLS = []extv1beta1.IngressTLS{
+			{Hosts: []string{"grpc.example.com"}, SecretName: "server-ingress-tls"},
+		}
+		// Grafana is not enabled, the Ingress TLS options are ignored.
+		a.Spec.Grafana.Ingress.Enabled = true
+		a.Spec.Grafana.Ingress.TLS = []extv1beta1.IngressTLS{
+			{Hosts: []string{"grafana.example.com"}, SecretName: "grafana-ingress-tls"},
+		}
+	})
+
+	want := map[string][]string{
+		"server-ingress-tls": {"grpc.example.com", "server.example.com"},
+	}
+	assert.DeepEqual(t, getIngressTLSHosts(a), want)
+}
+
+func Test_newIssuedCertificate(t *testing.T) {
+	a := makeTestArgoCD(withIssuer("ca-issuer", "ClusterIssuer"))
+
+	cert := newIssuedCertificate(common.ArgoCDRepoServerTLSSecretName, getRepoServerDNSNames(a), a)
+
+	assert.Equal(t, cert.GroupVersionKind(), certificateGVK)
+	assert.Equal(t, cert.GetName(), common.ArgoCDRepoServerTLSSecretName)
+	assert.Equal(t, cert.GetNamespace(), testNamespace)
+
+	secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+	assert.Equal(t, secretName, common.ArgoCDRepoServerTLSSecretName)
+
+	issuerRef, _, _ := unstructured.NestedStringMap(cert.Object, "spec", "issuerRef")
+	assert.DeepEqual(t, issuerRef, map[string]string{
+		"group": common.ArgoCDDefaultCertificateIssuerGroup,
+		"kind":  "ClusterIssuer",
+		"name":  "ca-issuer",
+	})
+
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	assert.DeepEqual(t, dnsNames, []string{
+		"argocd-repo-server",
+		"argocd-repo-server.argocd.svc",
+		"argocd-repo-server.argocd.svc.cluster.local",
+	})
+}
+
+func TestReconcileArgoCD_reconcileCertificates(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	enableCertManagerAPI(t)
+	a := makeTestArgoCD(withIssuer("ca-issuer", ""))
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileCertificates(a))
+
+	names := []string{
+		"argocd-tls",
+		common.ArgoCDRepoServerTLSSecretName,
+		common.ArgoCDRedisServerTLSSecretName,
+	}
+	for _, name := range names {
+		cert, err := getTestCertificate(t, r, name)
+		assert.NilError(t, err)
+		kind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")
+		assert.Equal(t, kind, common.ArgoCDDefaultCertificateIssuerKind)
+	}
+
+	// Removing the issuer removes the Certificates
+	a.Spec.TLS.Issuer = nil
+	assert.NilError(t, r.reconcileCertificates(a))
+
+	for _, name := range names {
+		_, err := getTestCertificate(t, r, name)
+		assert.Assert(t, apierrors.IsNotFound(err), "certificate %s should have been deleted", name)
+	}
+}
+
+func TestReconcileArgoCD_reconcileCertificates_openShiftAutoTLS(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	enableCertManagerAPI(t)
+	a := makeTestArgoCD(withIssuer("ca-issuer", ""), func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Repo.AutoTLS = "openshift"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileCertificates(a))
+
+	_, err := getTestCertificate(t, r, common.ArgoCDRepoServerTLSSecretName)
+	assert.Assert(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileCertificates_apiNotAvailable(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withIssuer("ca-issuer", ""))
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileCertificates(a))
+	assert.Assert(t, !useCertManager(a))
+}
@@ -0,0 +1,391 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+// argoCDComponent is a unit of ArgoCD resources that is reconciled independently of the other components.
+type argoCDComponent struct {
+	// name identifies the component in logs, events and errors.
+	name string
+
+	// dependsOn are the names of the components that must be reconciled successfully before this component.
+	dependsOn []string
+
+	// enabled returns true if the component should be reconciled for the given ArgoCD, always when nil.
+	enabled func(cr *argoprojv1b1.ArgoCD) bool
+
+	// reconcile will ensure that the resources for the component are present for the given ArgoCD.
+	reconcile func(r *ReconcileArgoCD, cr *argoprojv1b1.ArgoCD) error
+}
+
+// argoCDComponents is the dependency graph of the ArgoCD components. Components are listed after the components
+// they depend on, and are reconciled in this order.
+var argoCDComponents = []argoCDComponent{
+	{
+		name:      "status",
+		reconcile: (*ReconcileArgoCD).reconcileStatus,
+	},
+	{
+		name:      "rbac",
+		reconcile: (*ReconcileArgoCD).reconcileRBACComponent,
+	},
+	{
+		name:      "certificate-authority",
+		reconcile: (*ReconcileArgoCD).reconcileCertificateAuthority,
+	},
+	{
+		name:      "certificates",
+		reconcile: (*ReconcileArgoCD).reconcileCertificates,
+	},
+	{
+		name:      "secrets",
+		dependsOn: []string{"certificate-authority"},
+		reconcile: (*ReconcileArgoCD).reconcileSecrets,
+	},
+	{
+		name:      "config",
+		reconcile: (*ReconcileArgoCD).reconcileConfigComponent,
+	},
+	{
+		name:      "users",
+		dependsOn: []string{"secrets", "config"},
+		reconcile: (*ReconcileArgoCD).reconcileUsers,
+	},
+	{
+		name:      "repositories",
+		reconcile: (*ReconcileArgoCD).reconcileRepositorySecrets,
+	},
+	{
+		name:      "redis",
+		dependsOn: []string{"rbac", "secrets"},
+		reconcile: (*ReconcileArgoCD).reconcileRedisComponent,
+	},
+	{
+		name:      "dex",
+		dependsOn: []string{"rbac", "config"},
+		reconcile: (*ReconcileArgoCD).reconcileDexComponent,
+	},
+	{
+		name:      "repo-server",
+		dependsOn: []string{"secrets", "config"},
+		reconcile: (*ReconcileArgoCD).reconcileRepoServerComponent,
+	},
+	{
+		name:      "server",
+		dependsOn: []string{"rbac", "secrets", "config"},
+		reconcile: (*ReconcileArgoCD).reconcileServerComponent,
+	},
+	{
+		name:      "application-controller",
+		dependsOn: []string{"rbac", "secrets", "config"},
+		reconcile: (*ReconcileArgoCD).reconcileApplicationControllerComponent,
+	},
+	{
+		name:      "repo-server-tls",
+		dependsOn: []string{"repo-server", "server", "application-controller"},
+		reconcile: (*ReconcileArgoCD).reconcileRepoServerTLSSecret,
+	},
+	{
+		name:      "redis-tls",
+		dependsOn: []string{"redis", "repo-server", "server", "application-controller"},
+		reconcile: (*ReconcileArgoCD).reconcileRedisTLSSecret,
+	},
+	{
+		name:      "grafana",
+		dependsOn: []string{"secrets"},
+		reconcile: (*ReconcileArgoCD).reconcileGrafanaComponent,
+	},
+	{
+		name:      "prometheus",
+		reconcile: (*ReconcileArgoCD).reconcilePrometheusComponent,
+	},
+	{
+		name:      "applicationset",
+		enabled:   func(cr *argoprojv1b1.ArgoCD) bool { return cr.Spec.ApplicationSet != nil },
+		reconcile: (*ReconcileArgoCD).reconcileApplicationSetController,
+	},
+	{
+		name:      "sso",
+		dependsOn: []string{"server"},
+		enabled:   func(cr *argoprojv1b1.ArgoCD) bool { return cr.Spec.SSO != nil },
+		reconcile: (*ReconcileArgoCD).reconcileSSO,
+	},
+	{
+		name:      "network-policy",
+		reconcile: (*ReconcileArgoCD).reconcileNetworkPolicies,
+	},
+}
+
+// reconcileComponents will reconcile each of the given components in order. A component that fails does not stop
+// the reconciliation of the components that do not depend on it, all failures are returned as an aggregate error.
+func (r *ReconcileArgoCD) reconcileComponents(cr *argoprojv1b1.ArgoCD, components []argoCDComponent) error {
+	failed := make(map[string]bool)
+	errs := make([]error, 0)
+
+	for _, c := range components {
+		if c.enabled != nil && !c.enabled(cr) {
+			continue
+		}
+
+		if dep := failedDependency(c, failed); dep != "" {
+			log.Info(fmt.Sprintf("skipping %s, dependency %s was not reconciled", c.name, dep))
+			failed[c.name] = true
+			continue
+		}
+
+		log.Info(fmt.Sprintf("reconciling %s", c.name))
+		if err := c.reconcile(r, cr); err != nil {
+			err = fmt.Errorf("%s: %w", c.name, err)
+			log.Error(err, "failed to reconcile component")
+			r.recorder.Event(cr, corev1.EventTypeWarning, argoprojv1b1.ArgoCDReasonReconcileFailed, err.Error())
+			failed[c.name] = true
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// failedDependency returns the name of the first dependency of the given component that failed, if any.
+func failedDependency(c argoCDComponent, failed map[string]bool) string {
+	for _, dep := range c.dependsOn {
+		if failed[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// reconcileRBACComponent will ensure that the Roles, RoleBindings and ServiceAccounts are present.
+func (r *ReconcileArgoCD) reconcileRBACComponent(cr *argoprojv1b1.ArgoCD) error {
+	if _, err := r.reconcileRoles(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRoleBindings(cr); err != nil {
+		return err
+	}
+
+	return r.reconcileServiceAccounts(cr)
+}
+
+// reconcileConfigComponent will ensure that the ConfigMaps shared by the Argo CD components are present, along with
+// the Dex connector secrets referenced by the Argo CD ConfigMap.
+func (r *ReconcileArgoCD) reconcileConfigComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcileDexConnectorSecrets(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileArgoConfigMap(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRBAC(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileSSHKnownHosts(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileTLSCerts(cr); err != nil {
+		return err
+	}
+
+	return r.reconcileGPGKeysConfigMap(cr)
+}
+
+// reconcileRedisComponent will ensure that the Redis resources are present, unless an external Redis is used.
+func (r *ReconcileArgoCD) reconcileRedisComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcileRedisHAPodDisruptionBudgets(cr); err != nil {
+		return err
+	}
+
+	if isRedisExternal(cr) {
+		// The Argo CD components connect to the external Redis, the Redis deployed before is removed.
+		return r.deleteRedisWorkloads(cr)
+	}
+
+	if err := r.reconcileRedisConfiguration(cr); err != nil {
+		return err
+	}
+
+	if cr.Spec.HA.Enabled {
+		if err := r.reconcileRedisHAServices(cr); err != nil {
+			return err
+		}
+	} else {
+		if err := r.reconcileRedisService(cr); err != nil {
+			return err
+		}
+	}
+
+	if err := r.reconcileRedisDeployment(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRedisHAProxyDeployment(cr); err != nil {
+		return err
+	}
+
+	return r.reconcileRedisStatefulSet(cr)
+}
+
+// reconcileDexComponent will ensure that the Dex resources are present.
+func (r *ReconcileArgoCD) reconcileDexComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcileDexService(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileDexServiceCAConfigMap(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileDexDeployment(cr); err != nil {
+		return err
+	}
+
+	// Dex runs a single replica, a budget would only block the eviction of its pod, unless one is set explicitly.
+	enabled := !isDexDisabled() && cr.Spec.Dex.PodDisruptionBudget != nil
+	return r.reconcileComponentPodDisruptionBudget("dex-server", enabled, cr.Spec.Dex.PodDisruptionBudget, cr)
+}
+
+// reconcileRepoServerComponent will ensure that the Repo Server resources are present.
+func (r *ReconcileArgoCD) reconcileRepoServerComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcileRepoService(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRepoDeployment(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileComponentPodDisruptionBudget("repo-server", true, cr.Spec.Repo.PodDisruptionBudget, cr); err != nil {
+		return err
+	}
+
+	return r.reconcileRepoServerHPA(cr)
+}
+
+// reconcileServerComponent will ensure that the Argo CD Server resources are present.
+func (r *ReconcileArgoCD) reconcileServerComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcileServerService(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileServerMetricsService(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileServerDeployment(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileComponentPodDisruptionBudget("server", true, cr.Spec.Server.PodDisruptionBudget, cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileServerHPA(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileArgoServerIngress(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileArgoServerGRPCIngress(cr); err != nil {
+		return err
+	}
+
+	if IsRouteAPIAvailable() {
+		return r.reconcileServerRoute(cr)
+	}
+	return nil
+}
+
+// reconcileApplicationControllerComponent will ensure that the Application Controller resources are present.
+func (r *ReconcileArgoCD) reconcileApplicationControllerComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcileMetricsService(cr); err != nil {
+		return err
+	}
+
+	return r.reconcileApplicationControllerStatefulSet(cr)
+}
+
+// reconcileGrafanaComponent will ensure that the Grafana resources are present.
+func (r *ReconcileArgoCD) reconcileGrafanaComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcileGrafanaConfiguration(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileGrafanaDashboards(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileGrafanaService(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileGrafanaDeployment(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileGrafanaIngress(cr); err != nil {
+		return err
+	}
+
+	if IsRouteAPIAvailable() {
+		return r.reconcileGrafanaRoute(cr)
+	}
+	return nil
+}
+
+// reconcilePrometheusComponent will ensure that the Prometheus resources are present.
+func (r *ReconcileArgoCD) reconcilePrometheusComponent(cr *argoprojv1b1.ArgoCD) error {
+	if err := r.reconcilePrometheusIngress(cr); err != nil {
+		return err
+	}
+
+	if IsRouteAPIAvailable() {
+		if err := r.reconcilePrometheusRoute(cr); err != nil {
+			return err
+		}
+	}
+
+	if !IsPrometheusAPIAvailable() {
+		return nil
+	}
+
+	if err := r.reconcilePrometheus(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileMetricsServiceMonitor(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRepoServerServiceMonitor(cr); err != nil {
+		return err
+	}
+
+	return r.reconcileServerMetricsServiceMonitor(cr)
+}
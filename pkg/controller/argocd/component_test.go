@@ -0,0 +1,102 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argov1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+func TestArgoCDComponents_dependencyOrder(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range argoCDComponents {
+		assert.Assert(t, !seen[c.name], "component %s is defined more than once", c.name)
+		for _, dep := range c.dependsOn {
+			assert.Assert(t, seen[dep], "component %s depends on %s, which must be defined before it", c.name, dep)
+		}
+		seen[c.name] = true
+	}
+}
+
+func TestReconcileArgoCD_reconcileComponents(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	reconciled := []string{}
+	component := func(name string, err error, dependsOn ...string) argoCDComponent {
+		return argoCDComponent{
+			name:      name,
+			dependsOn: dependsOn,
+			reconcile: func(r *ReconcileArgoCD, cr *argov1beta1.ArgoCD) error {
+				reconciled = append(reconciled, name)
+				return err
+			},
+		}
+	}
+
+	components := []argoCDComponent{
+		component("secrets", errors.New("test error")),
+		component("config", nil),
+		component("server", nil, "secrets", "config"),
+		component("sso", nil, "server"),
+		component("grafana", errors.New("another error"), "config"),
+		component("prometheus", nil),
+		{
+			name:    "disabled",
+			enabled: func(cr *argov1beta1.ArgoCD) bool { return false },
+			reconcile: func(r *ReconcileArgoCD, cr *argov1beta1.ArgoCD) error {
+				reconciled = append(reconciled, "disabled")
+				return nil
+			},
+		},
+	}
+
+	err := r.reconcileComponents(a, components)
+	assert.Error(t, err, "[secrets: test error, grafana: another error]")
+	assert.DeepEqual(t, reconciled, []string{"secrets", "config", "grafana", "prometheus"})
+
+	recorder := r.recorder.(*record.FakeRecorder)
+	events := []string{}
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	assert.Equal(t, len(events), 2)
+	assert.Assert(t, strings.Contains(events[0], "secrets: test error"))
+	assert.Assert(t, strings.Contains(events[1], "grafana: another error"))
+}
+
+func TestReconcileArgoCD_reconcileComponents_noErrors(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	components := []argoCDComponent{
+		{
+			name:      "config",
+			reconcile: func(r *ReconcileArgoCD, cr *argov1beta1.ArgoCD) error { return nil },
+		},
+	}
+
+	assert.NilError(t, r.reconcileComponents(a, components))
+	assert.Equal(t, len(r.recorder.(*record.FakeRecorder).Events), 0)
+}
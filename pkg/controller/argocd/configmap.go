@@ -16,24 +16,34 @@ package argocd
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
 )
 
 // createRBACConfigMap will create the Argo CD RBAC ConfigMap resource.
-func (r *ReconcileArgoCD) createRBACConfigMap(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) createRBACConfigMap(cm *corev1.ConfigMap, cr *argoprojv1b1.ArgoCD) error {
+	policy, err := r.getRBACPolicyCSV(cr)
+	if err != nil {
+		return err
+	}
+
 	data := make(map[string]string)
-	data[common.ArgoCDKeyRBACPolicyCSV] = getRBACPolicy(cr)
+	data[common.ArgoCDKeyRBACPolicyCSV] = policy
 	data[common.ArgoCDKeyRBACPolicyDefault] = getRBACDefaultPolicy(cr)
 	data[common.ArgoCDKeyRBACScopes] = getRBACScopes(cr)
 	cm.Data = data
@@ -45,7 +55,7 @@ func (r *ReconcileArgoCD) createRBACConfigMap(cm *corev1.ConfigMap, cr *argoproj
 }
 
 // getApplicationInstanceLabelKey will return the application instance label key  for the given ArgoCD.
-func getApplicationInstanceLabelKey(cr *argoprojv1a1.ArgoCD) string {
+func getApplicationInstanceLabelKey(cr *argoprojv1b1.ArgoCD) string {
 	key := common.ArgoCDDefaultApplicationInstanceLabelKey
 	if len(cr.Spec.ApplicationInstanceLabelKey) > 0 {
 		key = cr.Spec.ApplicationInstanceLabelKey
@@ -54,7 +64,7 @@ func getApplicationInstanceLabelKey(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getCAConfigMapName will return the CA ConfigMap name for the given ArgoCD.
-func getCAConfigMapName(cr *argoprojv1a1.ArgoCD) string {
+func getCAConfigMapName(cr *argoprojv1b1.ArgoCD) string {
 	if len(cr.Spec.TLS.CA.ConfigMapName) > 0 {
 		return cr.Spec.TLS.CA.ConfigMapName
 	}
@@ -62,24 +72,46 @@ func getCAConfigMapName(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getConfigManagementPlugins will return the config management plugins for the given ArgoCD.
-func getConfigManagementPlugins(cr *argoprojv1a1.ArgoCD) string {
+func getConfigManagementPlugins(cr *argoprojv1b1.ArgoCD) (string, error) {
 	plugins := common.ArgoCDDefaultConfigManagementPlugins
 	if len(cr.Spec.ConfigManagementPlugins) > 0 {
-		plugins = cr.Spec.ConfigManagementPlugins
+		return marshalConfig(common.ArgoCDKeyConfigManagementPlugins, cr.Spec.ConfigManagementPlugins)
+	}
+	if raw, ok := argoprojv1a1.GetUnparsedStringField(cr, "configManagementPlugins"); ok {
+		plugins = raw
 	}
-	return plugins
+	return plugins, nil
 }
 
-func getDexConfig(cr *argoprojv1a1.ArgoCD) string {
+// getDexConfig will return the Dex configuration for the given ArgoCD. The OpenShift connector is added after the
+// typed connectors when OpenShift OAuth is enabled, unless the Dex configuration has connectors of its own.
+func (r *ReconcileArgoCD) getDexConfig(cr *argoprojv1b1.ArgoCD) (string, error) {
 	config := common.ArgoCDDefaultDexConfig
-	if len(cr.Spec.Dex.Config) > 0 {
-		config = cr.Spec.Dex.Config
+	dex := &argoprojv1b1.ArgoCDDexConfig{Connectors: getDexConnectors(cr)}
+	if cr.Spec.Dex.Config != nil {
+		dex.Options = cr.Spec.Dex.Config.Options
+	}
+
+	raw, unparsed := argoprojv1a1.GetUnparsedStringField(cr, "dex.config")
+	if cr.Spec.Dex.OpenShiftOAuth && !unparsed && (cr.Spec.Dex.Config == nil || len(cr.Spec.Dex.Config.Connectors) == 0) {
+		connector, err := r.getOpenShiftDexConnector(cr)
+		if err != nil {
+			return "", err
+		}
+		dex.Connectors = append(dex.Connectors, *connector)
 	}
-	return config
+
+	if len(dex.Connectors) > 0 || len(dex.Options) > 0 {
+		return marshalConfig(common.ArgoCDKeyDexConfig, dex)
+	}
+	if unparsed {
+		config = raw
+	}
+	return config, nil
 }
 
 // getGATrackingID will return the google analytics tracking ID for the given Argo CD.
-func getGATrackingID(cr *argoprojv1a1.ArgoCD) string {
+func getGATrackingID(cr *argoprojv1b1.ArgoCD) string {
 	id := common.ArgoCDDefaultGATrackingID
 	if len(cr.Spec.GATrackingID) > 0 {
 		id = cr.Spec.GATrackingID
@@ -88,7 +120,7 @@ func getGATrackingID(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getHelpChatURL will return the help chat URL for the given Argo CD.
-func getHelpChatURL(cr *argoprojv1a1.ArgoCD) string {
+func getHelpChatURL(cr *argoprojv1b1.ArgoCD) string {
 	url := common.ArgoCDDefaultHelpChatURL
 	if len(cr.Spec.HelpChatURL) > 0 {
 		url = cr.Spec.HelpChatURL
@@ -97,7 +129,7 @@ func getHelpChatURL(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getHelpChatText will return the help chat text for the given Argo CD.
-func getHelpChatText(cr *argoprojv1a1.ArgoCD) string {
+func getHelpChatText(cr *argoprojv1b1.ArgoCD) string {
 	text := common.ArgoCDDefaultHelpChatText
 	if len(cr.Spec.HelpChatText) > 0 {
 		text = cr.Spec.HelpChatText
@@ -106,7 +138,7 @@ func getHelpChatText(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getKustomizeBuildOptions will return the kuztomize build options for the given ArgoCD.
-func getKustomizeBuildOptions(cr *argoprojv1a1.ArgoCD) string {
+func getKustomizeBuildOptions(cr *argoprojv1b1.ArgoCD) string {
 	kbo := common.ArgoCDDefaultKustomizeBuildOptions
 	if len(cr.Spec.KustomizeBuildOptions) > 0 {
 		kbo = cr.Spec.KustomizeBuildOptions
@@ -115,16 +147,19 @@ func getKustomizeBuildOptions(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getOIDCConfig will return the OIDC configuration for the given ArgoCD.
-func getOIDCConfig(cr *argoprojv1a1.ArgoCD) string {
+func getOIDCConfig(cr *argoprojv1b1.ArgoCD) (string, error) {
 	config := common.ArgoCDDefaultOIDCConfig
-	if len(cr.Spec.OIDCConfig) > 0 {
-		config = cr.Spec.OIDCConfig
+	if cr.Spec.OIDCConfig != nil {
+		return marshalConfig(common.ArgoCDKeyOIDCConfig, cr.Spec.OIDCConfig)
 	}
-	return config
+	if raw, ok := argoprojv1a1.GetUnparsedStringField(cr, "oidcConfig"); ok {
+		config = raw
+	}
+	return config, nil
 }
 
 // getRBACPolicy will return the RBAC policy for the given ArgoCD.
-func getRBACPolicy(cr *argoprojv1a1.ArgoCD) string {
+func getRBACPolicy(cr *argoprojv1b1.ArgoCD) string {
 	policy := common.ArgoCDDefaultRBACPolicy
 	if cr.Spec.RBAC.Policy != nil {
 		policy = *cr.Spec.RBAC.Policy
@@ -133,7 +168,7 @@ func getRBACPolicy(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getRBACDefaultPolicy will retun the RBAC default policy for the given ArgoCD.
-func getRBACDefaultPolicy(cr *argoprojv1a1.ArgoCD) string {
+func getRBACDefaultPolicy(cr *argoprojv1b1.ArgoCD) string {
 	dp := common.ArgoCDDefaultRBACDefaultPolicy
 	if cr.Spec.RBAC.DefaultPolicy != nil {
 		dp = *cr.Spec.RBAC.DefaultPolicy
@@ -142,7 +177,7 @@ func getRBACDefaultPolicy(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getRBACScopes will return the RBAC scopes for the given ArgoCD.
-func getRBACScopes(cr *argoprojv1a1.ArgoCD) string {
+func getRBACScopes(cr *argoprojv1b1.ArgoCD) string {
 	scopes := common.ArgoCDDefaultRBACScopes
 	if cr.Spec.RBAC.Scopes != nil {
 		scopes = *cr.Spec.RBAC.Scopes
@@ -151,52 +186,67 @@ func getRBACScopes(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getResourceCustomizations will return the resource customizations for the given ArgoCD.
-func getResourceCustomizations(cr *argoprojv1a1.ArgoCD) string {
+func getResourceCustomizations(cr *argoprojv1b1.ArgoCD) (string, error) {
 	rc := common.ArgoCDDefaultResourceCustomizations
-	if cr.Spec.ResourceCustomizations != "" {
-		rc = cr.Spec.ResourceCustomizations
+	if len(cr.Spec.ResourceCustomizations) > 0 {
+		return marshalConfig(common.ArgoCDKeyResourceCustomizations, cr.Spec.ResourceCustomizations)
+	}
+	if raw, ok := argoprojv1a1.GetUnparsedStringField(cr, "resourceCustomizations"); ok {
+		rc = raw
 	}
-	return rc
+	return rc, nil
 }
 
 // getResourceExclusions will return the resource exclusions for the given ArgoCD.
-func getResourceExclusions(cr *argoprojv1a1.ArgoCD) string {
+func getResourceExclusions(cr *argoprojv1b1.ArgoCD) (string, error) {
 	re := common.ArgoCDDefaultResourceExclusions
-	if cr.Spec.ResourceExclusions != "" {
-		re = cr.Spec.ResourceExclusions
+	if len(cr.Spec.ResourceExclusions) > 0 {
+		return marshalConfig(common.ArgoCDKeyResourceExclusions, cr.Spec.ResourceExclusions)
 	}
-	return re
+	if raw, ok := argoprojv1a1.GetUnparsedStringField(cr, "resourceExclusions"); ok {
+		re = raw
+	}
+	return re, nil
 }
 
 // getResourceInclusions will return the resource inclusions for the given ArgoCD.
-func getResourceInclusions(cr *argoprojv1a1.ArgoCD) string {
+func getResourceInclusions(cr *argoprojv1b1.ArgoCD) (string, error) {
 	re := common.ArgoCDDefaultResourceInclusions
-	if cr.Spec.ResourceInclusions != "" {
-		re = cr.Spec.ResourceInclusions
+	if len(cr.Spec.ResourceInclusions) > 0 {
+		return marshalConfig(common.ArgoCDKeyResourceInclusions, cr.Spec.ResourceInclusions)
+	}
+	if raw, ok := argoprojv1a1.GetUnparsedStringField(cr, "resourceInclusions"); ok {
+		re = raw
 	}
-	return re
+	return re, nil
 }
 
 // getInitialRepositories will return the initial repositories for the given ArgoCD.
-func getInitialRepositories(cr *argoprojv1a1.ArgoCD) string {
+func getInitialRepositories(cr *argoprojv1b1.ArgoCD) (string, error) {
 	repos := common.ArgoCDDefaultRepositories
 	if len(cr.Spec.InitialRepositories) > 0 {
-		repos = cr.Spec.InitialRepositories
+		return marshalConfig(common.ArgoCDKeyRepositories, cr.Spec.InitialRepositories)
 	}
-	return repos
+	if raw, ok := argoprojv1a1.GetUnparsedStringField(cr, "initialRepositories"); ok {
+		repos = raw
+	}
+	return repos, nil
 }
 
 // getRepositoryCredentials will return the repository credentials for the given ArgoCD.
-func getRepositoryCredentials(cr *argoprojv1a1.ArgoCD) string {
+func getRepositoryCredentials(cr *argoprojv1b1.ArgoCD) (string, error) {
 	repos := common.ArgoCDDefaultRepositoryCredentials
 	if len(cr.Spec.RepositoryCredentials) > 0 {
-		repos = cr.Spec.RepositoryCredentials
+		return marshalConfig(common.ArgoCDKeyRepositoryCredentials, cr.Spec.RepositoryCredentials)
+	}
+	if raw, ok := argoprojv1a1.GetUnparsedStringField(cr, "repositoryCredentials"); ok {
+		repos = raw
 	}
-	return repos
+	return repos, nil
 }
 
 // getSSHKnownHosts will return the SSH Known Hosts data for the given ArgoCD.
-func getInitialSSHKnownHosts(cr *argoprojv1a1.ArgoCD) string {
+func getInitialSSHKnownHosts(cr *argoprojv1b1.ArgoCD) string {
 	skh := common.ArgoCDDefaultSSHKnownHosts
 	if cr.Spec.InitialSSHKnownHosts.ExcludeDefaultHosts {
 		skh = ""
@@ -208,7 +258,7 @@ func getInitialSSHKnownHosts(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getTLSCerts will return the TLS certs for the given ArgoCD.
-func getInitialTLSCerts(cr *argoprojv1a1.ArgoCD) map[string]string {
+func getInitialTLSCerts(cr *argoprojv1b1.ArgoCD) map[string]string {
 	certs := make(map[string]string)
 	if len(cr.Spec.TLS.InitialCerts) > 0 {
 		certs = cr.Spec.TLS.InitialCerts
@@ -216,8 +266,17 @@ func getInitialTLSCerts(cr *argoprojv1a1.ArgoCD) map[string]string {
 	return certs
 }
 
+// marshalConfig will return the given configuration value as YAML, the format expected in the Argo CD ConfigMap.
+func marshalConfig(key string, value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal configuration for key [%s]: %w", key, err)
+	}
+	return string(data), nil
+}
+
 // newConfigMap returns a new ConfigMap instance for the given ArgoCD.
-func newConfigMap(cr *argoprojv1a1.ArgoCD) *corev1.ConfigMap {
+func newConfigMap(cr *argoprojv1b1.ArgoCD) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -228,7 +287,7 @@ func newConfigMap(cr *argoprojv1a1.ArgoCD) *corev1.ConfigMap {
 }
 
 // newConfigMapWithName creates a new ConfigMap with the given name for the given ArgCD.
-func newConfigMapWithName(name string, cr *argoprojv1a1.ArgoCD) *corev1.ConfigMap {
+func newConfigMapWithName(name string, cr *argoprojv1b1.ArgoCD) *corev1.ConfigMap {
 	cm := newConfigMap(cr)
 	cm.ObjectMeta.Name = name
 
@@ -241,50 +300,15 @@ func newConfigMapWithName(name string, cr *argoprojv1a1.ArgoCD) *corev1.ConfigMa
 
 // newConfigMapWithName creates a new ConfigMap with the given suffix appended to the name.
 // The name for the CongifMap is based on the name of the given ArgCD.
-func newConfigMapWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *corev1.ConfigMap {
+func newConfigMapWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) *corev1.ConfigMap {
 	return newConfigMapWithName(fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, suffix), cr)
 }
 
-// reconcileConfigMaps will ensure that all ArgoCD ConfigMaps are present.
-func (r *ReconcileArgoCD) reconcileConfigMaps(cr *argoprojv1a1.ArgoCD) error {
-	if err := r.reconcileArgoConfigMap(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileRedisConfiguration(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileRBAC(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileSSHKnownHosts(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileTLSCerts(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileGrafanaConfiguration(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileGrafanaDashboards(cr); err != nil {
-		return err
-	}
-
-	return r.reconcileGPGKeysConfigMap(cr)
-}
-
 // reconcileCAConfigMap will ensure that the Certificate Authority ConfigMap is present.
-// This ConfigMap holds the CA Certificate data for client use.
-func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
+// This ConfigMap holds the CA Certificate data for client use, the bundle is updated when the CA certificate is
+// renewed.
+func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1b1.ArgoCD) error {
 	cm := newConfigMapWithName(getCAConfigMapName(cr), cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
-		return nil // ConfigMap found, do nothing
-	}
 
 	caSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, common.ArgoCDCASuffix)
 	if !argoutil.IsObjectFound(r.client, cr.Namespace, caSecret.Name, caSecret) {
@@ -292,8 +316,21 @@ func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 		return nil
 	}
 
+	bundle := string(getCABundle(caSecret))
+	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		if cm.Data[common.ArgoCDKeyTLSCert] == bundle {
+			return nil // ConfigMap up to date, do nothing
+		}
+		log.Info(fmt.Sprintf("ca bundle changed, updating ca configmap [%s]", cm.Name))
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[common.ArgoCDKeyTLSCert] = bundle
+		return r.client.Update(context.TODO(), cm)
+	}
+
 	cm.Data = map[string]string{
-		common.ArgoCDKeyTLSCert: string(caSecret.Data[common.ArgoCDKeyTLSCert]),
+		common.ArgoCDKeyTLSCert: bundle,
 	}
 
 	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
@@ -303,7 +340,7 @@ func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 }
 
 // reconcileConfiguration will ensure that the main ConfigMap for ArgoCD is present.
-func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1b1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
 		if err := r.reconcileDexConfiguration(cm, cr); err != nil {
@@ -316,34 +353,59 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 		cm.Data = make(map[string]string)
 	}
 
+	plugins, err := getConfigManagementPlugins(cr)
+	if err != nil {
+		return err
+	}
+	oidcConfig, err := getOIDCConfig(cr)
+	if err != nil {
+		return err
+	}
+	rc, err := getResourceCustomizations(cr)
+	if err != nil {
+		return err
+	}
+	re, err := getResourceExclusions(cr)
+	if err != nil {
+		return err
+	}
+	ri, err := getResourceInclusions(cr)
+	if err != nil {
+		return err
+	}
+	repos, err := getInitialRepositories(cr)
+	if err != nil {
+		return err
+	}
+	creds, err := getRepositoryCredentials(cr)
+	if err != nil {
+		return err
+	}
+
 	cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] = getApplicationInstanceLabelKey(cr)
-	cm.Data[common.ArgoCDKeyConfigManagementPlugins] = getConfigManagementPlugins(cr)
+	cm.Data[common.ArgoCDKeyConfigManagementPlugins] = plugins
 	cm.Data[common.ArgoCDKeyAdminEnabled] = fmt.Sprintf("%t", !cr.Spec.DisableAdmin)
 	cm.Data[common.ArgoCDKeyGATrackingID] = getGATrackingID(cr)
 	cm.Data[common.ArgoCDKeyGAAnonymizeUsers] = fmt.Sprint(cr.Spec.GAAnonymizeUsers)
 	cm.Data[common.ArgoCDKeyHelpChatURL] = getHelpChatURL(cr)
 	cm.Data[common.ArgoCDKeyHelpChatText] = getHelpChatText(cr)
 	cm.Data[common.ArgoCDKeyKustomizeBuildOptions] = getKustomizeBuildOptions(cr)
-	cm.Data[common.ArgoCDKeyOIDCConfig] = getOIDCConfig(cr)
-	if c := getResourceCustomizations(cr); c != "" {
-		cm.Data[common.ArgoCDKeyResourceCustomizations] = c
-	}
-	cm.Data[common.ArgoCDKeyResourceExclusions] = getResourceExclusions(cr)
-	cm.Data[common.ArgoCDKeyResourceInclusions] = getResourceInclusions(cr)
-	cm.Data[common.ArgoCDKeyRepositories] = getInitialRepositories(cr)
-	cm.Data[common.ArgoCDKeyRepositoryCredentials] = getRepositoryCredentials(cr)
+	cm.Data[common.ArgoCDKeyOIDCConfig] = oidcConfig
+	if rc != "" {
+		cm.Data[common.ArgoCDKeyResourceCustomizations] = rc
+	}
+	cm.Data[common.ArgoCDKeyResourceExclusions] = re
+	cm.Data[common.ArgoCDKeyResourceInclusions] = ri
+	cm.Data[common.ArgoCDKeyRepositories] = repos
+	cm.Data[common.ArgoCDKeyRepositoryCredentials] = creds
 	cm.Data[common.ArgoCDKeyStatusBadgeEnabled] = fmt.Sprint(cr.Spec.StatusBadgeEnabled)
 	cm.Data[common.ArgoCDKeyServerURL] = r.getArgoServerURI(cr)
 	cm.Data[common.ArgoCDKeyUsersAnonymousEnabled] = fmt.Sprint(cr.Spec.UsersAnonymousEnabled)
 
 	if !isDexDisabled() {
-		dexConfig := getDexConfig(cr)
-		if dexConfig == "" && cr.Spec.Dex.OpenShiftOAuth {
-			cfg, err := r.getOpenShiftDexConfig(cr)
-			if err != nil {
-				return err
-			}
-			dexConfig = cfg
+		dexConfig, err := r.getDexConfig(cr)
+		if err != nil {
+			return err
 		}
 		cm.Data[common.ArgoCDKeyDexConfig] = dexConfig
 	}
@@ -355,15 +417,11 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 }
 
 // reconcileDexConfiguration will ensure that Dex is configured properly.
-func (r *ReconcileArgoCD) reconcileDexConfiguration(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileDexConfiguration(cm *corev1.ConfigMap, cr *argoprojv1b1.ArgoCD) error {
 	actual := cm.Data[common.ArgoCDKeyDexConfig]
-	desired := getDexConfig(cr)
-	if len(desired) <= 0 && cr.Spec.Dex.OpenShiftOAuth {
-		cfg, err := r.getOpenShiftDexConfig(cr)
-		if err != nil {
-			return err
-		}
-		desired = cfg
+	desired, err := r.getDexConfig(cr)
+	if err != nil {
+		return err
 	}
 
 	if actual != desired {
@@ -386,7 +444,7 @@ func (r *ReconcileArgoCD) reconcileDexConfiguration(cm *corev1.ConfigMap, cr *ar
 	return nil
 }
 
-func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, cr *argoprojv1b1.ArgoCD) error {
 	changed := false
 
 	if cm.Data[common.ArgoCDKeyAdminEnabled] == fmt.Sprintf("%t", cr.Spec.DisableAdmin) {
@@ -399,8 +457,12 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 		changed = true
 	}
 
-	if cm.Data[common.ArgoCDKeyConfigManagementPlugins] != cr.Spec.ConfigManagementPlugins {
-		cm.Data[common.ArgoCDKeyConfigManagementPlugins] = cr.Spec.ConfigManagementPlugins
+	plugins, err := getConfigManagementPlugins(cr)
+	if err != nil {
+		return err
+	}
+	if cm.Data[common.ArgoCDKeyConfigManagementPlugins] != plugins {
+		cm.Data[common.ArgoCDKeyConfigManagementPlugins] = plugins
 		changed = true
 	}
 
@@ -430,19 +492,49 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 	}
 
 	if cr.Spec.SSO == nil {
-		if cm.Data[common.ArgoCDKeyOIDCConfig] != cr.Spec.OIDCConfig {
-			cm.Data[common.ArgoCDKeyOIDCConfig] = cr.Spec.OIDCConfig
+		config, err := getOIDCConfig(cr)
+		if err != nil {
+			return err
+		}
+		if cm.Data[common.ArgoCDKeyOIDCConfig] != config {
+			cm.Data[common.ArgoCDKeyOIDCConfig] = config
 			changed = true
 		}
 	}
 
-	if cm.Data[common.ArgoCDKeyResourceCustomizations] != cr.Spec.ResourceCustomizations {
-		cm.Data[common.ArgoCDKeyResourceCustomizations] = cr.Spec.ResourceCustomizations
+	rc, err := getResourceCustomizations(cr)
+	if err != nil {
+		return err
+	}
+	if cm.Data[common.ArgoCDKeyResourceCustomizations] != rc {
+		cm.Data[common.ArgoCDKeyResourceCustomizations] = rc
 		changed = true
 	}
 
-	if cm.Data[common.ArgoCDKeyResourceExclusions] != cr.Spec.ResourceExclusions {
-		cm.Data[common.ArgoCDKeyResourceExclusions] = cr.Spec.ResourceExclusions
+	re, err := getResourceExclusions(cr)
+	if err != nil {
+		return err
+	}
+	if cm.Data[common.ArgoCDKeyResourceExclusions] != re {
+		cm.Data[common.ArgoCDKeyResourceExclusions] = re
+		changed = true
+	}
+
+	ri, err := getResourceInclusions(cr)
+	if err != nil {
+		return err
+	}
+	if cm.Data[common.ArgoCDKeyResourceInclusions] != ri {
+		cm.Data[common.ArgoCDKeyResourceInclusions] = ri
+		changed = true
+	}
+
+	repos, err := getInitialRepositories(cr)
+	if err != nil {
+		return err
+	}
+	if cm.Data[common.ArgoCDKeyRepositories] != repos {
+		cm.Data[common.ArgoCDKeyRepositories] = repos
 		changed = true
 	}
 
@@ -462,8 +554,12 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 		changed = true
 	}
 
-	if cm.Data[common.ArgoCDKeyRepositoryCredentials] != cr.Spec.RepositoryCredentials {
-		cm.Data[common.ArgoCDKeyRepositoryCredentials] = cr.Spec.RepositoryCredentials
+	creds, err := getRepositoryCredentials(cr)
+	if err != nil {
+		return err
+	}
+	if cm.Data[common.ArgoCDKeyRepositoryCredentials] != creds {
+		cm.Data[common.ArgoCDKeyRepositoryCredentials] = creds
 		changed = true
 	}
 
@@ -475,7 +571,7 @@ func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, c
 }
 
 // reconcileGrafanaConfiguration will ensure that the Grafana configuration ConfigMap is present.
-func (r *ReconcileArgoCD) reconcileGrafanaConfiguration(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGrafanaConfiguration(cr *argoprojv1b1.ArgoCD) error {
 	if !cr.Spec.Grafana.Enabled {
 		return nil // Grafana not enabled, do nothing.
 	}
@@ -521,7 +617,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaConfiguration(cr *argoprojv1a1.ArgoCD)
 }
 
 // reconcileGrafanaDashboards will ensure that the Grafana dashboards ConfigMap is present.
-func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1b1.ArgoCD) error {
 	if !cr.Spec.Grafana.Enabled {
 		return nil // Grafana not enabled, do nothing.
 	}
@@ -557,7 +653,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1a1.ArgoCD) er
 }
 
 // reconcileRBAC will ensure that the ArgoCD RBAC ConfigMap is present.
-func (r *ReconcileArgoCD) reconcileRBAC(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRBAC(cr *argoprojv1b1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDRBACConfigMapName, cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
 		return r.reconcileRBACConfigMap(cm, cr)
@@ -566,12 +662,18 @@ func (r *ReconcileArgoCD) reconcileRBAC(cr *argoprojv1a1.ArgoCD) error {
 }
 
 // reconcileRBACConfigMap will ensure that the RBAC ConfigMap is syncronized with the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileRBACConfigMap(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRBACConfigMap(cm *corev1.ConfigMap, cr *argoprojv1b1.ArgoCD) error {
 	changed := false
 	// Policy CSV
-	if cr.Spec.RBAC.Policy != nil && cm.Data[common.ArgoCDKeyRBACPolicyCSV] != *cr.Spec.RBAC.Policy {
-		cm.Data[common.ArgoCDKeyRBACPolicyCSV] = *cr.Spec.RBAC.Policy
-		changed = true
+	if isRBACPolicyManaged(cr) {
+		policy, err := r.getRBACPolicyCSV(cr)
+		if err != nil {
+			return err
+		}
+		if cm.Data[common.ArgoCDKeyRBACPolicyCSV] != policy {
+			cm.Data[common.ArgoCDKeyRBACPolicyCSV] = policy
+			changed = true
+		}
 	}
 
 	// Default Policy
@@ -594,44 +696,72 @@ func (r *ReconcileArgoCD) reconcileRBACConfigMap(cm *corev1.ConfigMap, cr *argop
 }
 
 // reconcileRedisConfiguration will ensure that all of the Redis ConfigMaps are present for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileRedisConfiguration(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisConfiguration(cr *argoprojv1b1.ArgoCD) error {
 	if err := r.reconcileRedisHAConfigMap(cr); err != nil {
 		return err
 	}
 	return nil
 }
 
-// reconcileRedisHAConfigMap will ensure that the Redis HA ConfigMap is present for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileRedisHAConfigMap(cr *argoprojv1a1.ArgoCD) error {
+// reconcileRedisHAConfigMap will ensure that the Redis HA ConfigMap is present for the given ArgoCD. The ConfigMap is
+// updated when the Redis authentication or TLS options change.
+func (r *ReconcileArgoCD) reconcileRedisHAConfigMap(cr *argoprojv1b1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDRedisHAConfigMapName, cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
-		if !cr.Spec.HA.Enabled {
+	if !cr.Spec.HA.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
 			// ConfigMap exists but HA enabled flag has been set to false, delete the ConfigMap
 			return r.client.Delete(context.TODO(), cm)
 		}
-		return nil // ConfigMap found with nothing changed, move along...
+		return nil // HA not enabled, do nothing.
 	}
 
-	if !cr.Spec.HA.Enabled {
-		return nil // HA not enabled, do nothing.
+	data := getRedisHAConfigMapData(cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		if reflect.DeepEqual(cm.Data, data) {
+			return nil // ConfigMap found with nothing changed, move along...
+		}
+		cm.Data = data
+		return r.client.Update(context.TODO(), cm)
 	}
 
-	cm.Data = map[string]string{
+	cm.Data = data
+
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cm)
+}
+
+// getRedisHAConfigMapData will return the data of the Redis HA ConfigMap for the given ArgoCD.
+func getRedisHAConfigMapData(cr *argoprojv1b1.ArgoCD) map[string]string {
+	return map[string]string{
 		"haproxy.cfg":     getRedisHAProxyConfig(cr),
 		"haproxy_init.sh": getRedisHAProxyScript(cr),
 		"init.sh":         getRedisInitScript(cr),
 		"redis.conf":      getRedisConf(cr),
 		"sentinel.conf":   getRedisSentinelConf(cr),
 	}
+}
 
-	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
-		return err
+// getRedisHAConfigChecksum will return the SHA256 checksum of the data of the Redis HA ConfigMap for the given ArgoCD.
+// It is set on the Redis HA and HAProxy pods, so that they are rolled out when the configuration changes.
+func getRedisHAConfigChecksum(cr *argoprojv1b1.ArgoCD) string {
+	data := getRedisHAConfigMapData(cr)
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
 	}
-	return r.client.Create(context.TODO(), cm)
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", key, data[key])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
 // reconcileSSHKnownHosts will ensure that the ArgoCD SSH Known Hosts ConfigMap is present.
-func (r *ReconcileArgoCD) reconcileSSHKnownHosts(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileSSHKnownHosts(cr *argoprojv1b1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDKnownHostsConfigMapName, cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
 		return nil // ConfigMap found, move along...
@@ -648,7 +778,7 @@ func (r *ReconcileArgoCD) reconcileSSHKnownHosts(cr *argoprojv1a1.ArgoCD) error
 }
 
 // reconcileTLSCerts will ensure that the ArgoCD TLS Certs ConfigMap is present.
-func (r *ReconcileArgoCD) reconcileTLSCerts(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileTLSCerts(cr *argoprojv1b1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDTLSCertsConfigMapName, cr)
 	cm.Data = getInitialTLSCerts(cr)
 	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
@@ -662,7 +792,7 @@ func (r *ReconcileArgoCD) reconcileTLSCerts(cr *argoprojv1a1.ArgoCD) error {
 }
 
 // reconcileGPGKeysConfigMap creates a gpg-keys config map
-func (r *ReconcileArgoCD) reconcileGPGKeysConfigMap(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGPGKeysConfigMap(cr *argoprojv1b1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDGPGKeysConfigMapName, cr)
 	if argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
 		return nil
@@ -21,7 +21,7 @@ import (
 	"reflect"
 	"testing"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	"github.com/google/go-cmp/cmp"
@@ -29,6 +29,7 @@ import (
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
@@ -82,6 +83,26 @@ func TestReconcileArgoCD_reconcileTLSCerts_withUpdate(t *testing.T) {
 	}
 }
 
+func TestReconcileArgoCD_reconcileCAConfigMap_withRenewedCA(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileCertificateAuthority(a))
+
+	caSecret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ca", Namespace: a.Namespace}, caSecret))
+	bundle := append(append([]byte{}, caSecret.Data[corev1.TLSCertKey]...), caSecret.Data[corev1.TLSCertKey]...)
+	caSecret.Data[corev1.ServiceAccountRootCAKey] = bundle
+	assert.NilError(t, r.client.Update(context.TODO(), caSecret))
+
+	assert.NilError(t, r.reconcileCAConfigMap(a))
+
+	configMap := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: getCAConfigMapName(a), Namespace: a.Namespace}, configMap))
+	assert.Equal(t, configMap.Data[common.ArgoCDKeyTLSCert], string(bundle))
+}
+
 func TestReconcileArgoCD_reconcileArgoConfigMap(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -125,14 +146,25 @@ func TestReconcileArgoCD_reconcileArgoConfigMap(t *testing.T) {
 func TestReconcileArgoCDCM_withRepoCredentials(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
-	a.Spec.RepositoryCredentials = `
-- url: https://github.com/test/gitops.git
-  passwordSecret:
-    name: test
+	a.Spec.RepositoryCredentials = []argoprojv1beta1.ArgoCDRepositorySpec{{
+		URL: "https://github.com/test/gitops.git",
+		PasswordSecret: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "test"},
+			Key:                  "password",
+		},
+		UsernameSecret: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "test"},
+			Key:                  "username",
+		},
+	}}
+	want := `- passwordSecret:
     key: password
+    name: test
+  url: https://github.com/test/gitops.git
   usernameSecret:
+    key: username
     name: test
-    key: username`
+`
 
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -155,14 +187,14 @@ func TestReconcileArgoCDCM_withRepoCredentials(t *testing.T) {
 	}, cm)
 	assert.NilError(t, err)
 
-	if got := cm.Data[common.ArgoCDKeyRepositoryCredentials]; got != a.Spec.RepositoryCredentials {
-		t.Fatalf("reconcileArgoConfigMap failed: got %s, want %s", got, a.Spec.RepositoryCredentials)
+	if got := cm.Data[common.ArgoCDKeyRepositoryCredentials]; got != want {
+		t.Fatalf("reconcileArgoConfigMap failed: got %s, want %s", got, want)
 	}
 }
 
 func TestReconcileArgoCD_reconcileArgoConfigMap_withDisableAdmin(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.DisableAdmin = true
 	})
 	r := makeTestReconciler(t, a)
@@ -185,7 +217,7 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withDisableAdmin(t *testing.T) {
 func TestReconcileArgoCD_reconcileArgoConfigMap_withDexConnector(t *testing.T) {
 	restoreEnv(t)
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Dex.OpenShiftOAuth = true
 	})
 	sa := &corev1.ServiceAccount{
@@ -226,6 +258,76 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withDexConnector(t *testing.T) {
 	assert.Equal(t, config.(map[interface{}]interface{})["clientID"], "system:serviceaccount:argocd:argocd-argocd-dex-server")
 }
 
+func TestReconcileArgoCD_reconcileArgoConfigMap_withDexOptions(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Dex.Config = &argoprojv1beta1.ArgoCDDexConfig{
+			Options: map[string]runtime.RawExtension{
+				"logger":        {Raw: []byte(`{"level":"debug"}`)},
+				"staticClients": {Raw: []byte(`[{"id":"example-app","name":"Example App"}]`)},
+			},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm)
+	assert.NilError(t, err)
+
+	want := "logger:\n  level: debug\nstaticClients:\n- id: example-app\n  name: Example App\n"
+	if c := cm.Data["dex.config"]; c != want {
+		t.Fatalf("reconcileArgoConfigMap failed got %q, want %q", c, want)
+	}
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withUnparsedString(t *testing.T) {
+	restoreEnv(t)
+	logf.SetLogger(logf.ZapLogger(true))
+	dexConfig := "connectors:\n- type: github\n  id: github\n  name: GitHub\n  clientID: foo\n"
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Annotations = map[string]string{
+			"argocd.argoproj.io/v1alpha1.dex.config":         dexConfig,
+			"argocd.argoproj.io/v1alpha1.resourceInclusions": "testing: testing",
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm)
+	assert.NilError(t, err)
+	assert.Equal(t, cm.Data["dex.config"], dexConfig)
+	assert.Equal(t, cm.Data["resource.inclusions"], "testing: testing")
+
+	// The original strings are kept on the following reconciles.
+	assert.NilError(t, r.reconcileArgoConfigMap(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      common.ArgoCDConfigMapName,
+		Namespace: testNamespace,
+	}, cm))
+	assert.Equal(t, cm.Data["dex.config"], dexConfig)
+	assert.Equal(t, cm.Data["resource.inclusions"], "testing: testing")
+}
+
+func TestMarshalConfig(t *testing.T) {
+	config, err := marshalConfig(common.ArgoCDKeyResourceInclusions, []string{"Deployment"})
+	assert.NilError(t, err)
+	assert.Equal(t, config, "- Deployment\n")
+
+	_, err = marshalConfig(common.ArgoCDKeyResourceInclusions, make(chan int))
+	assert.ErrorContains(t, err, "unable to marshal configuration for key [resource.inclusions]")
+}
+
 func TestReconcileArgoCD_reconcileArgoConfigMap_withDexDisabled(t *testing.T) {
 	restoreEnv(t)
 	logf.SetLogger(logf.ZapLogger(true))
@@ -250,7 +352,7 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withDexDisabled(t *testing.T) {
 
 func TestReconcileArgoCD_reconcileGPGKeysConfigMap(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.DisableAdmin = true
 	})
 	r := makeTestReconciler(t, a)
@@ -269,9 +371,13 @@ func TestReconcileArgoCD_reconcileGPGKeysConfigMap(t *testing.T) {
 
 func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceInclusions(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
-	customizations := "testing: testing"
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
-		a.Spec.ResourceInclusions = customizations
+	customizations := "- apiGroups:\n  - '*'\n  clusters:\n  - https://kubernetes.default.svc\n  kinds:\n  - '*'\n"
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.ResourceInclusions = []argoprojv1beta1.ArgoCDFilteredResource{{
+			APIGroups: []string{"*"},
+			Kinds:     []string{"*"},
+			Clusters:  []string{"https://kubernetes.default.svc"},
+		}}
 	})
 	r := makeTestReconciler(t, a)
 
@@ -292,9 +398,11 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceInclusions(t *testin
 
 func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceCustomizations(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
-	customizations := "testing: testing"
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
-		a.Spec.ResourceCustomizations = customizations
+	customizations := "apps/Deployment:\n  health.lua: return {}\n"
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.ResourceCustomizations = map[string]argoprojv1beta1.ArgoCDResourceCustomization{
+			"apps/Deployment": {HealthLua: "return {}"},
+		}
 	})
 	r := makeTestReconciler(t, a)
 
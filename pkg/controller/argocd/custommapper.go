@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"strings"
 
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func (r *ReconcileArgoCD) clusterResourceMapper(o handler.MapObject) []reconcile.Request {
@@ -39,8 +42,8 @@ func (r *ReconcileArgoCD) clusterResourceMapper(o handler.MapObject) []reconcile
 func (r *ReconcileArgoCD) tlsSecretMapper(o handler.MapObject) []reconcile.Request {
 	var result = []reconcile.Request{}
 
-	// The secret must end with '-repo-server-tls'
-	if !strings.HasSuffix(o.Meta.GetName(), "-repo-server-tls") {
+	// The secret must end with '-repo-server-tls' or be the Redis TLS secret
+	if !strings.HasSuffix(o.Meta.GetName(), "-repo-server-tls") && o.Meta.GetName() != common.ArgoCDRedisServerTLSSecretName {
 		return result
 	}
 	namespacedArgoCDObject := client.ObjectKey{}
@@ -96,3 +99,107 @@ func (r *ReconcileArgoCD) tlsSecretMapper(o handler.MapObject) []reconcile.Reque
 
 	return result
 }
+
+// isSecretReferenced returns true when the given ArgoCD reads the admin
+// password, the secrets of a Dex connector or the credentials of a repository
+// from the named secret.
+func isSecretReferenced(cr *argoprojv1b1.ArgoCD, name string) bool {
+	if isAdminPasswordFromSecret(cr) && cr.Spec.AdminPassword.SecretRef.Name == name {
+		return true
+	}
+	for i := range cr.Spec.Dex.Connectors {
+		_, _, secrets := getDexConnectorOptions(&cr.Spec.Dex.Connectors[i])
+		for _, ref := range secrets {
+			if ref.Name == name {
+				return true
+			}
+		}
+	}
+	for _, repos := range [][]argoprojv1b1.ArgoCDRepositorySpec{cr.Spec.Repositories, cr.Spec.RepositoryCredentialTemplates} {
+		for i := range repos {
+			for _, ref := range getRepositorySecretRefs(&repos[i]) {
+				if ref.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// secretReferenceMapper maps a watch event on a secret back to the ArgoCD
+// objects in the same namespace that reference it.
+func (r *ReconcileArgoCD) secretReferenceMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1b1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		log.Error(err, fmt.Sprintf("could not list argocds in namespace %s", o.Meta.GetNamespace()))
+		return result
+	}
+
+	for i := range argocds.Items {
+		cr := &argocds.Items[i]
+		if isSecretReferenced(cr, o.Meta.GetName()) {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: cr.Name, Namespace: cr.Namespace},
+			})
+		}
+	}
+	return result
+}
+
+// rbacPolicyConfigMapMapper maps a watch event on a configmap back to the
+// ArgoCD objects in the same namespace that select it for RBAC policy fragments.
+func (r *ReconcileArgoCD) rbacPolicyConfigMapMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1b1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds, &client.ListOptions{Namespace: o.Meta.GetNamespace()}); err != nil {
+		log.Error(err, fmt.Sprintf("could not list argocds in namespace %s", o.Meta.GetNamespace()))
+		return result
+	}
+
+	for i := range argocds.Items {
+		cr := &argocds.Items[i]
+		if cr.Spec.RBAC.PolicyConfigMapSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(cr.Spec.RBAC.PolicyConfigMapSelector)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("invalid rbac policy configmap selector for argocd %s", cr.Name))
+			continue
+		}
+		if selector.Matches(labels.Set(o.Meta.GetLabels())) {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: cr.Name, Namespace: cr.Namespace},
+			})
+		}
+	}
+	return result
+}
+
+// groupMappingMapper maps a watch event on an OpenShift group back to the
+// ArgoCD objects that map it to an RBAC role.
+func (r *ReconcileArgoCD) groupMappingMapper(o handler.MapObject) []reconcile.Request {
+	var result = []reconcile.Request{}
+
+	argocds := &argoprojv1b1.ArgoCDList{}
+	if err := r.client.List(context.TODO(), argocds); err != nil {
+		log.Error(err, "could not list argocds")
+		return result
+	}
+
+	for i := range argocds.Items {
+		cr := &argocds.Items[i]
+		for j := range cr.Spec.RBAC.GroupMappings {
+			if isRBACGroupMapped(&cr.Spec.RBAC.GroupMappings[j], o.Meta) {
+				result = append(result, reconcile.Request{
+					NamespacedName: client.ObjectKey{Name: cr.Name, Namespace: cr.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return result
+}
@@ -1,11 +1,13 @@
 package argocd
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
-	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"gotest.tools/assert"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -107,7 +109,7 @@ func TestReconcileArgoCD_clusterRoleBindingMapper(t *testing.T) {
 }
 
 func TestReconcileArgoCD_tlsSecretMapper(t *testing.T) {
-	argocd := &v1alpha1.ArgoCD{
+	argocd := &v1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "argocd",
 			Namespace: "argocd-operator",
@@ -314,6 +316,43 @@ func TestReconcileArgoCD_tlsSecretMapper(t *testing.T) {
 		}
 	})
 
+	t.Run("Map Redis TLS secret with owner annotation", func(t *testing.T) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.ArgoCDRedisServerTLSSecretName,
+				Namespace: "argocd-operator",
+				Annotations: map[string]string{
+					common.AnnotationName: "argocd",
+				},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       []byte("foo"),
+				corev1.TLSPrivateKeyKey: []byte("bar"),
+			},
+		}
+		o := handler.MapObject{
+			Meta:   secret,
+			Object: secret,
+		}
+		objs := []runtime.Object{
+			secret,
+		}
+		r := makeReconciler(t, argocd, objs...)
+		want := []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Name:      "argocd",
+					Namespace: "argocd-operator",
+				},
+			},
+		}
+		got := r.tlsSecretMapper(o)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Reconciliation unsucessful: got: %v, want: %v", got, want)
+		}
+	})
+
 	t.Run("Map without owner and without annotation", func(t *testing.T) {
 		secret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -342,3 +381,127 @@ func TestReconcileArgoCD_tlsSecretMapper(t *testing.T) {
 	})
 
 }
+
+func TestReconcileArgoCD_secretReferenceMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1beta1.ArgoCD) {
+		a.Spec.AdminPassword = &v1beta1.ArgoCDAdminPasswordSpec{
+			SecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "admin-password"},
+				Key:                  "password",
+			},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "admin-password", Namespace: testNamespace}}
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}},
+	}
+	got := r.secretReferenceMapper(handler.MapObject{Meta: secret, Object: secret})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconciliation unsucessful: got: %v, want: %v", got, want)
+	}
+
+	// The secrets of the typed Dex connectors are referenced as well.
+	a.Spec.Dex.Connectors = []v1beta1.ArgoCDDexConnectorSpec{{
+		ID:   "github",
+		Name: "GitHub",
+		GitHub: &v1beta1.ArgoCDDexGitHubConnectorSpec{
+			ClientID: "client-id",
+			ClientSecretRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "github-oauth"},
+				Key:                  "clientSecret",
+			},
+		},
+	}}
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	github := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "github-oauth", Namespace: testNamespace}}
+	got = r.secretReferenceMapper(handler.MapObject{Meta: github, Object: github})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconciliation unsucessful: got: %v, want: %v", got, want)
+	}
+
+	// The credentials of the repositories and credential templates are referenced as well.
+	a.Spec.RepositoryCredentialTemplates = []v1beta1.ArgoCDRepositorySpec{{
+		URL: "https://github.com/argoproj",
+		GitHubApp: &v1beta1.ArgoCDRepositoryGitHubAppSpec{
+			ID:             1,
+			InstallationID: 2,
+			PrivateKeySecret: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "github-app"},
+				Key:                  "privateKey",
+			},
+		},
+	}}
+	assert.NilError(t, r.client.Update(context.TODO(), a))
+	app := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "github-app", Namespace: testNamespace}}
+	got = r.secretReferenceMapper(handler.MapObject{Meta: app, Object: app})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconciliation unsucessful: got: %v, want: %v", got, want)
+	}
+
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNamespace}}
+	got = r.secretReferenceMapper(handler.MapObject{Meta: other, Object: other})
+	if len(got) != 0 {
+		t.Errorf("Reconciliation unsucessful: got: %v, want no requests", got)
+	}
+}
+
+func TestReconcileArgoCD_rbacPolicyConfigMapMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1beta1.ArgoCD) {
+		a.Spec.RBAC.PolicyConfigMapSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"argocd.example.com/rbac": "true"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "team-a-rbac",
+		Namespace: testNamespace,
+		Labels:    map[string]string{"argocd.example.com/rbac": "true"},
+	}}
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}},
+	}
+	got := r.rbacPolicyConfigMapMapper(handler.MapObject{Meta: cm, Object: cm})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconciliation unsucessful: got: %v, want: %v", got, want)
+	}
+
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNamespace}}
+	got = r.rbacPolicyConfigMapMapper(handler.MapObject{Meta: other, Object: other})
+	if len(got) != 0 {
+		t.Errorf("Reconciliation unsucessful: got: %v, want no requests", got)
+	}
+}
+
+func TestReconcileArgoCD_groupMappingMapper(t *testing.T) {
+	a := makeTestArgoCD(func(a *v1beta1.ArgoCD) {
+		a.Spec.RBAC.GroupMappings = []v1beta1.ArgoCDRBACGroupMappingSpec{
+			{Groups: []string{"cluster-admins"}, Role: "admin"},
+			{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"argocd.example.com/team": "true"}}, Role: "readonly"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}},
+	}
+	admins := makeTestGroup("cluster-admins", nil)
+	got := r.groupMappingMapper(handler.MapObject{Meta: admins, Object: admins})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconciliation unsucessful: got: %v, want: %v", got, want)
+	}
+
+	team := makeTestGroup("team-a", map[string]string{"argocd.example.com/team": "true"})
+	got = r.groupMappingMapper(handler.MapObject{Meta: team, Object: team})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconciliation unsucessful: got: %v, want: %v", got, want)
+	}
+
+	other := makeTestGroup("other", nil)
+	got = r.groupMappingMapper(handler.MapObject{Meta: other, Object: other})
+	if len(got) != 0 {
+		t.Errorf("Reconciliation unsucessful: got: %v, want no requests", got)
+	}
+}
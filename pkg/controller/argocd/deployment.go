@@ -18,11 +18,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"reflect"
 	"strings"
-	"time"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 
@@ -31,10 +30,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func (r *ReconcileArgoCD) getArgoCDExport(cr *argoprojv1a1.ArgoCD) *argoprojv1a1.ArgoCDExport {
+func (r *ReconcileArgoCD) getArgoCDExport(cr *argoprojv1b1.ArgoCD) *argoprojv1a1.ArgoCDExport {
 	if cr.Spec.Import == nil {
 		return nil
 	}
@@ -59,7 +57,7 @@ func getArgoExportSecretName(export *argoprojv1a1.ArgoCDExport) string {
 	return name
 }
 
-func getArgoImportBackend(client client.Client, cr *argoprojv1a1.ArgoCD) string {
+func getArgoImportBackend(client client.Client, cr *argoprojv1b1.ArgoCD) string {
 	backend := common.ArgoCDExportStorageBackendLocal
 	namespace := cr.ObjectMeta.Namespace
 	if cr.Spec.Import != nil && cr.Spec.Import.Namespace != nil && len(*cr.Spec.Import.Namespace) > 0 {
@@ -76,7 +74,7 @@ func getArgoImportBackend(client client.Client, cr *argoprojv1a1.ArgoCD) string
 }
 
 // getArgoImportCommand will return the command for the ArgoCD import process.
-func getArgoImportCommand(client client.Client, cr *argoprojv1a1.ArgoCD) []string {
+func getArgoImportCommand(client client.Client, cr *argoprojv1b1.ArgoCD) []string {
 	cmd := make([]string, 0)
 	cmd = append(cmd, "uid_entrypoint.sh")
 	cmd = append(cmd, "argocd-operator-util")
@@ -185,20 +183,20 @@ func getArgoImportVolumes(cr *argoprojv1a1.ArgoCDExport) []corev1.Volume {
 }
 
 // getArgoRepoCommand will return the command for the ArgoCD Repo component.
-func getArgoRepoCommand(cr *argoprojv1a1.ArgoCD) []string {
+func getArgoRepoCommand(cr *argoprojv1b1.ArgoCD) []string {
 	cmd := make([]string, 0)
 
 	cmd = append(cmd, "uid_entrypoint.sh")
 	cmd = append(cmd, "argocd-repo-server")
 
-	cmd = append(cmd, "--redis")
-	cmd = append(cmd, getRedisServerAddress(cr))
+	cmd = append(cmd, getRedisServerArgs(cr)...)
+	cmd = append(cmd, getRedisClientArgs(cr)...)
 
 	return cmd
 }
 
 // getArgoServerCommand will return the command for the ArgoCD server component.
-func getArgoServerCommand(cr *argoprojv1a1.ArgoCD) []string {
+func getArgoServerCommand(cr *argoprojv1b1.ArgoCD) []string {
 	cmd := make([]string, 0)
 	cmd = append(cmd, "argocd-server")
 
@@ -219,24 +217,24 @@ func getArgoServerCommand(cr *argoprojv1a1.ArgoCD) []string {
 	cmd = append(cmd, "--repo-server")
 	cmd = append(cmd, getRepoServerAddress(cr))
 
-	cmd = append(cmd, "--redis")
-	cmd = append(cmd, getRedisServerAddress(cr))
+	cmd = append(cmd, getRedisServerArgs(cr)...)
+	cmd = append(cmd, getRedisClientArgs(cr)...)
 
 	return cmd
 }
 
 // getDexServerAddress will return the Dex server address.
-func getDexServerAddress(cr *argoprojv1a1.ArgoCD) string {
+func getDexServerAddress(cr *argoprojv1b1.ArgoCD) string {
 	return fmt.Sprintf("http://%s", fqdnServiceRef("dex-server", common.ArgoCDDefaultDexHTTPPort, cr))
 }
 
 // getRepoServerAddress will return the Argo CD repo server address.
-func getRepoServerAddress(cr *argoprojv1a1.ArgoCD) string {
+func getRepoServerAddress(cr *argoprojv1b1.ArgoCD) string {
 	return fqdnServiceRef("repo-server", common.ArgoCDDefaultRepoServerPort, cr)
 }
 
 // newDeployment returns a new Deployment instance for the given ArgoCD.
-func newDeployment(cr *argoprojv1a1.ArgoCD) *appsv1.Deployment {
+func newDeployment(cr *argoprojv1b1.ArgoCD) *appsv1.Deployment {
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -247,7 +245,8 @@ func newDeployment(cr *argoprojv1a1.ArgoCD) *appsv1.Deployment {
 }
 
 // newDeploymentWithName returns a new Deployment instance for the given ArgoCD using the given name.
-func newDeploymentWithName(name string, component string, cr *argoprojv1a1.ArgoCD) *appsv1.Deployment {
+// The pod overrides for the given component are merged into the pod template when the Deployment is applied.
+func newDeploymentWithName(name string, component string, cr *argoprojv1b1.ArgoCD) *appsv1.Deployment {
 	deploy := newDeployment(cr)
 	deploy.ObjectMeta.Name = name
 
@@ -275,48 +274,67 @@ func newDeploymentWithName(name string, component string, cr *argoprojv1a1.ArgoC
 }
 
 // newDeploymentWithSuffix returns a new Deployment instance for the given ArgoCD using the given suffix.
-func newDeploymentWithSuffix(suffix string, component string, cr *argoprojv1a1.ArgoCD) *appsv1.Deployment {
+func newDeploymentWithSuffix(suffix string, component string, cr *argoprojv1b1.ArgoCD) *appsv1.Deployment {
 	return newDeploymentWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), component, cr)
 }
 
-// reconcileDeployments will ensure that all Deployment resources are present for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileDeployments(cr *argoprojv1a1.ArgoCD) error {
-	err := r.reconcileDexDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileRedisDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileRedisHAProxyDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileRepoDeployment(cr)
-	if err != nil {
-		return err
+// getComponentReplicas will return the replica count for the Deployment of a stateless Argo CD component with the
+// given replica count and autoscale options. The HA replica count is used when HA is enabled and the component does
+// not set its own.
+func getComponentReplicas(replicas *int32, autoscale argoprojv1b1.ArgoCDAutoscaleSpec, cr *argoprojv1b1.ArgoCD) *int32 {
+	if replicas == nil && cr.Spec.HA.Enabled {
+		haReplicas := common.ArgoCDDefaultHAComponentReplicas
+		if cr.Spec.HA.ComponentReplicas != nil {
+			haReplicas = *cr.Spec.HA.ComponentReplicas
+		}
+		replicas = &haReplicas
 	}
+	return getDeploymentReplicas(replicas, autoscale)
+}
 
-	err = r.reconcileServerDeployment(cr)
-	if err != nil {
-		return err
+// getComponentAffinity will return the pod anti-affinity that spreads the pods with the given name across nodes, like
+// the Application Controller pods, or no affinity when HA is disabled for the given ArgoCD.
+func getComponentAffinity(name string, cr *argoprojv1b1.ArgoCD) *corev1.Affinity {
+	if !cr.Spec.HA.Enabled {
+		return nil
 	}
-
-	err = r.reconcileGrafanaDeployment(cr)
-	if err != nil {
-		return err
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								common.ArgoCDKeyName: name,
+							},
+						},
+						TopologyKey: common.ArgoCDKeyHostname,
+					},
+					Weight: int32(100),
+				}, {
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								common.ArgoCDKeyPartOf: common.ArgoCDAppName,
+							},
+						},
+						TopologyKey: common.ArgoCDKeyHostname,
+					},
+					Weight: int32(5),
+				},
+			},
+		},
 	}
-
-	return nil
 }
 
 // reconcileDexDeployment will ensure the Deployment resource is present for the ArgoCD Dex component.
-func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1b1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("dex-server", "dex-server", cr)
+
+	// Dex keeps its state in memory, a login fails when the callback is served by another pod, so it is not scaled
+	// when HA is enabled.
+	replicas := int32(1)
+	deploy.Spec.Replicas = &replicas
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
 		Command: []string{
 			"/shared/argocd-dex",
@@ -367,66 +385,42 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 			EmptyDir: &corev1.EmptyDirVolumeSource{},
 		},
 	}}
-	dexDisabled := isDexDisabled()
-	if dexDisabled {
-		log.Info("reconciling for dex, but dex is disabled")
+
+	// The OpenShift service CA bundle verifies the OpenShift servers, the service account CA is mounted with the token.
+	if useDexServiceCA(cr) {
+		deploy.Spec.Template.Spec.Containers[0].VolumeMounts = append(deploy.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "service-ca",
+			MountPath: common.ArgoCDDexServiceCAPath,
+			ReadOnly:  true,
+		})
+		deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "service-ca",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: nameWithSuffix(common.ArgoCDDexServiceCAConfigMapSuffix, cr),
+					},
+				},
+			},
+		})
 	}
 
-	existing := newDeploymentWithSuffix("dex-server", "dex-server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-		if dexDisabled {
+	if isDexDisabled() {
+		log.Info("reconciling for dex, but dex is disabled")
+		existing := newDeploymentWithSuffix("dex-server", "dex-server", cr)
+		if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 			log.Info("deleting the existing dex deployment because dex is disabled")
 			// Deployment exists but enabled flag has been set to false, delete the Deployment
 			return r.client.Delete(context.TODO(), existing)
 		}
-		changed := false
-
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getDexContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-
-		actualImage = existing.Spec.Template.Spec.InitContainers[0].Image
-		desiredImage = getArgoContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.InitContainers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Env,
-			deploy.Spec.Template.Spec.InitContainers[0].Env) {
-			existing.Spec.Template.Spec.InitContainers[0].Env = deploy.Spec.Template.Spec.InitContainers[0].Env
-			changed = true
-		}
-
-		if changed {
-			return r.client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
-	}
-
-	if dexDisabled {
 		return nil
 	}
 
-	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), deploy)
+	return r.applyObject(cr, deploy)
 }
 
 // reconcileGrafanaDeployment will ensure the Deployment resource is present for the ArgoCD Grafana component.
-func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1b1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("grafana", "grafana", cr)
 	deploy.Spec.Replicas = getGrafanaReplicas(cr)
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
@@ -509,48 +503,23 @@ func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) er
 		},
 	}
 
-	existing := newDeploymentWithSuffix("grafana", "grafana", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-		if !cr.Spec.Grafana.Enabled {
+	if !cr.Spec.Grafana.Enabled {
+		existing := newDeploymentWithSuffix("grafana", "grafana", cr)
+		if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 			// Deployment exists but enabled flag has been set to false, delete the Deployment
 			return r.client.Delete(context.TODO(), existing)
 		}
-		changed := false
-		if hasGrafanaSpecChanged(existing, cr) {
-			existing.Spec.Replicas = cr.Spec.Grafana.Size
-			changed = true
-		}
-
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-		if changed {
-			return r.client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled {
 		return nil // Grafana not enabled, do nothing.
 	}
-	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), deploy)
+
+	return r.applyObject(cr, deploy)
 }
 
 // reconcileRedisDeployment will ensure the Deployment resource is present for the ArgoCD Redis component.
-func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1b1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("redis", "redis", cr)
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
-		Args: []string{
-			"--save",
-			"",
-			"--appendonly",
-			"no",
-		},
+		Args:            getRedisArgs(cr),
 		Image:           getRedisContainerImage(cr),
 		ImagePullPolicy: corev1.PullAlways,
 		Name:            "redis",
@@ -559,74 +528,44 @@ func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) erro
 				ContainerPort: common.ArgoCDDefaultRedisPort,
 			},
 		},
-		Resources: getRedisResources(cr),
-		Env:       proxyEnvVars(),
+		Resources:    getRedisResources(cr),
+		Env:          proxyEnvVars(getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, cr)...),
+		VolumeMounts: getRedisTLSVolumeMounts(cr),
 	}}
+	deploy.Spec.Template.Spec.Volumes = getRedisTLSVolumes(cr)
 
 	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
 		return err
 	}
 
-	existing := newDeploymentWithSuffix("redis", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-		if cr.Spec.HA.Enabled {
+	if cr.Spec.HA.Enabled {
+		existing := newDeploymentWithSuffix("redis", "redis", cr)
+		if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
 			// Deployment exists but HA enabled flag has been set to true, delete the Deployment
-			return r.client.Delete(context.TODO(), deploy)
-		}
-		changed := false
-		actualImage := deploy.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getRedisContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-
-		if changed {
-			return r.client.Update(context.TODO(), existing)
+			return r.client.Delete(context.TODO(), existing)
 		}
-		return nil // Deployment found with nothing to do, move along...
-	}
-
-	if cr.Spec.HA.Enabled {
 		return nil // HA enabled, do nothing.
 	}
-	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), deploy)
+
+	return r.applyObject(cr, deploy)
 }
 
 // reconcileRedisHAProxyDeployment will ensure the Deployment resource is present for the Redis HA Proxy component.
-func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1b1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) {
-		if !cr.Spec.HA.Enabled {
+	if !cr.Spec.HA.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) {
 			// Deployment exists but HA enabled flag has been set to false, delete the Deployment
 			return r.client.Delete(context.TODO(), deploy)
 		}
-
-		actualImage := deploy.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getRedisHAProxyContainerImage(cr)
-
-		if actualImage != desiredImage {
-			deploy.Spec.Template.Spec.Containers[0].Image = desiredImage
-			deploy.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			return r.client.Update(context.TODO(), deploy)
-		}
-		return nil // Deployment found, do nothing
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
+	deploy.Spec.Replicas = cr.Spec.HA.RedisProxyReplicas
+	deploy.Spec.Template.ObjectMeta.Annotations = map[string]string{
+		common.ArgoCDKeyRedisHAConfigChecksum: getRedisHAConfigChecksum(cr),
+	}
+	deploy.Spec.Template.Spec.TopologySpreadConstraints = getRedisHATopologySpreadConstraints(nameWithSuffix("redis-ha-haproxy", cr), cr)
 	deploy.Spec.Template.Spec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: &corev1.PodAntiAffinity{
 			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
@@ -677,7 +616,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 			},
 		},
 		Resources: getRedisHAProxyResources(cr),
-		VolumeMounts: []corev1.VolumeMount{
+		VolumeMounts: append([]corev1.VolumeMount{
 			{
 				Name:      "data",
 				MountPath: "/usr/local/etc/haproxy",
@@ -686,7 +625,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 				Name:      "shared-socket",
 				MountPath: "/run/haproxy",
 			},
-		},
+		}, getRedisTLSVolumeMounts(cr)...),
 	}}
 
 	deploy.Spec.Template.Spec.InitContainers = []corev1.Container{{
@@ -699,9 +638,9 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 		Image:           getRedisHAProxyContainerImage(cr),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Name:            "config-init",
-		Env:             proxyEnvVars(),
+		Env:             proxyEnvVars(getRedisAuthEnv(common.ArgoCDRedisHAAuthEnvName, cr)...),
 		Resources:       getRedisHAProxyResources(cr),
-		VolumeMounts: []corev1.VolumeMount{
+		VolumeMounts: append([]corev1.VolumeMount{
 			{
 				Name:      "config-volume",
 				MountPath: "/readonly",
@@ -711,7 +650,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 				Name:      "data",
 				MountPath: "/data",
 			},
-		},
+		}, getRedisTLSVolumeMounts(cr)...),
 	}}
 
 	deploy.Spec.Template.Spec.Volumes = []corev1.Volume{
@@ -739,21 +678,22 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 		},
 	}
 
+	deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes, getRedisTLSVolumes(cr)...)
+
 	deploy.Spec.Template.Spec.ServiceAccountName = fmt.Sprintf("%s-%s", cr.Name, "argocd-redis-ha")
 
 	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
 		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), deploy)
+	return r.applyObject(cr, deploy)
 }
 
 // reconcileRepoDeployment will ensure the Deployment resource is present for the ArgoCD Repo component.
-func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1b1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("repo-server", "repo-server", cr)
+	deploy.Spec.Replicas = getComponentReplicas(cr.Spec.Repo.Replicas, cr.Spec.Repo.Autoscale, cr)
+	deploy.Spec.Template.Spec.Affinity = getComponentAffinity(deploy.Name, cr)
 	automountToken := false
 	if cr.Spec.Repo.MountSAToken {
 		automountToken = cr.Spec.Repo.MountSAToken
@@ -778,7 +718,7 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 			InitialDelaySeconds: 5,
 			PeriodSeconds:       10,
 		},
-		Env:  proxyEnvVars(),
+		Env:  proxyEnvVars(getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, cr)...),
 		Name: "argocd-repo-server",
 		Ports: []corev1.ContainerPort{
 			{
@@ -799,7 +739,7 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 			PeriodSeconds:       10,
 		},
 		Resources: getArgoRepoResources(cr),
-		VolumeMounts: []corev1.VolumeMount{
+		VolumeMounts: append([]corev1.VolumeMount{
 			{
 				Name:      "ssh-known-hosts",
 				MountPath: "/app/config/ssh",
@@ -820,7 +760,7 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 				Name:      "argocd-repo-server-tls",
 				MountPath: "/app/config/reposerver/tls",
 			},
-		},
+		}, getRedisTLSVolumeMounts(cr)...),
 	}}
 
 	deploy.Spec.Template.Spec.Volumes = []corev1.Volume{
@@ -870,52 +810,21 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 			},
 		},
 	}
+	deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes, getRedisTLSVolumes(cr)...)
 
-	existing := newDeploymentWithSuffix("repo-server", "repo-server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-		changed := false
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getArgoContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Volumes, existing.Spec.Template.Spec.Volumes) {
-			existing.Spec.Template.Spec.Volumes = deploy.Spec.Template.Spec.Volumes
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].VolumeMounts,
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts) {
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts = deploy.Spec.Template.Spec.Containers[0].VolumeMounts
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Env,
-			existing.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-
-		if changed {
-			return r.client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
-	}
-
-	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), deploy)
+	return r.applyObject(cr, deploy)
 }
 
 // reconcileServerDeployment will ensure the Deployment resource is present for the ArgoCD Server component.
-func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1b1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("server", "server", cr)
+	deploy.Spec.Replicas = getComponentReplicas(cr.Spec.Server.Replicas, cr.Spec.Server.Autoscale, cr)
+	deploy.Spec.Template.Spec.Affinity = getComponentAffinity(deploy.Name, cr)
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
 		Command:         getArgoServerCommand(cr),
 		Image:           getArgoContainerImage(cr),
 		ImagePullPolicy: corev1.PullAlways,
-		Env:             proxyEnvVars(),
+		Env:             proxyEnvVars(getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, cr)...),
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
@@ -945,7 +854,7 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 			PeriodSeconds:       30,
 		},
 		Resources: getArgoServerResources(cr),
-		VolumeMounts: []corev1.VolumeMount{
+		VolumeMounts: append([]corev1.VolumeMount{
 			{
 				Name:      "ssh-known-hosts",
 				MountPath: "/app/config/ssh",
@@ -957,7 +866,7 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 				Name:      "argocd-repo-server-tls",
 				MountPath: "/app/config/server/tls",
 			},
-		},
+		}, getRedisTLSVolumeMounts(cr)...),
 	}}
 	deploy.Spec.Template.Spec.ServiceAccountName = fmt.Sprintf("%s-%s", cr.Name, "argocd-server")
 	deploy.Spec.Template.Spec.Volumes = []corev1.Volume{
@@ -989,46 +898,9 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 			},
 		},
 	}
+	deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes, getRedisTLSVolumes(cr)...)
 
-	existing := newDeploymentWithSuffix("server", "server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getArgoContainerImage(cr)
-		changed := false
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Command,
-			deploy.Spec.Template.Spec.Containers[0].Command) {
-			existing.Spec.Template.Spec.Containers[0].Command = deploy.Spec.Template.Spec.Containers[0].Command
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Volumes, existing.Spec.Template.Spec.Volumes) {
-			existing.Spec.Template.Spec.Volumes = deploy.Spec.Template.Spec.Volumes
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].VolumeMounts,
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts) {
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts = deploy.Spec.Template.Spec.Containers[0].VolumeMounts
-			changed = true
-		}
-		if changed {
-			return r.client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
-	}
-
-	if err := controllerutil.SetControllerReference(cr, deploy, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), deploy)
+	return r.applyObject(cr, deploy)
 }
 
 // triggerDeploymentRollout will update the label with the given key to trigger a new rollout of the Deployment.
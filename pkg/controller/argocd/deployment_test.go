@@ -19,7 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -158,6 +158,18 @@ func TestReconcileArgoCD_reconcileDeployments_Dex_with_resources(t *testing.T) {
 	assert.DeepEqual(t, deployment.Spec.Template.Spec.InitContainers[0].Resources, testResources)
 }
 
+// reconcileTestDeploymentComponents will reconcile the components that own the Deployments of the given ArgoCD.
+func reconcileTestDeploymentComponents(t *testing.T, r *ReconcileArgoCD, a *argoprojv1beta1.ArgoCD) {
+	t.Helper()
+	assert.NilError(t, r.reconcileDexComponent(a))
+	assert.NilError(t, r.reconcileRedisComponent(a))
+	assert.NilError(t, r.reconcileRepoServerComponent(a))
+	assert.NilError(t, r.reconcileServerComponent(a))
+
+	// The Grafana configuration is generated from the Grafana Secret, which is reconciled by the Secrets component.
+	assert.NilError(t, r.reconcileGrafanaDeployment(a))
+}
+
 // reconcileRepoDeployments creates a Deployment with the proxy settings from the
 // environment propagated.
 func TestReconcileArgoCD_reconcileDeployments_proxy(t *testing.T) {
@@ -167,13 +179,12 @@ func TestReconcileArgoCD_reconcileDeployments_proxy(t *testing.T) {
 	os.Setenv("no_proxy", testNoProxy)
 
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Grafana.Enabled = true
 	})
 	r := makeTestReconciler(t, a)
 
-	err := r.reconcileDeployments(a)
-	assert.NilError(t, err)
+	reconcileTestDeploymentComponents(t, r, a)
 
 	for _, v := range deploymentNames {
 		assertDeploymentHasProxyVars(t, r.client, v)
@@ -188,12 +199,11 @@ func TestReconcileArgoCD_reconcileDeployments_proxy(t *testing.T) {
 func TestReconcileArgoCD_reconcileDeployments_proxy_update_existing(t *testing.T) {
 	restoreEnv(t)
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Grafana.Enabled = true
 	})
 	r := makeTestReconciler(t, a)
-	err := r.reconcileDeployments(a)
-	assert.NilError(t, err)
+	reconcileTestDeploymentComponents(t, r, a)
 	for _, v := range deploymentNames {
 		refuteDeploymentHasProxyVars(t, r.client, v)
 	}
@@ -204,8 +214,7 @@ func TestReconcileArgoCD_reconcileDeployments_proxy_update_existing(t *testing.T
 
 	logf.SetLogger(logf.ZapLogger(true))
 
-	err = r.reconcileDeployments(a)
-	assert.NilError(t, err)
+	reconcileTestDeploymentComponents(t, r, a)
 
 	for _, v := range deploymentNames {
 		assertDeploymentHasProxyVars(t, r.client, v)
@@ -220,13 +229,13 @@ func TestReconcileArgoCD_reconcileDeployments_HA_proxy(t *testing.T) {
 	os.Setenv("no_proxy", testNoProxy)
 
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	useRedisConfigTemplates(t)
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.HA.Enabled = true
 	})
 	r := makeTestReconciler(t, a)
 
-	err := r.reconcileDeployments(a)
-	assert.NilError(t, err)
+	reconcileTestDeploymentComponents(t, r, a)
 
 	assertDeploymentHasProxyVars(t, r.client, "argocd-redis-ha-haproxy")
 }
@@ -238,7 +247,7 @@ func TestReconcileArgoCD_reconcileDeployments_HA_proxy_with_resources(t *testing
 	os.Setenv("no_proxy", testNoProxy)
 
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCDWithResources(func(a *argoprojv1alpha1.ArgoCD) {
+	a := makeTestArgoCDWithResources(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.HA.Enabled = true
 	})
 	r := makeTestReconciler(t, a)
@@ -403,10 +412,12 @@ func TestReconcileArgoCD_reconcileDexDeployment(t *testing.T) {
 					{
 						Name:          "http",
 						ContainerPort: 5556,
+						Protocol:      corev1.ProtocolTCP,
 					},
 					{
 						Name:          "grpc",
 						ContainerPort: 5557,
+						Protocol:      corev1.ProtocolTCP,
 					},
 				},
 				VolumeMounts: []corev1.VolumeMount{
@@ -483,10 +494,12 @@ func TestReconcileArgoCD_reconcileDexDeployment_withUpdate(t *testing.T) {
 					{
 						Name:          "http",
 						ContainerPort: 5556,
+						Protocol:      corev1.ProtocolTCP,
 					},
 					{
 						Name:          "grpc",
 						ContainerPort: 5557,
+						Protocol:      corev1.ProtocolTCP,
 					},
 				},
 				VolumeMounts: []corev1.VolumeMount{
@@ -534,8 +547,8 @@ func TestReconcileArgoCD_reconcileServerDeployment(t *testing.T) {
 					"argocd-redis.argocd.svc.cluster.local:6379",
 				},
 				Ports: []corev1.ContainerPort{
-					{ContainerPort: 8080},
-					{ContainerPort: 8083},
+					{ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+					{ContainerPort: 8083, Protocol: corev1.ProtocolTCP},
 				},
 				LivenessProbe: &corev1.Probe{
 					Handler: corev1.Handler{
@@ -557,6 +570,7 @@ func TestReconcileArgoCD_reconcileServerDeployment(t *testing.T) {
 					InitialDelaySeconds: 3,
 					PeriodSeconds:       30,
 				},
+				Env:          getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a),
 				VolumeMounts: serverDefaultVolumeMounts(),
 			},
 		},
@@ -571,7 +585,7 @@ func TestReconcileArgoCD_reconcileServerDeployment(t *testing.T) {
 
 func TestReconcileArgoCD_reconcileServerDeploymentWithInsecure(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Server.Insecure = true
 	})
 	r := makeTestReconciler(t, a)
@@ -605,8 +619,8 @@ func TestReconcileArgoCD_reconcileServerDeploymentWithInsecure(t *testing.T) {
 					"argocd-redis.argocd.svc.cluster.local:6379",
 				},
 				Ports: []corev1.ContainerPort{
-					{ContainerPort: 8080},
-					{ContainerPort: 8083},
+					{ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+					{ContainerPort: 8083, Protocol: corev1.ProtocolTCP},
 				},
 				LivenessProbe: &corev1.Probe{
 					Handler: corev1.Handler{
@@ -628,6 +642,7 @@ func TestReconcileArgoCD_reconcileServerDeploymentWithInsecure(t *testing.T) {
 					InitialDelaySeconds: 3,
 					PeriodSeconds:       30,
 				},
+				Env:          getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a),
 				VolumeMounts: serverDefaultVolumeMounts(),
 			},
 		},
@@ -647,7 +662,7 @@ func TestReconcileArgoCD_reconcileServerDeploymentChangedToInsecure(t *testing.T
 
 	assert.NilError(t, r.reconcileServerDeployment(a))
 
-	a = makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+	a = makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Server.Insecure = true
 	})
 	assert.NilError(t, r.reconcileServerDeployment(a))
@@ -679,8 +694,8 @@ func TestReconcileArgoCD_reconcileServerDeploymentChangedToInsecure(t *testing.T
 					"argocd-redis.argocd.svc.cluster.local:6379",
 				},
 				Ports: []corev1.ContainerPort{
-					{ContainerPort: 8080},
-					{ContainerPort: 8083},
+					{ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+					{ContainerPort: 8083, Protocol: corev1.ProtocolTCP},
 				},
 				LivenessProbe: &corev1.Probe{
 					Handler: corev1.Handler{
@@ -702,6 +717,7 @@ func TestReconcileArgoCD_reconcileServerDeploymentChangedToInsecure(t *testing.T
 					InitialDelaySeconds: 3,
 					PeriodSeconds:       30,
 				},
+				Env:          getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a),
 				VolumeMounts: serverDefaultVolumeMounts(),
 			},
 		},
@@ -714,6 +730,89 @@ func TestReconcileArgoCD_reconcileServerDeploymentChangedToInsecure(t *testing.T
 	}
 }
 
+func TestReconcileArgoCD_reconcileServerDeployment_correctsDrift(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	want := deployment.Spec.Template.Spec.DeepCopy()
+
+	deployment.Spec.Template.Spec.ServiceAccountName = "default"
+	deployment.Spec.Template.Spec.Containers[0].Image = "example.com/argocd:drifted"
+	deployment.Spec.Template.Spec.Containers[0].LivenessProbe = nil
+	assert.NilError(t, r.client.Update(context.TODO(), deployment))
+
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	if diff := cmp.Diff(*want, deployment.Spec.Template.Spec); diff != "" {
+		t.Fatalf("failed to correct drift on argocd-server deployment:\n%s", diff)
+	}
+}
+
+func TestReconcileArgoCD_reconcileDexDeployment_HA(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.HA.ComponentReplicas = int32Ptr(3)
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileDexDeployment(a))
+
+	// Dex keeps its state in memory, it is not scaled with the other components.
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: "argocd-dex-server", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, int32(1))
+	assert.Assert(t, deployment.Spec.Template.Spec.Affinity == nil)
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_HA(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, common.ArgoCDDefaultHAComponentReplicas)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Affinity, getComponentAffinity("argocd-server", a))
+	terms := deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Equal(t, terms[0].PodAffinityTerm.LabelSelector.MatchLabels[common.ArgoCDKeyName], "argocd-server")
+	assert.Equal(t, terms[0].PodAffinityTerm.TopologyKey, common.ArgoCDKeyHostname)
+
+	// The HA replica count is used for the components without their own replica count.
+	a.Spec.HA.ComponentReplicas = int32Ptr(3)
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, int32(3))
+
+	a.Spec.Server.Replicas = int32Ptr(4)
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Equal(t, *deployment.Spec.Replicas, int32(4))
+
+	// The replica count and anti-affinity are not set when HA is disabled.
+	a.Spec.HA.Enabled = false
+	a.Spec.Server.Replicas = nil
+	assert.NilError(t, r.reconcileServerDeployment(a))
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Assert(t, deployment.Spec.Replicas == nil)
+	assert.Assert(t, deployment.Spec.Template.Spec.Affinity == nil)
+}
+
 func TestReconcileArgoCD_reconcileRedisDeployment(t *testing.T) {
 	// tests reconciler hook for redis deployment
 	cr := makeTestArgoCD()
@@ -756,13 +855,13 @@ func restoreEnv(t *testing.T) {
 }
 
 func operationProcessors(n int32) argoCDOpt {
-	return func(a *argoprojv1alpha1.ArgoCD) {
+	return func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Controller.Processors.Operation = n
 	}
 }
 
 func appSync(d time.Duration) argoCDOpt {
-	return func(a *argoprojv1alpha1.ArgoCD) {
+	return func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Controller.AppSync = &metav1.Duration{Duration: d}
 	}
 }
@@ -782,17 +881,29 @@ func assertDeploymentHasProxyVars(t *testing.T, c client.Client, name string) {
 		{Name: "no_proxy", Value: testNoProxy},
 	}
 	for _, c := range deployment.Spec.Template.Spec.Containers {
-		if diff := cmp.Diff(want, c.Env); diff != "" {
+		if diff := cmp.Diff(want, withoutSecretEnv(c.Env)); diff != "" {
 			t.Errorf("deployment proxy configuration failed for container %v in deployment %q:\n%s", c, name, diff)
 		}
 	}
 	for _, c := range deployment.Spec.Template.Spec.InitContainers {
-		if diff := cmp.Diff(want, c.Env); diff != "" {
+		if diff := cmp.Diff(want, withoutSecretEnv(c.Env)); diff != "" {
 			t.Errorf("deployment proxy configuration failed for init-container %v in deployment %q:\n%s", c, name, diff)
 		}
 	}
 }
 
+// withoutSecretEnv returns the given environment variables without the variables set from secrets, such as the Redis
+// password.
+func withoutSecretEnv(env []corev1.EnvVar) []corev1.EnvVar {
+	result := []corev1.EnvVar{}
+	for _, v := range env {
+		if v.ValueFrom == nil || v.ValueFrom.SecretKeyRef == nil {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
 func refuteDeploymentHasProxyVars(t *testing.T, c client.Client, name string) {
 	t.Helper()
 	deployment := &appsv1.Deployment{}
@@ -829,7 +940,7 @@ func assertNotFound(t *testing.T, err error) {
 }
 
 func controllerProcessors(n int32) argoCDOpt {
-	return func(a *argoprojv1alpha1.ArgoCD) {
+	return func(a *argoprojv1beta1.ArgoCD) {
 		a.Spec.Controller.Processors.Status = n
 	}
 }
@@ -0,0 +1,318 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+var openShiftConfigAPIFound = false
+
+// infrastructureGVK is the GroupVersionKind of the OpenShift Infrastructure resource.
+var infrastructureGVK = schema.GroupVersionKind{
+	Group:   common.ArgoCDOpenShiftConfigGroup,
+	Version: common.ArgoCDOpenShiftConfigVersion,
+	Kind:    "Infrastructure",
+}
+
+// IsOpenShiftConfigAPIAvailable returns true if the OpenShift cluster configuration API is present.
+func IsOpenShiftConfigAPIAvailable() bool {
+	return openShiftConfigAPIFound
+}
+
+// verifyOpenShiftConfigAPI will verify that the OpenShift cluster configuration API is present.
+func verifyOpenShiftConfigAPI() error {
+	found, err := argoutil.VerifyAPI(common.ArgoCDOpenShiftConfigGroup, common.ArgoCDOpenShiftConfigVersion)
+	if err != nil {
+		return err
+	}
+	openShiftConfigAPIFound = found
+	return nil
+}
+
+// getOpenShiftDexCA will return the CA bundle used by Dex to verify the OpenShift API and OAuth servers for the given
+// ArgoCD.
+func getOpenShiftDexCA(cr *argoprojv1b1.ArgoCD) string {
+	ca := common.ArgoCDDexOpenShiftCAServiceAccount
+	if len(cr.Spec.Dex.OpenShiftOAuthCA) > 0 {
+		ca = cr.Spec.Dex.OpenShiftOAuthCA
+	}
+	return ca
+}
+
+// getOpenShiftDexRootCA will return the path of the CA bundle in the Dex container for the OpenShift Dex connector.
+func getOpenShiftDexRootCA(cr *argoprojv1b1.ArgoCD) string {
+	if getOpenShiftDexCA(cr) == common.ArgoCDDexOpenShiftCAServiceCA {
+		return fmt.Sprintf("%s/%s", common.ArgoCDDexServiceCAPath, common.ArgoCDKeyServiceCABundle)
+	}
+	return common.ArgoCDServiceAccountCAPath
+}
+
+// useDexServiceCA returns true if the OpenShift service CA bundle is mounted in the Dex container for the given ArgoCD.
+func useDexServiceCA(cr *argoprojv1b1.ArgoCD) bool {
+	return !isDexDisabled() && cr.Spec.Dex.OpenShiftOAuth && getOpenShiftDexCA(cr) == common.ArgoCDDexOpenShiftCAServiceCA
+}
+
+// getOpenShiftDexIssuer will return the issuer of the OpenShift Dex connector for the given ArgoCD. Dex reads the
+// OAuth server metadata and the users from the OpenShift API server, the internal URL of the API server is read from
+// the OpenShift Infrastructure resource unless an issuer is set.
+func (r *ReconcileArgoCD) getOpenShiftDexIssuer(cr *argoprojv1b1.ArgoCD) (string, error) {
+	if len(cr.Spec.Dex.OpenShiftOAuthIssuer) > 0 {
+		return cr.Spec.Dex.OpenShiftOAuthIssuer, nil
+	}
+
+	if !IsOpenShiftConfigAPIAvailable() {
+		return common.ArgoCDDefaultDexOpenShiftIssuer, nil
+	}
+
+	infra := &unstructured.Unstructured{}
+	infra.SetGroupVersionKind(infrastructureGVK)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDOpenShiftInfrastructureName}, infra)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return common.ArgoCDDefaultDexOpenShiftIssuer, nil
+		}
+		return "", err
+	}
+
+	issuer, _, err := unstructured.NestedString(infra.Object, "status", "apiServerInternalURI")
+	if err != nil {
+		return "", err
+	}
+	if len(issuer) == 0 {
+		log.Info(fmt.Sprintf("infrastructure [%s] has no internal api server url, using [%s] as the dex issuer",
+			infra.GetName(), common.ArgoCDDefaultDexOpenShiftIssuer))
+		return common.ArgoCDDefaultDexOpenShiftIssuer, nil
+	}
+	return issuer, nil
+}
+
+// reconcileDexServiceCAConfigMap will ensure that the ConfigMap holding the OpenShift service CA bundle is present when
+// Dex verifies the OpenShift servers with it, and removes the ConfigMap otherwise. OpenShift injects the bundle in the
+// annotated ConfigMap and keeps it up to date.
+func (r *ReconcileArgoCD) reconcileDexServiceCAConfigMap(cr *argoprojv1b1.ArgoCD) error {
+	cm := newConfigMapWithSuffix(common.ArgoCDDexServiceCAConfigMapSuffix, cr)
+	exists := argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm)
+
+	if !useDexServiceCA(cr) {
+		if exists && metav1.IsControlledBy(cm, cr) {
+			log.Info(fmt.Sprintf("deleting dex service ca configmap [%s], it is no longer needed", cm.Name))
+			return r.client.Delete(context.TODO(), cm)
+		}
+		return nil
+	}
+
+	if exists {
+		if cm.Annotations[common.ArgoCDKeyInjectCABundle] == "true" {
+			return nil // ConfigMap up to date, do nothing
+		}
+		if cm.Annotations == nil {
+			cm.Annotations = make(map[string]string)
+		}
+		cm.Annotations[common.ArgoCDKeyInjectCABundle] = "true"
+		return r.client.Update(context.TODO(), cm)
+	}
+
+	cm.Annotations = map[string]string{
+		common.ArgoCDKeyInjectCABundle: "true",
+	}
+
+	if err := controllerutil.SetControllerReference(cr, cm, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), cm)
+}
+
+// getDexConnectorSecretKey will return the key in the argocd-secret Secret that holds the value of the given Dex
+// configuration field for the typed connector with the given ID.
+func getDexConnectorSecretKey(id string, field string) string {
+	return fmt.Sprintf("%s%s.%s", common.ArgoCDKeyDexConnectorSecretPrefix, id, field)
+}
+
+// getDexConnectorOptions will return the Dex connector type and options of the given typed connector, along with the
+// secrets it references keyed by the Dex configuration field they are set in. The type is empty when no connector
+// type is set.
+func getDexConnectorOptions(connector *argoprojv1b1.ArgoCDDexConnectorSpec) (string, interface{}, map[string]*corev1.SecretKeySelector) {
+	switch {
+	case connector.GitHub != nil:
+		return "github", connector.GitHub, map[string]*corev1.SecretKeySelector{
+			"clientSecret": &connector.GitHub.ClientSecretRef,
+		}
+	case connector.GitLab != nil:
+		return "gitlab", connector.GitLab, map[string]*corev1.SecretKeySelector{
+			"clientSecret": &connector.GitLab.ClientSecretRef,
+		}
+	case connector.LDAP != nil:
+		secrets := make(map[string]*corev1.SecretKeySelector)
+		if connector.LDAP.BindPasswordRef != nil {
+			secrets["bindPW"] = connector.LDAP.BindPasswordRef
+		}
+		return "ldap", connector.LDAP, secrets
+	case connector.Microsoft != nil:
+		return "microsoft", connector.Microsoft, map[string]*corev1.SecretKeySelector{
+			"clientSecret": &connector.Microsoft.ClientSecretRef,
+		}
+	case connector.OIDC != nil:
+		return "oidc", connector.OIDC, map[string]*corev1.SecretKeySelector{
+			"clientSecret": &connector.OIDC.ClientSecretRef,
+		}
+	case connector.SAML != nil:
+		return "saml", connector.SAML, map[string]*corev1.SecretKeySelector{}
+	}
+	return "", nil, nil
+}
+
+// getDexConnectorConfig will return the Dex configuration for the given typed connector options. The typed options
+// use the field names of the Dex configuration, the secret references are replaced by a reference to the key in the
+// argocd-secret Secret that holds their value, which Argo CD substitutes when it passes the configuration to Dex.
+func getDexConnectorConfig(id string, options interface{}, secrets map[string]*corev1.SecretKeySelector) (*runtime.RawExtension, error) {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]interface{})
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	for field := range config {
+		if strings.HasSuffix(field, "Ref") {
+			delete(config, field)
+		}
+	}
+	for field := range secrets {
+		config[field] = "$" + getDexConnectorSecretKey(id, field)
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
+// getDexConnectors will return the Dex connectors for the given ArgoCD, the connectors in the Dex configuration
+// followed by the typed connectors.
+func getDexConnectors(cr *argoprojv1b1.ArgoCD) []argoprojv1b1.ArgoCDDexConnector {
+	connectors := make([]argoprojv1b1.ArgoCDDexConnector, 0)
+	if cr.Spec.Dex.Config != nil {
+		connectors = append(connectors, cr.Spec.Dex.Config.Connectors...)
+	}
+
+	for i := range cr.Spec.Dex.Connectors {
+		connector := &cr.Spec.Dex.Connectors[i]
+		connectorType, options, secrets := getDexConnectorOptions(connector)
+		if len(connectorType) == 0 {
+			log.Info(fmt.Sprintf("dex connector [%s] has no connector type, skipping", connector.ID))
+			continue
+		}
+
+		config, err := getDexConnectorConfig(connector.ID, options, secrets)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("unable to render dex connector [%s]", connector.ID))
+			continue
+		}
+
+		connectors = append(connectors, argoprojv1b1.ArgoCDDexConnector{
+			Config: config,
+			ID:     connector.ID,
+			Name:   connector.Name,
+			Type:   connectorType,
+		})
+	}
+	return connectors
+}
+
+// getSecretKeyValue will return the value of the given key of a Secret in the namespace of the given ArgoCD.
+func (r *ReconcileArgoCD) getSecretKeyValue(cr *argoprojv1b1.ArgoCD, ref *corev1.SecretKeySelector) ([]byte, error) {
+	secret, err := argoutil.FetchSecret(r.client, cr.ObjectMeta, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("key [%s] not found in secret [%s]", ref.Key, ref.Name)
+	}
+	return value, nil
+}
+
+// reconcileDexConnectorSecrets will ensure that the argocd-secret Secret holds the values of the secrets referenced
+// by the typed Dex connectors of the given ArgoCD, and removes the values that are no longer referenced.
+func (r *ReconcileArgoCD) reconcileDexConnectorSecrets(cr *argoprojv1b1.ArgoCD) error {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDSecretName)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+		log.Info(fmt.Sprintf("argo secret [%s] not found, waiting to reconcile dex connector secrets", secret.Name))
+		return nil
+	}
+
+	desired := make(map[string][]byte)
+	if !isDexDisabled() {
+		for i := range cr.Spec.Dex.Connectors {
+			connector := &cr.Spec.Dex.Connectors[i]
+			_, _, secrets := getDexConnectorOptions(connector)
+			for field, ref := range secrets {
+				value, err := r.getSecretKeyValue(cr, ref)
+				if err != nil {
+					return fmt.Errorf("unable to read secret for dex connector [%s]: %w", connector.ID, err)
+				}
+				desired[getDexConnectorSecretKey(connector.ID, field)] = value
+			}
+		}
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	changed := false
+	for key := range secret.Data {
+		if _, ok := desired[key]; !ok && strings.HasPrefix(key, common.ArgoCDKeyDexConnectorSecretPrefix) {
+			delete(secret.Data, key)
+			changed = true
+		}
+	}
+	for key, value := range desired {
+		if !bytes.Equal(secret.Data[key], value) {
+			secret.Data[key] = value
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	// Argo CD restarts Dex with the substituted secrets when the argocd-secret Secret changes.
+	log.Info(fmt.Sprintf("updating dex connector secrets in argo secret [%s]", secret.Name))
+	return r.client.Update(context.TODO(), secret)
+}
@@ -0,0 +1,280 @@
+package argocd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/yaml"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func makeTestDexConnectors() []argoprojv1beta1.ArgoCDDexConnectorSpec {
+	return []argoprojv1beta1.ArgoCDDexConnectorSpec{
+		{
+			ID:   "github",
+			Name: "GitHub",
+			GitHub: &argoprojv1beta1.ArgoCDDexGitHubConnectorSpec{
+				ClientID: "client-id",
+				ClientSecretRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "dex-secrets"},
+					Key:                  "github",
+				},
+				Orgs: []argoprojv1beta1.ArgoCDDexGitHubOrgSpec{{Name: "example", Teams: []string{"admins"}}},
+			},
+		},
+		{
+			ID:   "ldap",
+			Name: "LDAP",
+			LDAP: &argoprojv1beta1.ArgoCDDexLDAPConnectorSpec{
+				Host:   "ldap.example.com:636",
+				BindDN: "cn=argocd,dc=example,dc=com",
+				BindPasswordRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "dex-secrets"},
+					Key:                  "ldap",
+				},
+				UserSearch: argoprojv1beta1.ArgoCDDexLDAPUserSearchSpec{BaseDN: "ou=users,dc=example,dc=com", Username: "uid"},
+			},
+		},
+	}
+}
+
+func TestReconcileArgoCD_getDexConfig_connectors(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Dex.Config = &argoprojv1beta1.ArgoCDDexConfig{
+			Connectors: []argoprojv1beta1.ArgoCDDexConnector{{
+				ID:     "oidc",
+				Name:   "OIDC",
+				Type:   "oidc",
+				Config: &runtime.RawExtension{Raw: []byte(`{"issuer":"https://sso.example.com"}`)},
+			}},
+		}
+		a.Spec.Dex.Connectors = makeTestDexConnectors()
+	})
+
+	config := struct {
+		Connectors []struct {
+			Config map[string]interface{} `json:"config"`
+			ID     string                 `json:"id"`
+			Type   string                 `json:"type"`
+		} `json:"connectors"`
+	}{}
+	r := makeTestReconciler(t, a)
+	dexConfig, err := r.getDexConfig(a)
+	assert.NilError(t, err)
+	assert.NilError(t, yaml.Unmarshal([]byte(dexConfig), &config))
+	assert.Equal(t, len(config.Connectors), 3)
+
+	// The connectors in the Dex configuration come first.
+	assert.Equal(t, config.Connectors[0].ID, "oidc")
+
+	github := config.Connectors[1]
+	assert.Equal(t, github.Type, "github")
+	assert.Equal(t, github.Config["clientID"], "client-id")
+	assert.Equal(t, github.Config["clientSecret"], "$dex.connectors.github.clientSecret")
+	_, ok := github.Config["clientSecretRef"]
+	assert.Assert(t, !ok)
+
+	ldap := config.Connectors[2]
+	assert.Equal(t, ldap.Type, "ldap")
+	assert.Equal(t, ldap.Config["bindPW"], "$dex.connectors.ldap.bindPW")
+	assert.Equal(t, ldap.Config["userSearch"].(map[string]interface{})["username"], "uid")
+}
+
+func TestReconcileArgoCD_reconcileDexConnectorSecrets(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Dex.Connectors = makeTestDexConnectors()
+	})
+	secret := argoutil.NewSecretWithName(a.ObjectMeta, common.ArgoCDSecretName)
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyServerSecretKey: []byte("secret-key"),
+		"dex.github.clientSecret":       []byte("manual"),
+	}
+	dexSecrets := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dex-secrets", Namespace: testNamespace},
+		Data: map[string][]byte{
+			"github": []byte("github-secret"),
+			"ldap":   []byte("ldap-password"),
+		},
+	}
+	r := makeTestReconciler(t, a, secret, dexSecrets)
+	key := types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}
+
+	assert.NilError(t, r.reconcileDexConnectorSecrets(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, string(secret.Data["dex.connectors.github.clientSecret"]), "github-secret")
+	assert.Equal(t, string(secret.Data["dex.connectors.ldap.bindPW"]), "ldap-password")
+
+	// The secrets of a removed connector are removed, the keys set by hand are kept.
+	a.Spec.Dex.Connectors = a.Spec.Dex.Connectors[:1]
+	assert.NilError(t, r.reconcileDexConnectorSecrets(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	_, ok := secret.Data["dex.connectors.ldap.bindPW"]
+	assert.Assert(t, !ok)
+	assert.Equal(t, string(secret.Data["dex.github.clientSecret"]), "manual")
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyServerSecretKey]), "secret-key")
+
+	// A missing key is reported.
+	a.Spec.Dex.Connectors[0].GitHub.ClientSecretRef.Key = "missing"
+	err := r.reconcileDexConnectorSecrets(a)
+	assert.Assert(t, err != nil && strings.Contains(err.Error(), "key [missing] not found in secret [dex-secrets]"))
+}
+
+// enableOpenShiftConfigAPI marks the OpenShift cluster configuration API as available and registers the
+// Infrastructure kind with the test scheme, so that the fake client can read it.
+func enableOpenShiftConfigAPI(t *testing.T) {
+	openShiftConfigAPIFound = true
+	scheme.Scheme.AddKnownTypeWithName(infrastructureGVK, &unstructured.Unstructured{})
+	t.Cleanup(func() {
+		openShiftConfigAPIFound = false
+	})
+}
+
+func makeTestDexServiceAccount() (*corev1.ServiceAccount, *corev1.Secret) {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-argocd-dex-server", Namespace: testNamespace},
+		Secrets:    []corev1.ObjectReference{{Name: "argocd-dex-server-token"}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-dex-server-token", Namespace: testNamespace},
+		Data:       map[string][]byte{"token": []byte("dex-token")},
+	}
+	return sa, secret
+}
+
+func TestReconcileArgoCD_getOpenShiftDexConnector(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	enableOpenShiftConfigAPI(t)
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = true
+	})
+	infra := &unstructured.Unstructured{}
+	infra.SetGroupVersionKind(infrastructureGVK)
+	infra.SetName(common.ArgoCDOpenShiftInfrastructureName)
+	infra.Object["status"] = map[string]interface{}{
+		"apiServerInternalURI": "https://api-int.example.com:6443",
+	}
+	sa, secret := makeTestDexServiceAccount()
+	r := makeTestReconciler(t, a, infra, sa, secret)
+
+	getConfig := func() map[string]interface{} {
+		t.Helper()
+		connector, err := r.getOpenShiftDexConnector(a)
+		assert.NilError(t, err)
+		assert.Equal(t, connector.ID, "openshift")
+		config := make(map[string]interface{})
+		assert.NilError(t, yaml.Unmarshal(connector.Config.Raw, &config))
+		return config
+	}
+
+	// The issuer is discovered from the cluster, the TLS certificates are verified with the service account CA.
+	config := getConfig()
+	assert.Equal(t, config["issuer"], "https://api-int.example.com:6443")
+	assert.Equal(t, config["rootCA"], "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt")
+	_, ok := config["insecureCA"]
+	assert.Assert(t, !ok)
+
+	a.Spec.Dex.OpenShiftOAuthCA = common.ArgoCDDexOpenShiftCAServiceCA
+	a.Spec.Dex.OpenShiftOAuthIssuer = "https://api.example.com:6443"
+	config = getConfig()
+	assert.Equal(t, config["issuer"], "https://api.example.com:6443")
+	assert.Equal(t, config["rootCA"], "/app/config/dex/service-ca/service-ca.crt")
+}
+
+func TestReconcileArgoCD_getDexConfig_openShiftConnector(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = true
+		a.Spec.Dex.Connectors = makeTestDexConnectors()
+	})
+	sa, secret := makeTestDexServiceAccount()
+	r := makeTestReconciler(t, a, sa, secret)
+
+	getConnectorIDs := func() []string {
+		t.Helper()
+		dexConfig, err := r.getDexConfig(a)
+		assert.NilError(t, err)
+		config := struct {
+			Connectors []struct {
+				ID string `json:"id"`
+			} `json:"connectors"`
+		}{}
+		assert.NilError(t, yaml.Unmarshal([]byte(dexConfig), &config))
+		ids := []string{}
+		for _, connector := range config.Connectors {
+			ids = append(ids, connector.ID)
+		}
+		return ids
+	}
+
+	// The OpenShift connector follows the typed connectors.
+	assert.DeepEqual(t, getConnectorIDs(), []string{"github", "ldap", "openshift"})
+
+	// The connectors in the Dex configuration replace the OpenShift connector.
+	a.Spec.Dex.Config = &argoprojv1beta1.ArgoCDDexConfig{
+		Connectors: []argoprojv1beta1.ArgoCDDexConnector{{ID: "oidc", Name: "OIDC", Type: "oidc"}},
+	}
+	assert.DeepEqual(t, getConnectorIDs(), []string{"oidc", "github", "ldap"})
+}
+
+func TestReconcileArgoCD_getOpenShiftDexIssuer_default(t *testing.T) {
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	// The API server is reached through its service without the OpenShift cluster configuration.
+	issuer, err := r.getOpenShiftDexIssuer(a)
+	assert.NilError(t, err)
+	assert.Equal(t, issuer, "https://kubernetes.default.svc")
+
+	enableOpenShiftConfigAPI(t)
+	issuer, err = r.getOpenShiftDexIssuer(a)
+	assert.NilError(t, err)
+	assert.Equal(t, issuer, "https://kubernetes.default.svc")
+}
+
+func TestReconcileArgoCD_reconcileDexServiceCAConfigMap(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	restoreEnv(t)
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = true
+		a.Spec.Dex.OpenShiftOAuthCA = common.ArgoCDDexOpenShiftCAServiceCA
+	})
+	r := makeTestReconciler(t, a)
+	key := types.NamespacedName{Name: "argocd-dex-service-ca", Namespace: testNamespace}
+
+	assert.NilError(t, r.reconcileDexServiceCAConfigMap(a))
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, cm))
+	assert.Equal(t, cm.Annotations[common.ArgoCDKeyInjectCABundle], "true")
+
+	assert.NilError(t, r.reconcileDexDeployment(a))
+	deploy := &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-server", Namespace: testNamespace}, deploy))
+	volumes := deploy.Spec.Template.Spec.Volumes
+	assert.Equal(t, volumes[len(volumes)-1].ConfigMap.Name, "argocd-dex-service-ca")
+	mounts := deploy.Spec.Template.Spec.Containers[0].VolumeMounts
+	assert.Equal(t, mounts[len(mounts)-1].MountPath, "/app/config/dex/service-ca")
+
+	// The ConfigMap is removed when the service account CA is used.
+	a.Spec.Dex.OpenShiftOAuthCA = common.ArgoCDDexOpenShiftCAServiceAccount
+	assert.NilError(t, r.reconcileDexServiceCAConfigMap(a))
+	err := r.client.Get(context.TODO(), key, &corev1.ConfigMap{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+}
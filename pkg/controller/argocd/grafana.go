@@ -23,10 +23,9 @@ import (
 	"strings"
 	"text/template"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/sethvargo/go-password/password"
-	appsv1 "k8s.io/api/apps/v1"
 )
 
 // GrafanaConfig represents the Grafana configuration options.
@@ -59,7 +58,7 @@ func generateGrafanaSecretKey() ([]byte, error) {
 }
 
 // getGrafanaHost will return the hostname value for Grafana.
-func getGrafanaHost(cr *argoprojv1a1.ArgoCD) string {
+func getGrafanaHost(cr *argoprojv1b1.ArgoCD) string {
 	host := nameWithSuffix("grafana", cr)
 	if len(cr.Spec.Grafana.Host) > 0 {
 		host = cr.Spec.Grafana.Host
@@ -68,7 +67,7 @@ func getGrafanaHost(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getGrafanaReplicas will return the size value for the Grafana replica count.
-func getGrafanaReplicas(cr *argoprojv1a1.ArgoCD) *int32 {
+func getGrafanaReplicas(cr *argoprojv1b1.ArgoCD) *int32 {
 	replicas := common.ArgoCDDefaultGrafanaReplicas
 	if cr.Spec.Grafana.Size != nil {
 		if *cr.Spec.Grafana.Size >= 0 && *cr.Spec.Grafana.Size != replicas {
@@ -87,21 +86,6 @@ func getGrafanaConfigPath() string {
 	return common.ArgoCDDefaultGrafanaConfigPath
 }
 
-// hasGrafanaSpecChanged will return true if the supported properties differs in the actual versus the desired state.
-func hasGrafanaSpecChanged(actual *appsv1.Deployment, desired *argoprojv1a1.ArgoCD) bool {
-	// Replica count
-	if desired.Spec.Grafana.Size != nil { // Replica count specified in desired state
-		if *desired.Spec.Grafana.Size >= 0 && *actual.Spec.Replicas != *desired.Spec.Grafana.Size {
-			return true
-		}
-	} else { // Replica count NOT specified in desired state
-		if *actual.Spec.Replicas != common.ArgoCDDefaultGrafanaReplicas {
-			return true
-		}
-	}
-	return false
-}
-
 // loadGrafanaConfigs will scan the config directory and read any files ending with '.yaml'
 func loadGrafanaConfigs() (map[string]string, error) {
 	data := make(map[string]string)
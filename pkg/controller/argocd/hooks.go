@@ -3,7 +3,7 @@ package argocd
 import (
 	"sync"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 )
 
 var (
@@ -13,7 +13,7 @@ var (
 
 // Hook changes resources as they are created or updated by the
 // reconciler.
-type Hook func(*argoprojv1alpha1.ArgoCD, interface{}, string) error
+type Hook func(*argoprojv1beta1.ArgoCD, interface{}, string) error
 
 // Register adds a modifier for updating resources during reconciliation.
 func Register(h ...Hook) {
@@ -22,7 +22,7 @@ func Register(h ...Hook) {
 	hooks = append(hooks, h...)
 }
 
-func applyReconcilerHook(cr *argoprojv1alpha1.ArgoCD, i interface{}, hint string) error {
+func applyReconcilerHook(cr *argoprojv1beta1.ArgoCD, i interface{}, hint string) error {
 	mutex.Lock()
 	defer mutex.Unlock()
 	for _, v := range hooks {
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"testing"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/rbac/v1"
@@ -12,7 +12,7 @@ import (
 
 var errMsg = errors.New("this is a test error")
 
-func testDeploymentHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) error {
+func testDeploymentHook(cr *argoprojv1beta1.ArgoCD, v interface{}, s string) error {
 	switch o := v.(type) {
 	case *appsv1.Deployment:
 		var replicas int32 = 3
@@ -21,7 +21,7 @@ func testDeploymentHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) er
 	return nil
 }
 
-func testClusterRoleHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) error {
+func testClusterRoleHook(cr *argoprojv1beta1.ArgoCD, v interface{}, s string) error {
 	switch o := v.(type) {
 	case *v1.ClusterRole:
 		o.Rules = append(o.Rules, policyRuleForApplicationController()...)
@@ -29,7 +29,7 @@ func testClusterRoleHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) e
 	return nil
 }
 
-func testRoleHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) error {
+func testRoleHook(cr *argoprojv1beta1.ArgoCD, v interface{}, s string) error {
 	switch o := v.(type) {
 	case *v1.Role:
 		if o.Name == cr.Name+"-"+applicationController {
@@ -39,7 +39,7 @@ func testRoleHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) error {
 	return nil
 }
 
-func testErrorHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, s string) error {
+func testErrorHook(cr *argoprojv1beta1.ArgoCD, v interface{}, s string) error {
 	return errMsg
 }
 
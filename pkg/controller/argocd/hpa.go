@@ -17,14 +17,15 @@ package argocd
 import (
 	"context"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
-	autoscaling "k8s.io/api/autoscaling/v1"
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func newHorizontalPodAutoscaler(cr *argoprojv1a1.ArgoCD) *autoscaling.HorizontalPodAutoscaler {
+func newHorizontalPodAutoscaler(cr *argoprojv1b1.ArgoCD) *autoscaling.HorizontalPodAutoscaler {
 	return &autoscaling.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -34,7 +35,7 @@ func newHorizontalPodAutoscaler(cr *argoprojv1a1.ArgoCD) *autoscaling.Horizontal
 	}
 }
 
-func newHorizontalPodAutoscalerWithName(name string, cr *argoprojv1a1.ArgoCD) *autoscaling.HorizontalPodAutoscaler {
+func newHorizontalPodAutoscalerWithName(name string, cr *argoprojv1b1.ArgoCD) *autoscaling.HorizontalPodAutoscaler {
 	hpa := newHorizontalPodAutoscaler(cr)
 	hpa.ObjectMeta.Name = name
 
@@ -45,49 +46,105 @@ func newHorizontalPodAutoscalerWithName(name string, cr *argoprojv1a1.ArgoCD) *a
 	return hpa
 }
 
-func newHorizontalPodAutoscalerWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *autoscaling.HorizontalPodAutoscaler {
+func newHorizontalPodAutoscalerWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) *autoscaling.HorizontalPodAutoscaler {
 	return newHorizontalPodAutoscalerWithName(nameWithSuffix(suffix, cr), cr)
 }
 
-// reconcileServerHPA will ensure that the HorizontalPodAutoscaler is present for the Argo CD Server component.
-func (r *ReconcileArgoCD) reconcileServerHPA(cr *argoprojv1a1.ArgoCD) error {
-	hpa := newHorizontalPodAutoscalerWithSuffix("server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, hpa.Name, hpa) {
-		if !cr.Spec.Server.Autoscale.Enabled {
-			return r.client.Delete(context.TODO(), hpa) // HorizontalPodAutoscaler found but globally disabled, delete it.
+// getHPASpec will return the HorizontalPodAutoscaler spec for the Deployment with the given suffix. The autoscaling/v1
+// HPA options are used as the base, the MinReplicas, MaxReplicas and Metrics options are set on top of them.
+func getHPASpec(suffix string, autoscale argoprojv1b1.ArgoCDAutoscaleSpec, cr *argoprojv1b1.ArgoCD) autoscaling.HorizontalPodAutoscalerSpec {
+	minReplicas := common.ArgoCDDefaultAutoscaleMinReplicas
+	spec := autoscaling.HorizontalPodAutoscalerSpec{
+		MaxReplicas: common.ArgoCDDefaultAutoscaleMaxReplicas,
+		MinReplicas: &minReplicas,
+		ScaleTargetRef: autoscaling.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       nameWithSuffix(suffix, cr),
+		},
+	}
+
+	targetCPU := common.ArgoCDDefaultAutoscaleTargetCPUUtilization
+	if hpa := autoscale.HPA; hpa != nil {
+		if hpa.ScaleTargetRef.Name != "" {
+			spec.ScaleTargetRef = autoscaling.CrossVersionObjectReference{
+				APIVersion: hpa.ScaleTargetRef.APIVersion,
+				Kind:       hpa.ScaleTargetRef.Kind,
+				Name:       hpa.ScaleTargetRef.Name,
+			}
+		}
+		if hpa.MaxReplicas > 0 {
+			spec.MaxReplicas = hpa.MaxReplicas
+		}
+		if hpa.MinReplicas != nil {
+			spec.MinReplicas = hpa.MinReplicas
+		}
+		if hpa.TargetCPUUtilizationPercentage != nil {
+			targetCPU = *hpa.TargetCPUUtilizationPercentage
 		}
-		return nil // HorizontalPodAutoscaler found and configured, nothing do to, move along...
 	}
 
-	if !cr.Spec.Server.Autoscale.Enabled {
-		return nil // AutoScale not enabled, move along...
+	if autoscale.MaxReplicas > 0 {
+		spec.MaxReplicas = autoscale.MaxReplicas
+	}
+	if autoscale.MinReplicas != nil {
+		spec.MinReplicas = autoscale.MinReplicas
 	}
 
-	if cr.Spec.Server.Autoscale.HPA != nil {
-		hpa.Spec = *cr.Spec.Server.Autoscale.HPA
+	if len(autoscale.Metrics) > 0 {
+		spec.Metrics = autoscale.Metrics
 	} else {
-		hpa.Spec.MaxReplicas = 3
+		spec.Metrics = []autoscaling.MetricSpec{{
+			Type: autoscaling.ResourceMetricSourceType,
+			Resource: &autoscaling.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscaling.MetricTarget{
+					Type:               autoscaling.UtilizationMetricType,
+					AverageUtilization: &targetCPU,
+				},
+			},
+		}}
+	}
 
-		var minrReplicas int32 = 1
-		hpa.Spec.MinReplicas = &minrReplicas
+	spec.Behavior = autoscale.Behavior
+	return spec
+}
 
-		var tcup int32 = 50
-		hpa.Spec.TargetCPUUtilizationPercentage = &tcup
+// getDeploymentReplicas will return the replica count for a Deployment with the given autoscale options. No replica
+// count is returned while autoscaling is enabled, so that the HorizontalPodAutoscaler owns the scale.
+func getDeploymentReplicas(replicas *int32, autoscale argoprojv1b1.ArgoCDAutoscaleSpec) *int32 {
+	if autoscale.Enabled {
+		return nil
+	}
+	return replicas
+}
 
-		hpa.Spec.ScaleTargetRef = autoscaling.CrossVersionObjectReference{
-			APIVersion: "apps/v1",
-			Kind:       "Deployment",
-			Name:       nameWithSuffix("server", cr),
+// reconcileHPA will ensure that the HorizontalPodAutoscaler for the Deployment with the given suffix matches the
+// given autoscale options.
+func (r *ReconcileArgoCD) reconcileHPA(suffix string, autoscale argoprojv1b1.ArgoCDAutoscaleSpec, cr *argoprojv1b1.ArgoCD) error {
+	hpa := newHorizontalPodAutoscalerWithSuffix(suffix, cr)
+	if !autoscale.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, hpa.Name, hpa) {
+			return r.client.Delete(context.TODO(), hpa) // HorizontalPodAutoscaler found but globally disabled, delete it.
 		}
+		return nil // AutoScale not enabled, move along...
 	}
 
-	return r.client.Create(context.TODO(), hpa)
+	hpa.Spec = getHPASpec(suffix, autoscale, cr)
+	return r.applyObject(cr, hpa)
 }
 
-// reconcileAutoscalers will ensure that all HorizontalPodAutoscalers are present for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileAutoscalers(cr *argoprojv1a1.ArgoCD) error {
-	if err := r.reconcileServerHPA(cr); err != nil {
-		return err
-	}
-	return nil
+// reconcileServerHPA will ensure that the HorizontalPodAutoscaler is present for the Argo CD Server component.
+func (r *ReconcileArgoCD) reconcileServerHPA(cr *argoprojv1b1.ArgoCD) error {
+	return r.reconcileHPA("server", cr.Spec.Server.Autoscale, cr)
+}
+
+// reconcileRepoServerHPA will ensure that the HorizontalPodAutoscaler is present for the Argo CD repo server component.
+func (r *ReconcileArgoCD) reconcileRepoServerHPA(cr *argoprojv1b1.ArgoCD) error {
+	return r.reconcileHPA("repo-server", cr.Spec.Repo.Autoscale, cr)
+}
+
+// reconcileApplicationSetHPA will ensure that the HorizontalPodAutoscaler is present for the ApplicationSet controller.
+func (r *ReconcileArgoCD) reconcileApplicationSetHPA(cr *argoprojv1b1.ArgoCD) error {
+	return r.reconcileHPA("applicationset-controller", cr.Spec.ApplicationSet.Autoscale, cr)
 }
@@ -0,0 +1,170 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func int32Ptr(val int32) *int32 {
+	return &val
+}
+
+func cpuUtilizationMetric(percentage int32) autoscaling.MetricSpec {
+	return autoscaling.MetricSpec{
+		Type: autoscaling.ResourceMetricSourceType,
+		Resource: &autoscaling.ResourceMetricSource{
+			Name: corev1.ResourceCPU,
+			Target: autoscaling.MetricTarget{
+				Type:               autoscaling.UtilizationMetricType,
+				AverageUtilization: &percentage,
+			},
+		},
+	}
+}
+
+func TestGetHPASpec(t *testing.T) {
+	memoryMetric := autoscaling.MetricSpec{
+		Type: autoscaling.ResourceMetricSourceType,
+		Resource: &autoscaling.ResourceMetricSource{
+			Name: corev1.ResourceMemory,
+			Target: autoscaling.MetricTarget{
+				Type:         autoscaling.AverageValueMetricType,
+				AverageValue: resource.NewQuantity(512*1024*1024, resource.BinarySI),
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		autoscale argoprojv1beta1.ArgoCDAutoscaleSpec
+		want      autoscaling.HorizontalPodAutoscalerSpec
+	}{
+		{
+			name:      "defaults",
+			autoscale: argoprojv1beta1.ArgoCDAutoscaleSpec{Enabled: true},
+			want: autoscaling.HorizontalPodAutoscalerSpec{
+				MaxReplicas: 3,
+				MinReplicas: int32Ptr(1),
+				Metrics:     []autoscaling.MetricSpec{cpuUtilizationMetric(50)},
+			},
+		},
+		{
+			name: "autoscaling/v1 options",
+			autoscale: argoprojv1beta1.ArgoCDAutoscaleSpec{
+				Enabled: true,
+				HPA: &autoscalingv1.HorizontalPodAutoscalerSpec{
+					MaxReplicas:                    5,
+					MinReplicas:                    int32Ptr(2),
+					TargetCPUUtilizationPercentage: int32Ptr(80),
+				},
+			},
+			want: autoscaling.HorizontalPodAutoscalerSpec{
+				MaxReplicas: 5,
+				MinReplicas: int32Ptr(2),
+				Metrics:     []autoscaling.MetricSpec{cpuUtilizationMetric(80)},
+			},
+		},
+		{
+			name: "metrics take precedence",
+			autoscale: argoprojv1beta1.ArgoCDAutoscaleSpec{
+				Enabled: true,
+				HPA: &autoscalingv1.HorizontalPodAutoscalerSpec{
+					MaxReplicas:                    5,
+					TargetCPUUtilizationPercentage: int32Ptr(80),
+				},
+				MaxReplicas: 10,
+				MinReplicas: int32Ptr(3),
+				Metrics:     []autoscaling.MetricSpec{memoryMetric},
+			},
+			want: autoscaling.HorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				MinReplicas: int32Ptr(3),
+				Metrics:     []autoscaling.MetricSpec{memoryMetric},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := makeTestArgoCD()
+			tt.want.ScaleTargetRef = autoscaling.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "argocd-repo-server",
+			}
+			if diff := cmp.Diff(tt.want, getHPASpec("repo-server", tt.autoscale, cr)); diff != "" {
+				t.Fatalf("unexpected HorizontalPodAutoscaler spec:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileArgoCD_reconcileServerHPA_updatesSpec(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Server.Autoscale.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileServerHPA(a))
+
+	hpa := &autoscaling.HorizontalPodAutoscaler{}
+	key := types.NamespacedName{Name: "argocd-server", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, hpa))
+	assert.Equal(t, hpa.Spec.MaxReplicas, int32(3))
+
+	a.Spec.Server.Autoscale.MaxReplicas = 6
+	assert.NilError(t, r.reconcileServerHPA(a))
+
+	hpa = &autoscaling.HorizontalPodAutoscaler{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, hpa))
+	assert.Equal(t, hpa.Spec.MaxReplicas, int32(6))
+
+	a.Spec.Server.Autoscale.Enabled = false
+	assert.NilError(t, r.reconcileServerHPA(a))
+
+	err := r.client.Get(context.TODO(), key, &autoscaling.HorizontalPodAutoscaler{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileRepoServerHPA(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Repo.Autoscale.Enabled = true
+		a.Spec.Repo.Replicas = int32Ptr(4)
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+	assert.NilError(t, r.reconcileRepoServerHPA(a))
+
+	hpa := &autoscaling.HorizontalPodAutoscaler{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server", Namespace: a.Namespace}, hpa))
+	assert.Equal(t, hpa.Spec.ScaleTargetRef.Name, "argocd-repo-server")
+
+	// The replica count is left to the HorizontalPodAutoscaler while autoscaling is enabled.
+	deploy := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: "argocd-repo-server", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deploy))
+	assert.Assert(t, deploy.Spec.Replicas == nil)
+
+	a.Spec.Repo.Autoscale.Enabled = false
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deploy = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deploy))
+	assert.Equal(t, *deploy.Spec.Replicas, int32(4))
+}
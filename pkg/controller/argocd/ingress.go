@@ -18,17 +18,16 @@ import (
 	"context"
 	"fmt"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // getDefaultIngressAnnotations will return the default Ingress Annotations for the given ArgoCD.
-func getDefaultIngressAnnotations(cr *argoprojv1a1.ArgoCD) map[string]string {
+func getDefaultIngressAnnotations(cr *argoprojv1b1.ArgoCD) map[string]string {
 	annotations := make(map[string]string)
 	annotations[common.ArgoCDKeyIngressClass] = "nginx"
 	return annotations
@@ -44,7 +43,7 @@ func getPathOrDefault(path string) string {
 }
 
 // newIngress returns a new Ingress instance for the given ArgoCD.
-func newIngress(cr *argoprojv1a1.ArgoCD) *extv1beta1.Ingress {
+func newIngress(cr *argoprojv1b1.ArgoCD) *extv1beta1.Ingress {
 	return &extv1beta1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -55,7 +54,7 @@ func newIngress(cr *argoprojv1a1.ArgoCD) *extv1beta1.Ingress {
 }
 
 // newIngressWithName returns a new Ingress with the given name and ArgoCD.
-func newIngressWithName(name string, cr *argoprojv1a1.ArgoCD) *extv1beta1.Ingress {
+func newIngressWithName(name string, cr *argoprojv1b1.ArgoCD) *extv1beta1.Ingress {
 	ingress := newIngress(cr)
 	ingress.ObjectMeta.Name = name
 
@@ -67,42 +66,18 @@ func newIngressWithName(name string, cr *argoprojv1a1.ArgoCD) *extv1beta1.Ingres
 }
 
 // newIngressWithSuffix returns a new Ingress with the given name suffix for the ArgoCD.
-func newIngressWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *extv1beta1.Ingress {
+func newIngressWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) *extv1beta1.Ingress {
 	return newIngressWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), cr)
 }
 
-// reconcileIngresses will ensure that all ArgoCD Ingress resources are present.
-func (r *ReconcileArgoCD) reconcileIngresses(cr *argoprojv1a1.ArgoCD) error {
-	if err := r.reconcileArgoServerIngress(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileArgoServerGRPCIngress(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileGrafanaIngress(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcilePrometheusIngress(cr); err != nil {
-		return err
-	}
-	return nil
-}
-
 // reconcileArgoServerIngress will ensure that the ArgoCD Server Ingress is present.
-func (r *ReconcileArgoCD) reconcileArgoServerIngress(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileArgoServerIngress(cr *argoprojv1b1.ArgoCD) error {
 	ingress := newIngressWithSuffix("server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Server.Ingress.Enabled {
+	if !cr.Spec.Server.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Server.Ingress.Enabled {
 		return nil // Ingress not enabled, move along...
 	}
 
@@ -153,24 +128,17 @@ func (r *ReconcileArgoCD) reconcileArgoServerIngress(cr *argoprojv1a1.ArgoCD) er
 		ingress.Spec.TLS = cr.Spec.Server.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), ingress)
+	return r.applyObject(cr, ingress)
 }
 
 // reconcileArgoServerGRPCIngress will ensure that the ArgoCD Server GRPC Ingress is present.
-func (r *ReconcileArgoCD) reconcileArgoServerGRPCIngress(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileArgoServerGRPCIngress(cr *argoprojv1b1.ArgoCD) error {
 	ingress := newIngressWithSuffix("grpc", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Server.GRPC.Ingress.Enabled {
+	if !cr.Spec.Server.GRPC.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Server.GRPC.Ingress.Enabled {
 		return nil // Ingress not enabled, move along...
 	}
 
@@ -220,24 +188,17 @@ func (r *ReconcileArgoCD) reconcileArgoServerGRPCIngress(cr *argoprojv1a1.ArgoCD
 		ingress.Spec.TLS = cr.Spec.Server.GRPC.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), ingress)
+	return r.applyObject(cr, ingress)
 }
 
 // reconcileGrafanaIngress will ensure that the ArgoCD Server GRPC Ingress is present.
-func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1b1.ArgoCD) error {
 	ingress := newIngressWithSuffix("grafana", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
+	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
 		return nil // Grafana itself or Ingress not enabled, move along...
 	}
 
@@ -289,24 +250,17 @@ func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1a1.ArgoCD) error
 		ingress.Spec.TLS = cr.Spec.Grafana.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), ingress)
+	return r.applyObject(cr, ingress)
 }
 
 // reconcilePrometheusIngress will ensure that the Prometheus Ingress is present.
-func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1b1.ArgoCD) error {
 	ingress := newIngressWithSuffix("prometheus", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
+	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
 		return nil // Prometheus itself or Ingress not enabled, move along...
 	}
 
@@ -355,8 +309,5 @@ func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) er
 		ingress.Spec.TLS = cr.Spec.Prometheus.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), ingress)
+	return r.applyObject(cr, ingress)
 }
@@ -20,7 +20,7 @@ import (
 	json "encoding/json"
 	"fmt"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	keycloakv1alpha1 "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
@@ -161,7 +161,7 @@ func getKeycloakContainer() corev1.Container {
 	}
 }
 
-func getKeycloakDeploymentConfigTemplate(cr *argoprojv1a1.ArgoCD) *appsv1.DeploymentConfig {
+func getKeycloakDeploymentConfigTemplate(cr *argoprojv1b1.ArgoCD) *appsv1.DeploymentConfig {
 	ns := cr.Namespace
 	keycloakContainer := getKeycloakContainer()
 	keycloakImage := common.ArgoCDKeycloakImageName
@@ -177,7 +177,7 @@ func getKeycloakDeploymentConfigTemplate(cr *argoprojv1a1.ArgoCD) *appsv1.Deploy
 			Namespace: ns,
 			OwnerReferences: []metav1.OwnerReference{
 				{
-					APIVersion: "argoproj.io/v1alpha1",
+					APIVersion: "argoproj.io/v1beta1",
 					UID:        cr.UID,
 					Name:       cr.Name,
 					Controller: &controllerRef,
@@ -291,7 +291,7 @@ func getKeycloakRouteTemplate(ns string) *routev1.Route {
 	}
 }
 
-func newKeycloakTemplateInstance(cr *argoprojv1a1.ArgoCD) (*template.TemplateInstance, error) {
+func newKeycloakTemplateInstance(cr *argoprojv1b1.ArgoCD) (*template.TemplateInstance, error) {
 	tpl, err := newKeycloakTemplate(cr)
 	if err != nil {
 		return nil, err
@@ -307,7 +307,7 @@ func newKeycloakTemplateInstance(cr *argoprojv1a1.ArgoCD) (*template.TemplateIns
 	}, nil
 }
 
-func newKeycloakTemplate(cr *argoprojv1a1.ArgoCD) (template.Template, error) {
+func newKeycloakTemplate(cr *argoprojv1b1.ArgoCD) (template.Template, error) {
 	ns := cr.Namespace
 	tmpl := template.Template{}
 	configMapTemplate := getKeycloakConfigMapTemplate(ns)
@@ -389,7 +389,7 @@ func newKeycloakTemplate(cr *argoprojv1a1.ArgoCD) (template.Template, error) {
 }
 
 // prepares a keycloak config which is used in creating keycloak realm configuration.
-func (r *ReconcileArgoCD) prepareKeycloakConfig(cr *argoprojv1a1.ArgoCD) (*keycloakConfig, error) {
+func (r *ReconcileArgoCD) prepareKeycloakConfig(cr *argoprojv1b1.ArgoCD) (*keycloakConfig, error) {
 
 	var tlsVerification bool
 	// Get keycloak hostname from route.
@@ -558,7 +558,7 @@ func createRealmConfig(cfg *keycloakConfig) ([]byte, error) {
 }
 
 // Gets Keycloak Server cert. This cert is used to authenticate the api calls to the Keycloak service.
-func (r *ReconcileArgoCD) getKCServerCert(cr *argoprojv1a1.ArgoCD) ([]byte, error) {
+func (r *ReconcileArgoCD) getKCServerCert(cr *argoprojv1b1.ArgoCD) ([]byte, error) {
 
 	sslCertsSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -584,7 +584,7 @@ func getOAuthClient(ns string) string {
 }
 
 // Updates OIDC configuration for ArgoCD.
-func (r *ReconcileArgoCD) updateArgoCDConfiguration(cr *argoprojv1a1.ArgoCD, kRouteURL string) error {
+func (r *ReconcileArgoCD) updateArgoCDConfiguration(cr *argoprojv1b1.ArgoCD, kRouteURL string) error {
 
 	// Update the ArgoCD client secret for OIDC in argocd-secret.
 	argoCDSecret := &corev1.Secret{
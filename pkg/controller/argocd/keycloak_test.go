@@ -18,7 +18,7 @@ import (
 	"context"
 	"testing"
 
-	argoappv1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoappv1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	appsv1 "github.com/openshift/api/apps/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	"gotest.tools/assert"
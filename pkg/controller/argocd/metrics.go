@@ -0,0 +1,33 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// certificateExpiry is the expiry of the certificates used by Argo CD, served on the operator metrics endpoint.
+var certificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "argocd_operator_certificate_expiry_timestamp_seconds",
+		Help: "The time at which the certificate in a TLS secret used by Argo CD expires, in seconds since the epoch.",
+	},
+	[]string{"namespace", "argocd", "secret"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry)
+}
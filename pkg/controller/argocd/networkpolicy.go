@@ -0,0 +1,227 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// newNetworkPolicyWithSuffix returns a new NetworkPolicy instance with the given suffix for the given ArgoCD.
+func newNetworkPolicyWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) *networkingv1.NetworkPolicy {
+	name := nameWithSuffix(suffix, cr)
+	lbls := labelsForCluster(cr)
+	lbls[common.ArgoCDKeyName] = name
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+			Labels:    lbls,
+		},
+	}
+}
+
+// getNetworkPolicyPodPeers will return a peer for each of the Argo CD pods with the given name suffixes.
+func getNetworkPolicyPodPeers(cr *argoprojv1b1.ArgoCD, suffixes ...string) []networkingv1.NetworkPolicyPeer {
+	peers := make([]networkingv1.NetworkPolicyPeer, 0, len(suffixes))
+	for _, suffix := range suffixes {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.ArgoCDKeyName: nameWithSuffix(suffix, cr),
+				},
+			},
+		})
+	}
+	return peers
+}
+
+// getNetworkPolicyPorts will return the TCP NetworkPolicy ports for the given port numbers.
+func getNetworkPolicyPorts(ports ...int) []networkingv1.NetworkPolicyPort {
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		protocol := corev1.ProtocolTCP
+		number := intstr.FromInt(port)
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{
+			Port:     &number,
+			Protocol: &protocol,
+		})
+	}
+	return policyPorts
+}
+
+// getNetworkPolicyMetricsPeers will return the peers allowed to scrape the metrics ports for the given ArgoCD.
+func getNetworkPolicyMetricsPeers(cr *argoprojv1b1.ArgoCD) []networkingv1.NetworkPolicyPeer {
+	peers := make([]networkingv1.NetworkPolicyPeer, 0)
+	if cr.Spec.Prometheus.Enabled {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.ArgoCDKeyPrometheus: cr.Name,
+				},
+			},
+		})
+	}
+	return append(peers, cr.Spec.NetworkPolicy.MetricsPeers...)
+}
+
+// getNetworkPolicyIngressRules will return the given rules, leaving out the rules without peers. A rule without peers
+// would allow any source, these are the rules for the metrics ports when no metrics peers are given.
+func getNetworkPolicyIngressRules(rules ...networkingv1.NetworkPolicyIngressRule) []networkingv1.NetworkPolicyIngressRule {
+	filtered := make([]networkingv1.NetworkPolicyIngressRule, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule.From) > 0 {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered
+}
+
+// getNetworkPolicies will return the NetworkPolicies for the enabled components of the given ArgoCD, keyed by name.
+// Each policy selects the pods of a component and allows the traffic from the components that connect to it. The
+// extra peers of the component are allowed on any port.
+func getNetworkPolicies(cr *argoprojv1b1.ArgoCD) map[string]*networkingv1.NetworkPolicy {
+	policies := make(map[string]*networkingv1.NetworkPolicy)
+	metricsPeers := getNetworkPolicyMetricsPeers(cr)
+
+	add := func(suffix string, pods []string, rules []networkingv1.NetworkPolicyIngressRule) {
+		names := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			names = append(names, nameWithSuffix(pod, cr))
+		}
+
+		np := newNetworkPolicyWithSuffix(suffix, cr)
+		np.Spec.PodSelector = metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      common.ArgoCDKeyName,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   names,
+			}},
+		}
+		np.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		np.Spec.Ingress = rules
+		if peers := cr.Spec.NetworkPolicy.ExtraPeers[suffix]; len(peers) > 0 {
+			np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{From: peers})
+		}
+		policies[np.Name] = np
+	}
+
+	// The Argo CD server is the entry point for the UI and CLI, any source may reach it unless ingress controller
+	// peers are given.
+	add("server", []string{"server"}, append([]networkingv1.NetworkPolicyIngressRule{{
+		From:  cr.Spec.NetworkPolicy.IngressControllerPeers,
+		Ports: getNetworkPolicyPorts(common.ArgoCDDefaultServerPort),
+	}}, getNetworkPolicyIngressRules(networkingv1.NetworkPolicyIngressRule{
+		From:  metricsPeers,
+		Ports: getNetworkPolicyPorts(common.ArgoCDDefaultServerMetricsPort),
+	})...))
+
+	repoClients := []string{"server", "application-controller"}
+	if cr.Spec.ApplicationSet != nil {
+		repoClients = append(repoClients, "applicationset-controller")
+	}
+	add("repo-server", []string{"repo-server"}, getNetworkPolicyIngressRules(networkingv1.NetworkPolicyIngressRule{
+		From:  getNetworkPolicyPodPeers(cr, repoClients...),
+		Ports: getNetworkPolicyPorts(common.ArgoCDDefaultRepoServerPort),
+	}, networkingv1.NetworkPolicyIngressRule{
+		From:  metricsPeers,
+		Ports: getNetworkPolicyPorts(common.ArgoCDDefaultRepoMetricsPort),
+	}))
+
+	add("application-controller", []string{"application-controller"}, getNetworkPolicyIngressRules(networkingv1.NetworkPolicyIngressRule{
+		From:  metricsPeers,
+		Ports: getNetworkPolicyPorts(common.ArgoCDDefaultApplicationControllerMetricsPort),
+	}))
+
+	if !isDexDisabled() {
+		add("dex-server", []string{"dex-server"}, getNetworkPolicyIngressRules(networkingv1.NetworkPolicyIngressRule{
+			From:  getNetworkPolicyPodPeers(cr, "server"),
+			Ports: getNetworkPolicyPorts(common.ArgoCDDefaultDexHTTPPort, common.ArgoCDDefaultDexGRPCPort),
+		}))
+	}
+
+	if !isRedisExternal(cr) {
+		add("redis", []string{"redis", "redis-ha-haproxy"}, getNetworkPolicyIngressRules(networkingv1.NetworkPolicyIngressRule{
+			From:  getNetworkPolicyPodPeers(cr, "server", "repo-server", "application-controller"),
+			Ports: getNetworkPolicyPorts(common.ArgoCDDefaultRedisPort),
+		}))
+
+		// The Redis HA servers replicate from each other and are monitored by their Sentinels and HAProxy.
+		if cr.Spec.HA.Enabled {
+			add("redis-ha", []string{"redis-ha"}, getNetworkPolicyIngressRules(networkingv1.NetworkPolicyIngressRule{
+				From:  getNetworkPolicyPodPeers(cr, "redis-ha", "redis-ha-haproxy"),
+				Ports: getNetworkPolicyPorts(common.ArgoCDDefaultRedisPort, common.ArgoCDDefaultRedisSentinelPort),
+			}))
+		}
+	}
+	return policies
+}
+
+// reconcileNetworkPolicies will ensure that the NetworkPolicies are present for the enabled components of the given
+// ArgoCD when requested, and removes the NetworkPolicies that are no longer needed.
+func (r *ReconcileArgoCD) reconcileNetworkPolicies(cr *argoprojv1b1.ArgoCD) error {
+	policies := make(map[string]*networkingv1.NetworkPolicy)
+	if cr.Spec.NetworkPolicy.Enabled {
+		policies = getNetworkPolicies(cr)
+	}
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := r.applyObject(cr, policies[name]); err != nil {
+			return err
+		}
+	}
+
+	// The policies are labelled with their own name, they are selected by the managed-by label instead.
+	existing := &networkingv1.NetworkPolicyList{}
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			common.ArgoCDKeyManagedBy: cr.Name,
+		}),
+		Namespace: cr.Namespace,
+	}
+	if err := r.client.List(context.TODO(), existing, opts); err != nil {
+		return err
+	}
+
+	for i := range existing.Items {
+		np := &existing.Items[i]
+		if _, ok := policies[np.Name]; ok || !metav1.IsControlledBy(np, cr) {
+			continue
+		}
+		log.Info(fmt.Sprintf("deleting network policy [%s], it is no longer needed", np.Name))
+		if err := r.client.Delete(context.TODO(), np); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestGetNetworkPolicies(t *testing.T) {
+	a := makeTestArgoCD()
+
+	policies := getNetworkPolicies(a)
+	assert.Equal(t, len(policies), 5)
+	for _, name := range []string{"argocd-server", "argocd-repo-server", "argocd-application-controller", "argocd-dex-server", "argocd-redis"} {
+		np, ok := policies[name]
+		assert.Assert(t, ok, name)
+		assert.DeepEqual(t, np.Spec.PolicyTypes, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress})
+	}
+
+	// Any source may reach the server, but nothing may reach the metrics ports without metrics peers.
+	server := policies["argocd-server"]
+	assert.Equal(t, len(server.Spec.Ingress), 1)
+	assert.Assert(t, server.Spec.Ingress[0].From == nil)
+	assert.Equal(t, *server.Spec.Ingress[0].Ports[0].Port, intstr.FromInt(common.ArgoCDDefaultServerPort))
+	assert.Equal(t, len(policies["argocd-application-controller"].Spec.Ingress), 0)
+
+	repo := policies["argocd-repo-server"]
+	assert.DeepEqual(t, repo.Spec.PodSelector.MatchExpressions[0].Values, []string{"argocd-repo-server"})
+	assert.Equal(t, len(repo.Spec.Ingress), 1)
+	assert.DeepEqual(t, repo.Spec.Ingress[0].From, getNetworkPolicyPodPeers(a, "server", "application-controller"))
+	assert.Equal(t, *repo.Spec.Ingress[0].Ports[0].Port, intstr.FromInt(common.ArgoCDDefaultRepoServerPort))
+
+	redis := policies["argocd-redis"]
+	assert.DeepEqual(t, redis.Spec.PodSelector.MatchExpressions[0].Values, []string{"argocd-redis", "argocd-redis-ha-haproxy"})
+	assert.DeepEqual(t, redis.Spec.Ingress[0].From, getNetworkPolicyPodPeers(a, "server", "repo-server", "application-controller"))
+}
+
+func TestGetNetworkPolicies_peers(t *testing.T) {
+	ingressPeers := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ingress-nginx"}},
+	}}
+	extraPeers := []networkingv1.NetworkPolicyPeer{{
+		IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"},
+	}}
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.Prometheus.Enabled = true
+		a.Spec.ApplicationSet = &argoprojv1beta1.ArgoCDApplicationSet{}
+		a.Spec.NetworkPolicy.IngressControllerPeers = ingressPeers
+		a.Spec.NetworkPolicy.ExtraPeers = map[string][]networkingv1.NetworkPolicyPeer{"repo-server": extraPeers}
+	})
+
+	policies := getNetworkPolicies(a)
+	assert.Equal(t, len(policies), 6)
+
+	prometheus := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{common.ArgoCDKeyPrometheus: testArgoCDName}},
+	}
+	server := policies["argocd-server"]
+	assert.Equal(t, len(server.Spec.Ingress), 2)
+	assert.DeepEqual(t, server.Spec.Ingress[0].From, ingressPeers)
+	assert.DeepEqual(t, server.Spec.Ingress[1].From, []networkingv1.NetworkPolicyPeer{prometheus})
+	assert.Equal(t, *server.Spec.Ingress[1].Ports[0].Port, intstr.FromInt(common.ArgoCDDefaultServerMetricsPort))
+
+	repo := policies["argocd-repo-server"]
+	assert.Equal(t, len(repo.Spec.Ingress), 3)
+	assert.DeepEqual(t, repo.Spec.Ingress[0].From, getNetworkPolicyPodPeers(a, "server", "application-controller", "applicationset-controller"))
+	assert.DeepEqual(t, repo.Spec.Ingress[2].From, extraPeers)
+	assert.Assert(t, repo.Spec.Ingress[2].Ports == nil)
+
+	redisHA := policies["argocd-redis-ha"]
+	assert.DeepEqual(t, redisHA.Spec.Ingress[0].From, getNetworkPolicyPodPeers(a, "redis-ha", "redis-ha-haproxy"))
+	assert.Equal(t, len(redisHA.Spec.Ingress[0].Ports), 2)
+}
+
+func TestReconcileArgoCD_reconcileNetworkPolicies(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.NetworkPolicy.Enabled = true
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileNetworkPolicies(a))
+	np := &networkingv1.NetworkPolicy{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha", Namespace: testNamespace}, np))
+	assert.Equal(t, np.Labels[common.ArgoCDKeyName], "argocd-redis-ha")
+
+	// The Redis HA policy is removed with Redis HA.
+	a.Spec.HA.Enabled = false
+	assert.NilError(t, r.reconcileNetworkPolicies(a))
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha", Namespace: testNamespace}, &networkingv1.NetworkPolicy{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, np))
+
+	// All policies are removed when the option is disabled.
+	a.Spec.NetworkPolicy.Enabled = false
+	assert.NilError(t, r.reconcileNetworkPolicies(a))
+	list := &networkingv1.NetworkPolicyList{}
+	assert.NilError(t, r.client.List(context.TODO(), list))
+	assert.Equal(t, len(list.Items), 0)
+}
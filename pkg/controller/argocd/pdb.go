@@ -0,0 +1,131 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// newPodDisruptionBudgetWithSuffix returns a new PodDisruptionBudget instance with the given suffix for the given
+// ArgoCD.
+func newPodDisruptionBudgetWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) *policyv1beta1.PodDisruptionBudget {
+	name := nameWithSuffix(suffix, cr)
+	lbls := labelsForCluster(cr)
+	lbls[common.ArgoCDKeyName] = name
+
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+			Labels:    lbls,
+		},
+	}
+}
+
+// getRedisHAPodDisruptionBudgets will return the PodDisruptionBudgets for the Redis HA server and HAProxy pods of the
+// given ArgoCD. A majority of the Redis HA replicas is kept available, so that the Sentinels can still fail over the
+// Redis master during a voluntary disruption.
+func getRedisHAPodDisruptionBudgets(cr *argoprojv1b1.ArgoCD) []*policyv1beta1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(int(getRedisHAMajority(cr)))
+	server := newPodDisruptionBudgetWithSuffix("redis-ha-server", cr)
+	server.Spec = policyv1beta1.PodDisruptionBudgetSpec{
+		MinAvailable: &minAvailable,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				common.ArgoCDKeyName: nameWithSuffix("redis-ha", cr),
+			},
+		},
+	}
+
+	maxUnavailable := intstr.FromInt(1)
+	haproxy := newPodDisruptionBudgetWithSuffix("redis-ha-haproxy", cr)
+	haproxy.Spec = policyv1beta1.PodDisruptionBudgetSpec{
+		MaxUnavailable: &maxUnavailable,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				common.ArgoCDKeyName: nameWithSuffix("redis-ha-haproxy", cr),
+			},
+		},
+	}
+
+	return []*policyv1beta1.PodDisruptionBudget{server, haproxy}
+}
+
+// reconcileRedisHAPodDisruptionBudgets will ensure that the PodDisruptionBudgets are present for the Redis HA pods of
+// the given ArgoCD, and removes them when HA is disabled or an external Redis is used.
+func (r *ReconcileArgoCD) reconcileRedisHAPodDisruptionBudgets(cr *argoprojv1b1.ArgoCD) error {
+	for _, pdb := range getRedisHAPodDisruptionBudgets(cr) {
+		if !cr.Spec.HA.Enabled || isRedisExternal(cr) {
+			if argoutil.IsObjectFound(r.client, cr.Namespace, pdb.Name, pdb) {
+				log.Info(fmt.Sprintf("deleting pod disruption budget [%s], redis ha is not used", pdb.Name))
+				if err := r.client.Delete(context.TODO(), pdb); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := r.applyObject(cr, pdb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getComponentPodDisruptionBudget will return the PodDisruptionBudget for the pods of the Deployment with the given
+// suffix for the given ArgoCD. One pod may be unavailable when the given options set no budget.
+func getComponentPodDisruptionBudget(suffix string, budget *argoprojv1b1.ArgoCDPodDisruptionBudgetSpec, cr *argoprojv1b1.ArgoCD) *policyv1beta1.PodDisruptionBudget {
+	pdb := newPodDisruptionBudgetWithSuffix(suffix, cr)
+	pdb.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			common.ArgoCDKeyName: nameWithSuffix(suffix, cr),
+		},
+	}
+
+	if budget != nil {
+		budget = budget.DeepCopy()
+		pdb.Spec.MaxUnavailable = budget.MaxUnavailable
+		pdb.Spec.MinAvailable = budget.MinAvailable
+	}
+	if pdb.Spec.MaxUnavailable == nil && pdb.Spec.MinAvailable == nil {
+		maxUnavailable := intstr.FromInt(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+	return pdb
+}
+
+// reconcileComponentPodDisruptionBudget will ensure that the PodDisruptionBudget is present for the pods of the
+// Deployment with the given suffix when HA is enabled or the given options are set, and removes it otherwise. The
+// enabled argument indicates whether the Deployment is present.
+func (r *ReconcileArgoCD) reconcileComponentPodDisruptionBudget(suffix string, enabled bool, budget *argoprojv1b1.ArgoCDPodDisruptionBudgetSpec, cr *argoprojv1b1.ArgoCD) error {
+	pdb := getComponentPodDisruptionBudget(suffix, budget, cr)
+	if !enabled || (!cr.Spec.HA.Enabled && budget == nil) {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, pdb.Name, pdb) {
+			log.Info(fmt.Sprintf("deleting pod disruption budget [%s], it is no longer needed", pdb.Name))
+			return r.client.Delete(context.TODO(), pdb)
+		}
+		return nil
+	}
+	return r.applyObject(cr, pdb)
+}
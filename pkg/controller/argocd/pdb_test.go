@@ -0,0 +1,100 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func TestReconcileArgoCD_reconcileRedisHAPodDisruptionBudgets(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.HA.Replicas = int32Ptr(5)
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisHAPodDisruptionBudgets(a))
+
+	server := &policyv1beta1.PodDisruptionBudget{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: testNamespace}, server))
+	assert.Equal(t, *server.Spec.MinAvailable, intstr.FromInt(3))
+	assert.Equal(t, server.Spec.Selector.MatchLabels[common.ArgoCDKeyName], "argocd-redis-ha")
+
+	haproxy := &policyv1beta1.PodDisruptionBudget{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-haproxy", Namespace: testNamespace}, haproxy))
+	assert.Equal(t, *haproxy.Spec.MaxUnavailable, intstr.FromInt(1))
+	assert.Equal(t, haproxy.Spec.Selector.MatchLabels[common.ArgoCDKeyName], "argocd-redis-ha-haproxy")
+
+	// The PodDisruptionBudgets are removed when HA is disabled.
+	a.Spec.HA.Enabled = false
+	assert.NilError(t, r.reconcileRedisHAPodDisruptionBudgets(a))
+	for _, name := range []string{"argocd-redis-ha-server", "argocd-redis-ha-haproxy"} {
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, &policyv1beta1.PodDisruptionBudget{})
+		assert.Assert(t, apierrors.IsNotFound(err))
+	}
+}
+
+func TestReconcileArgoCD_reconcileComponentPodDisruptionBudget(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	key := types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}
+
+	// No budget is created for a single replica without HA.
+	assert.NilError(t, r.reconcileComponentPodDisruptionBudget("server", true, a.Spec.Server.PodDisruptionBudget, a))
+	err := r.client.Get(context.TODO(), key, &policyv1beta1.PodDisruptionBudget{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+
+	// One pod may be unavailable by default when HA is enabled.
+	a.Spec.HA.Enabled = true
+	assert.NilError(t, r.reconcileComponentPodDisruptionBudget("server", true, a.Spec.Server.PodDisruptionBudget, a))
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, pdb))
+	assert.Equal(t, *pdb.Spec.MaxUnavailable, intstr.FromInt(1))
+	assert.Assert(t, pdb.Spec.MinAvailable == nil)
+	assert.Equal(t, pdb.Spec.Selector.MatchLabels[common.ArgoCDKeyName], "argocd-server")
+
+	minAvailable := intstr.FromString("50%")
+	a.Spec.Server.PodDisruptionBudget = &argoprojv1beta1.ArgoCDPodDisruptionBudgetSpec{MinAvailable: &minAvailable}
+	assert.NilError(t, r.reconcileComponentPodDisruptionBudget("server", true, a.Spec.Server.PodDisruptionBudget, a))
+	pdb = &policyv1beta1.PodDisruptionBudget{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, pdb))
+	assert.Equal(t, *pdb.Spec.MinAvailable, minAvailable)
+	assert.Assert(t, pdb.Spec.MaxUnavailable == nil)
+
+	// The budget is removed with the Deployment.
+	assert.NilError(t, r.reconcileComponentPodDisruptionBudget("server", false, a.Spec.Server.PodDisruptionBudget, a))
+	err = r.client.Get(context.TODO(), key, &policyv1beta1.PodDisruptionBudget{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileDexComponent_podDisruptionBudget(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	key := types.NamespacedName{Name: "argocd-dex-server", Namespace: testNamespace}
+
+	// Dex runs a single replica, no budget is created for it when HA is enabled.
+	assert.NilError(t, r.reconcileDexComponent(a))
+	err := r.client.Get(context.TODO(), key, &policyv1beta1.PodDisruptionBudget{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+
+	minAvailable := intstr.FromInt(1)
+	a.Spec.Dex.PodDisruptionBudget = &argoprojv1beta1.ArgoCDPodDisruptionBudgetSpec{MinAvailable: &minAvailable}
+	assert.NilError(t, r.reconcileDexComponent(a))
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, pdb))
+	assert.Equal(t, pdb.Spec.MinAvailable.IntValue(), 1)
+}
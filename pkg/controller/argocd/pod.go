@@ -0,0 +1,148 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+// getPodOverrides will return the pod overrides for the given component label of the given ArgoCD.
+func getPodOverrides(cr *argoprojv1b1.ArgoCD, component string) *argoprojv1b1.ArgoCDPodOverrideSpec {
+	switch component {
+	case "application-controller":
+		return cr.Spec.Controller.PodOverrides
+	case "dex-server":
+		return cr.Spec.Dex.PodOverrides
+	case "grafana":
+		return cr.Spec.Grafana.PodOverrides
+	case "redis":
+		// The Redis Deployment is replaced by the HA StatefulSet and HAProxy Deployment when HA is enabled.
+		if cr.Spec.HA.Enabled {
+			return cr.Spec.HA.PodOverrides
+		}
+		return cr.Spec.Redis.PodOverrides
+	case "repo-server":
+		return cr.Spec.Repo.PodOverrides
+	case "server":
+		return cr.Spec.Server.PodOverrides
+	}
+	return nil
+}
+
+// applyPodOverrides will merge the given overrides into the given pod template.
+func applyPodOverrides(template *corev1.PodTemplateSpec, overrides *argoprojv1b1.ArgoCDPodOverrideSpec) {
+	if overrides == nil {
+		return
+	}
+
+	for k, v := range overrides.Labels {
+		// Labels set by the operator are used by selectors and must not be changed.
+		if _, ok := template.Labels[k]; ok {
+			continue
+		}
+		if template.Labels == nil {
+			template.Labels = make(map[string]string)
+		}
+		template.Labels[k] = v
+	}
+
+	for k, v := range overrides.Annotations {
+		if template.Annotations == nil {
+			template.Annotations = make(map[string]string)
+		}
+		template.Annotations[k] = v
+	}
+
+	spec := &template.Spec
+	if overrides.Affinity != nil {
+		spec.Affinity = overrides.Affinity.DeepCopy()
+	}
+
+	for k, v := range overrides.NodeSelector {
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = make(map[string]string)
+		}
+		spec.NodeSelector[k] = v
+	}
+
+	if overrides.PriorityClassName != "" {
+		spec.PriorityClassName = overrides.PriorityClassName
+	}
+
+	if overrides.SecurityContext != nil {
+		spec.SecurityContext = overrides.SecurityContext.DeepCopy()
+	}
+
+	spec.Tolerations = append(spec.Tolerations, overrides.Tolerations...)
+	spec.TopologySpreadConstraints = append(spec.TopologySpreadConstraints, overrides.TopologySpreadConstraints...)
+
+	for _, v := range overrides.Volumes {
+		spec.Volumes = mergeVolume(spec.Volumes, v)
+	}
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		for _, v := range overrides.Env {
+			container.Env = mergeEnvVar(container.Env, v)
+		}
+		for _, v := range overrides.VolumeMounts {
+			container.VolumeMounts = mergeVolumeMount(container.VolumeMounts, v)
+		}
+		if overrides.ContainerSecurityContext != nil {
+			container.SecurityContext = overrides.ContainerSecurityContext.DeepCopy()
+		}
+	}
+
+	if overrides.ContainerSecurityContext != nil {
+		for i := range spec.InitContainers {
+			spec.InitContainers[i].SecurityContext = overrides.ContainerSecurityContext.DeepCopy()
+		}
+	}
+}
+
+// mergeEnvVar will replace the variable with the same name as the given variable, or append it when not present.
+func mergeEnvVar(vars []corev1.EnvVar, v corev1.EnvVar) []corev1.EnvVar {
+	for i := range vars {
+		if vars[i].Name == v.Name {
+			vars[i] = v
+			return vars
+		}
+	}
+	return append(vars, v)
+}
+
+// mergeVolume will replace the volume with the same name as the given volume, or append it when not present.
+func mergeVolume(volumes []corev1.Volume, v corev1.Volume) []corev1.Volume {
+	for i := range volumes {
+		if volumes[i].Name == v.Name {
+			volumes[i] = v
+			return volumes
+		}
+	}
+	return append(volumes, v)
+}
+
+// mergeVolumeMount will replace the mount with the same path as the given mount, or append it when not present.
+func mergeVolumeMount(mounts []corev1.VolumeMount, m corev1.VolumeMount) []corev1.VolumeMount {
+	for i := range mounts {
+		if mounts[i].MountPath == m.MountPath {
+			mounts[i] = m
+			return mounts
+		}
+	}
+	return append(mounts, m)
+}
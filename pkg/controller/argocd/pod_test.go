@@ -0,0 +1,178 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetPodOverrides(t *testing.T) {
+	overrides := &argoprojv1beta1.ArgoCDPodOverrideSpec{PriorityClassName: "infra"}
+
+	tests := []struct {
+		name      string
+		component string
+		opts      func(*argoprojv1beta1.ArgoCD)
+	}{
+		{"application controller", "application-controller", func(a *argoprojv1beta1.ArgoCD) { a.Spec.Controller.PodOverrides = overrides }},
+		{"dex", "dex-server", func(a *argoprojv1beta1.ArgoCD) { a.Spec.Dex.PodOverrides = overrides }},
+		{"grafana", "grafana", func(a *argoprojv1beta1.ArgoCD) { a.Spec.Grafana.PodOverrides = overrides }},
+		{"redis", "redis", func(a *argoprojv1beta1.ArgoCD) { a.Spec.Redis.PodOverrides = overrides }},
+		{"redis ha", "redis", func(a *argoprojv1beta1.ArgoCD) {
+			a.Spec.HA.Enabled = true
+			a.Spec.HA.PodOverrides = overrides
+			a.Spec.Redis.PodOverrides = &argoprojv1beta1.ArgoCDPodOverrideSpec{}
+		}},
+		{"repo server", "repo-server", func(a *argoprojv1beta1.ArgoCD) { a.Spec.Repo.PodOverrides = overrides }},
+		{"server", "server", func(a *argoprojv1beta1.ArgoCD) { a.Spec.Server.PodOverrides = overrides }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := makeTestArgoCD(tt.opts)
+			assert.Equal(t, getPodOverrides(a, tt.component), overrides)
+		})
+	}
+
+	assert.Assert(t, getPodOverrides(makeTestArgoCD(), "controller") == nil)
+}
+
+func TestApplyPodOverrides(t *testing.T) {
+	seconds := int64(30)
+	template := corev1.PodTemplateSpec{}
+	template.Labels = map[string]string{common.ArgoCDKeyName: "argocd-server"}
+	template.Spec = corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:         "argocd-server",
+			Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: testHTTPProxy}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "tls-certs", MountPath: "/app/config/tls"}},
+		}},
+		InitContainers: []corev1.Container{{Name: "copyutil"}},
+		Volumes:        []corev1.Volume{{Name: "tls-certs"}},
+	}
+
+	overrides := &argoprojv1beta1.ArgoCDPodOverrideSpec{
+		Affinity:                 &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+		Annotations:              map[string]string{"example.com/team": "platform"},
+		ContainerSecurityContext: &corev1.SecurityContext{RunAsNonRoot: boolPtr(true)},
+		Env: []corev1.EnvVar{
+			{Name: "HTTP_PROXY", Value: "proxy.example.com:3128"},
+			{Name: "ARGOCD_LOG_LEVEL", Value: "debug"},
+		},
+		Labels: map[string]string{
+			common.ArgoCDKeyName: "overridden",
+			"example.com/tier":   "infra",
+		},
+		NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+		PriorityClassName: "infra",
+		SecurityContext:   &corev1.PodSecurityContext{FSGroup: &seconds},
+		Tolerations: []corev1.Toleration{{
+			Key:               "node-role.kubernetes.io/infra",
+			Effect:            corev1.TaintEffectNoSchedule,
+			TolerationSeconds: &seconds,
+		}},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       common.ArgoCDKeyHostname,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+		}},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "custom-certs", MountPath: "/app/config/tls"},
+			{Name: "plugins", MountPath: "/plugins"},
+		},
+		Volumes: []corev1.Volume{
+			{Name: "tls-certs", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			{Name: "plugins", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		},
+	}
+
+	applyPodOverrides(&template, overrides)
+
+	want := corev1.PodTemplateSpec{}
+	want.Labels = map[string]string{
+		common.ArgoCDKeyName: "argocd-server",
+		"example.com/tier":   "infra",
+	}
+	want.Annotations = overrides.Annotations
+	want.Spec = corev1.PodSpec{
+		Affinity: overrides.Affinity,
+		Containers: []corev1.Container{{
+			Name: "argocd-server",
+			Env: []corev1.EnvVar{
+				{Name: "HTTP_PROXY", Value: "proxy.example.com:3128"},
+				{Name: "ARGOCD_LOG_LEVEL", Value: "debug"},
+			},
+			SecurityContext: overrides.ContainerSecurityContext,
+			VolumeMounts:    overrides.VolumeMounts,
+		}},
+		InitContainers: []corev1.Container{{
+			Name:            "copyutil",
+			SecurityContext: overrides.ContainerSecurityContext,
+		}},
+		NodeSelector:              overrides.NodeSelector,
+		PriorityClassName:         "infra",
+		SecurityContext:           overrides.SecurityContext,
+		Tolerations:               overrides.Tolerations,
+		TopologySpreadConstraints: overrides.TopologySpreadConstraints,
+		Volumes:                   overrides.Volumes,
+	}
+
+	if diff := cmp.Diff(want, template); diff != "" {
+		t.Fatalf("failed to apply pod overrides:\n%s", diff)
+	}
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_podOverrides(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Repo.PodOverrides = &argoprojv1beta1.ArgoCDPodOverrideSpec{
+			NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+			Env:          []corev1.EnvVar{{Name: "ARGOCD_EXEC_TIMEOUT", Value: "180s"}},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: "argocd-repo-server", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.NodeSelector, map[string]string{"node-role.kubernetes.io/infra": ""})
+	env := proxyEnvVars(getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a)...)
+	assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Env, append(env, corev1.EnvVar{Name: "ARGOCD_EXEC_TIMEOUT", Value: "180s"}))
+
+	// Removing the overrides should remove them from the Deployment.
+	a.Spec.Repo.PodOverrides = nil
+	assert.NilError(t, r.reconcileRepoDeployment(a))
+
+	deployment = &appsv1.Deployment{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, deployment))
+	assert.Assert(t, deployment.Spec.Template.Spec.NodeSelector == nil)
+	assert.Equal(t, len(deployment.Spec.Template.Spec.Containers[0].Env), len(env))
+}
+
+func TestReconcileArgoCD_reconcileApplicationController_podOverrides(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Controller.PodOverrides = &argoprojv1beta1.ArgoCDPodOverrideSpec{
+			PriorityClassName: "system-cluster-critical",
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	key := types.NamespacedName{Name: "argocd-application-controller", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ss))
+	assert.Equal(t, ss.Spec.Template.Spec.PriorityClassName, "system-cluster-critical")
+}
@@ -1,7 +1,7 @@
 package argocd
 
 import (
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	v1 "k8s.io/api/rbac/v1"
 )
 
@@ -22,7 +22,7 @@ func policyRuleForApplicationController() []v1.PolicyRule {
 	}
 }
 
-func policyRuleForRedisHa(cr *argoprojv1alpha1.ArgoCD) []v1.PolicyRule {
+func policyRuleForRedisHa(cr *argoprojv1beta1.ArgoCD) []v1.PolicyRule {
 
 	rules := []v1.PolicyRule{
 		{
@@ -18,18 +18,17 @@ import (
 	"context"
 	"fmt"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 var prometheusAPIFound = false
 
 // getPrometheusHost will return the hostname value for Prometheus.
-func getPrometheusHost(cr *argoprojv1a1.ArgoCD) string {
+func getPrometheusHost(cr *argoprojv1b1.ArgoCD) string {
 	host := nameWithSuffix("prometheus", cr)
 	if len(cr.Spec.Prometheus.Host) > 0 {
 		host = cr.Spec.Prometheus.Host
@@ -38,7 +37,7 @@ func getPrometheusHost(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getPrometheusSize will return the size value for the Prometheus replica count.
-func getPrometheusReplicas(cr *argoprojv1a1.ArgoCD) *int32 {
+func getPrometheusReplicas(cr *argoprojv1b1.ArgoCD) *int32 {
 	replicas := common.ArgoCDDefaultPrometheusReplicas
 	if cr.Spec.Prometheus.Size != nil {
 		if *cr.Spec.Prometheus.Size >= 0 && *cr.Spec.Prometheus.Size != replicas {
@@ -53,25 +52,6 @@ func IsPrometheusAPIAvailable() bool {
 	return prometheusAPIFound
 }
 
-// hasPrometheusSpecChanged will return true if the supported properties differs in the actual versus the desired state.
-func hasPrometheusSpecChanged(actual *monitoringv1.Prometheus, desired *argoprojv1a1.ArgoCD) bool {
-	// Replica count
-	if desired.Spec.Prometheus.Size != nil && *desired.Spec.Prometheus.Size >= 0 { // Valid replica count specified in desired state
-		if actual.Spec.Replicas != nil { // Actual replicas value is set
-			if *actual.Spec.Replicas != *desired.Spec.Prometheus.Size {
-				return true
-			}
-		} else if *desired.Spec.Prometheus.Size != common.ArgoCDDefaultPrometheusReplicas { // Actual replicas value is NOT set, but desired replicas differs from the default
-			return true
-		}
-	} else { // Replica count NOT specified in desired state
-		if actual.Spec.Replicas != nil && *actual.Spec.Replicas != common.ArgoCDDefaultPrometheusReplicas {
-			return true
-		}
-	}
-	return false
-}
-
 // verifyPrometheusAPI will verify that the Prometheus API is present.
 func verifyPrometheusAPI() error {
 	found, err := argoutil.VerifyAPI(monitoringv1.SchemeGroupVersion.Group, monitoringv1.SchemeGroupVersion.Version)
@@ -83,7 +63,7 @@ func verifyPrometheusAPI() error {
 }
 
 // newPrometheus returns a new Prometheus instance for the given ArgoCD.
-func newPrometheus(cr *argoprojv1a1.ArgoCD) *monitoringv1.Prometheus {
+func newPrometheus(cr *argoprojv1b1.ArgoCD) *monitoringv1.Prometheus {
 	return &monitoringv1.Prometheus{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -94,7 +74,7 @@ func newPrometheus(cr *argoprojv1a1.ArgoCD) *monitoringv1.Prometheus {
 }
 
 // newServiceMonitor returns a new ServiceMonitor instance.
-func newServiceMonitor(cr *argoprojv1a1.ArgoCD) *monitoringv1.ServiceMonitor {
+func newServiceMonitor(cr *argoprojv1b1.ArgoCD) *monitoringv1.ServiceMonitor {
 	return &monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -105,7 +85,7 @@ func newServiceMonitor(cr *argoprojv1a1.ArgoCD) *monitoringv1.ServiceMonitor {
 }
 
 // newServiceMonitorWithName returns a new ServiceMonitor instance for the given ArgoCD using the given name.
-func newServiceMonitorWithName(name string, cr *argoprojv1a1.ArgoCD) *monitoringv1.ServiceMonitor {
+func newServiceMonitorWithName(name string, cr *argoprojv1b1.ArgoCD) *monitoringv1.ServiceMonitor {
 	svcmon := newServiceMonitor(cr)
 	svcmon.ObjectMeta.Name = name
 
@@ -118,22 +98,18 @@ func newServiceMonitorWithName(name string, cr *argoprojv1a1.ArgoCD) *monitoring
 }
 
 // newServiceMonitorWithSuffix returns a new ServiceMonitor instance for the given ArgoCD using the given suffix.
-func newServiceMonitorWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *monitoringv1.ServiceMonitor {
+func newServiceMonitorWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) *monitoringv1.ServiceMonitor {
 	return newServiceMonitorWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), cr)
 }
 
 // reconcileMetricsServiceMonitor will ensure that the ServiceMonitor is present for the ArgoCD metrics Service.
-func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1b1.ArgoCD) error {
 	sm := newServiceMonitorWithSuffix(common.ArgoCDKeyMetrics, cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, sm.Name, sm) {
-		if !cr.Spec.Prometheus.Enabled {
+	if !cr.Spec.Prometheus.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, sm.Name, sm) {
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.client.Delete(context.TODO(), sm)
 		}
-		return nil // ServiceMonitor found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled {
 		return nil // Prometheus not enabled, do nothing.
 	}
 
@@ -148,28 +124,17 @@ func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, sm, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), sm)
+	return r.applyObject(cr, sm)
 }
 
 // reconcilePrometheus will ensure that Prometheus is present for ArgoCD metrics.
-func (r *ReconcileArgoCD) reconcilePrometheus(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcilePrometheus(cr *argoprojv1b1.ArgoCD) error {
 	prometheus := newPrometheus(cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, prometheus.Name, prometheus) {
-		if !cr.Spec.Prometheus.Enabled {
+	if !cr.Spec.Prometheus.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, prometheus.Name, prometheus) {
 			// Prometheus exists but enabled flag has been set to false, delete the Prometheus
 			return r.client.Delete(context.TODO(), prometheus)
 		}
-		if hasPrometheusSpecChanged(prometheus, cr) {
-			prometheus.Spec.Replicas = cr.Spec.Prometheus.Size
-			return r.client.Update(context.TODO(), prometheus)
-		}
-		return nil // Prometheus found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled {
 		return nil // Prometheus not enabled, do nothing.
 	}
 
@@ -177,24 +142,17 @@ func (r *ReconcileArgoCD) reconcilePrometheus(cr *argoprojv1a1.ArgoCD) error {
 	prometheus.Spec.ServiceAccountName = "prometheus-k8s"
 	prometheus.Spec.ServiceMonitorSelector = &metav1.LabelSelector{}
 
-	if err := controllerutil.SetControllerReference(cr, prometheus, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), prometheus)
+	return r.applyObject(cr, prometheus)
 }
 
 // reconcileRepoServerServiceMonitor will ensure that the ServiceMonitor is present for the Repo Server metrics Service.
-func (r *ReconcileArgoCD) reconcileRepoServerServiceMonitor(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRepoServerServiceMonitor(cr *argoprojv1b1.ArgoCD) error {
 	sm := newServiceMonitorWithSuffix("repo-server-metrics", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, sm.Name, sm) {
-		if !cr.Spec.Prometheus.Enabled {
+	if !cr.Spec.Prometheus.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, sm.Name, sm) {
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.client.Delete(context.TODO(), sm)
 		}
-		return nil // ServiceMonitor found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled {
 		return nil // Prometheus not enabled, do nothing.
 	}
 
@@ -209,24 +167,17 @@ func (r *ReconcileArgoCD) reconcileRepoServerServiceMonitor(cr *argoprojv1a1.Arg
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, sm, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), sm)
+	return r.applyObject(cr, sm)
 }
 
 // reconcileServerMetricsServiceMonitor will ensure that the ServiceMonitor is present for the ArgoCD Server metrics Service.
-func (r *ReconcileArgoCD) reconcileServerMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileServerMetricsServiceMonitor(cr *argoprojv1b1.ArgoCD) error {
 	sm := newServiceMonitorWithSuffix("server-metrics", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, sm.Name, sm) {
-		if !cr.Spec.Prometheus.Enabled {
+	if !cr.Spec.Prometheus.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, sm.Name, sm) {
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.client.Delete(context.TODO(), sm)
 		}
-		return nil // ServiceMonitor found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled {
 		return nil // Prometheus not enabled, do nothing.
 	}
 
@@ -241,8 +192,5 @@ func (r *ReconcileArgoCD) reconcileServerMetricsServiceMonitor(cr *argoprojv1a1.
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, sm, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), sm)
+	return r.applyObject(cr, sm)
 }
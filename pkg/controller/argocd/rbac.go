@@ -0,0 +1,216 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+	"github.com/argoproj-labs/argocd-operator/pkg/validation"
+)
+
+var userAPIFound = false
+
+// IsUserAPIAvailable returns true if the OpenShift user API is present.
+func IsUserAPIAvailable() bool {
+	return userAPIFound
+}
+
+// verifyUserAPI will verify that the OpenShift user API is present.
+func verifyUserAPI() error {
+	found, err := argoutil.VerifyAPI(userv1.GroupName, userv1.GroupVersion.Version)
+	if err != nil {
+		return err
+	}
+	userAPIFound = found
+	return nil
+}
+
+// isRBACPolicyManaged returns true if the operator renders the RBAC policy CSV for the given ArgoCD. The policy CSV
+// in the RBAC ConfigMap is left as is when none of the policy options are set.
+func isRBACPolicyManaged(cr *argoprojv1b1.ArgoCD) bool {
+	return cr.Spec.RBAC.Policy != nil || len(cr.Spec.RBAC.Roles) > 0 || len(cr.Spec.RBAC.GroupBindings) > 0 ||
+		len(cr.Spec.RBAC.GroupMappings) > 0 || cr.Spec.RBAC.PolicyConfigMapSelector != nil
+}
+
+// getRBACRolesPolicy will return the policy CSV lines for the typed roles and group bindings of the given ArgoCD.
+func getRBACRolesPolicy(cr *argoprojv1b1.ArgoCD) string {
+	lines := make([]string, 0)
+	for _, role := range cr.Spec.RBAC.Roles {
+		for _, permission := range role.Permissions {
+			effect := common.ArgoCDRBACEffectAllow
+			if len(permission.Effect) > 0 {
+				effect = permission.Effect
+			}
+			lines = append(lines, fmt.Sprintf("p, %s%s, %s, %s, %s, %s", common.ArgoCDRBACRolePrefix, role.Name,
+				permission.Resource, permission.Action, permission.Object, effect))
+		}
+	}
+
+	for _, binding := range cr.Spec.RBAC.GroupBindings {
+		lines = append(lines, fmt.Sprintf("g, %s, %s%s", binding.Group, common.ArgoCDRBACRolePrefix, binding.Role))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isRBACGroupMapped returns true if the given group is named or selected by the given group mapping.
+func isRBACGroupMapped(mapping *argoprojv1b1.ArgoCDRBACGroupMappingSpec, group metav1.Object) bool {
+	for _, name := range mapping.Groups {
+		if name == group.GetName() {
+			return true
+		}
+	}
+
+	if mapping.Selector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(mapping.Selector)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("invalid rbac group mapping selector for role %s", mapping.Role))
+		return false
+	}
+	return selector.Matches(labels.Set(group.GetLabels()))
+}
+
+// getRBACGroupMappingsPolicy will return the policy CSV lines binding the mapped groups to their roles for the given
+// ArgoCD. On OpenShift, only the Groups that exist and have members are bound. Without the OpenShift user API, the
+// named groups are bound as they are and the selectors are ignored.
+func (r *ReconcileArgoCD) getRBACGroupMappingsPolicy(cr *argoprojv1b1.ArgoCD) (string, error) {
+	if len(cr.Spec.RBAC.GroupMappings) == 0 {
+		return "", nil
+	}
+
+	groups := &userv1.GroupList{}
+	if IsUserAPIAvailable() {
+		if err := r.client.List(context.TODO(), groups); err != nil {
+			return "", err
+		}
+	}
+
+	lines := make([]string, 0)
+	found := make(map[string]bool)
+	for i := range cr.Spec.RBAC.GroupMappings {
+		mapping := &cr.Spec.RBAC.GroupMappings[i]
+
+		names := make([]string, 0)
+		if !IsUserAPIAvailable() {
+			names = append(names, mapping.Groups...)
+		}
+		for j := range groups.Items {
+			group := &groups.Items[j]
+			if len(group.Users) > 0 && isRBACGroupMapped(mapping, group) {
+				names = append(names, group.Name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			line := fmt.Sprintf("g, %s, %s%s", name, common.ArgoCDRBACRolePrefix, mapping.Role)
+			if !found[line] {
+				found[line] = true
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// getRBACPolicyConfigMaps will return the ConfigMaps holding the RBAC policy fragments for the given ArgoCD, sorted
+// by name.
+func (r *ReconcileArgoCD) getRBACPolicyConfigMaps(cr *argoprojv1b1.ArgoCD) ([]corev1.ConfigMap, error) {
+	if cr.Spec.RBAC.PolicyConfigMapSelector == nil {
+		return nil, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cr.Spec.RBAC.PolicyConfigMapSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &corev1.ConfigMapList{}
+	opts := &client.ListOptions{
+		LabelSelector: selector,
+		Namespace:     cr.Namespace,
+	}
+	if err := r.client.List(context.TODO(), list, opts); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list.Items, nil
+}
+
+// getRBACPolicyCSV will return the RBAC policy CSV for the given ArgoCD: the Policy, followed by the typed roles and
+// group bindings, the mapped groups, and the policy fragments of the selected ConfigMaps. A fragment that is not valid
+// is left out and reported with an Event, the policy is validated before it is returned.
+func (r *ReconcileArgoCD) getRBACPolicyCSV(cr *argoprojv1b1.ArgoCD) (string, error) {
+	policy := getRBACPolicy(cr)
+	add := func(section string) {
+		if len(policy) > 0 && !strings.HasSuffix(policy, "\n") {
+			policy += "\n"
+		}
+		policy += section
+	}
+
+	if roles := getRBACRolesPolicy(cr); len(roles) > 0 {
+		add(roles)
+	}
+
+	mappings, err := r.getRBACGroupMappingsPolicy(cr)
+	if err != nil {
+		return "", err
+	}
+	if len(mappings) > 0 {
+		add(mappings)
+	}
+
+	cms, err := r.getRBACPolicyConfigMaps(cr)
+	if err != nil {
+		return "", err
+	}
+
+	for _, cm := range cms {
+		fragment := cm.Data[common.ArgoCDKeyRBACPolicyCSV]
+		if len(strings.TrimSpace(fragment)) == 0 {
+			continue
+		}
+
+		if err := validation.ValidateRBACPolicy(fragment); err != nil {
+			log.Info(fmt.Sprintf("rbac policy fragment in configmap [%s] is not valid, leaving it out", cm.Name))
+			r.recorder.Event(cr, corev1.EventTypeWarning, argoprojv1b1.ArgoCDReasonInvalidRBACPolicy,
+				fmt.Sprintf("left out the rbac policy fragment in configmap [%s]: %v", cm.Name, err))
+			continue
+		}
+		add(fmt.Sprintf("# %s\n%s", cm.Name, fragment))
+	}
+
+	if err := validation.ValidateRBACPolicy(policy); err != nil {
+		return "", err
+	}
+	return policy, nil
+}
@@ -0,0 +1,151 @@
+package argocd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	userv1 "github.com/openshift/api/user/v1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func enableUserAPI(t *testing.T) {
+	userAPIFound = true
+	assert.NilError(t, userv1.AddToScheme(scheme.Scheme))
+	t.Cleanup(func() {
+		userAPIFound = false
+	})
+}
+
+func makeTestGroup(name string, lbls map[string]string, users ...string) *userv1.Group {
+	return &userv1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: lbls},
+		Users:      users,
+	}
+}
+
+func makeTestRBACPolicyConfigMap(name string, policy string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"argocd.example.com/rbac": "true"},
+		},
+		Data: map[string]string{common.ArgoCDKeyRBACPolicyCSV: policy},
+	}
+}
+
+func TestGetRBACRolesPolicy(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.RBAC.Roles = []argoprojv1beta1.ArgoCDRBACRoleSpec{{
+			Name: "deployer",
+			Permissions: []argoprojv1beta1.ArgoCDRBACPermissionSpec{
+				{Resource: "applications", Action: "sync", Object: "team-a/*"},
+				{Resource: "applications", Action: "delete", Object: "*", Effect: "deny"},
+			},
+		}}
+		a.Spec.RBAC.GroupBindings = []argoprojv1beta1.ArgoCDRBACGroupBindingSpec{
+			{Group: "team-a", Role: "deployer"},
+			{Group: "auditors", Role: "readonly"},
+		}
+	})
+
+	assert.Equal(t, getRBACRolesPolicy(a), strings.Join([]string{
+		"p, role:deployer, applications, sync, team-a/*, allow",
+		"p, role:deployer, applications, delete, *, deny",
+		"g, team-a, role:deployer",
+		"g, auditors, role:readonly",
+	}, "\n"))
+}
+
+func TestReconcileArgoCD_reconcileRBAC_policyConfigMaps(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	policy := "g, cluster-admins, role:admin\n"
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.RBAC.Policy = &policy
+		a.Spec.RBAC.GroupBindings = []argoprojv1beta1.ArgoCDRBACGroupBindingSpec{{Group: "auditors", Role: "readonly"}}
+		a.Spec.RBAC.PolicyConfigMapSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"argocd.example.com/rbac": "true"},
+		}
+	})
+	teamB := makeTestRBACPolicyConfigMap("team-b", "g, team-b, role:readonly")
+	teamA := makeTestRBACPolicyConfigMap("team-a", "p, role:team-a, applications, *, team-a/*, allow\ng, team-a, role:team-a\n")
+	invalid := makeTestRBACPolicyConfigMap("team-c", "p, role:team-c, applications, *")
+	r := makeTestReconciler(t, a, teamB, teamA, invalid)
+
+	assert.NilError(t, r.reconcileRBAC(a))
+	cm := &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRBACConfigMapName, Namespace: testNamespace}, cm))
+	assert.Equal(t, cm.Data[common.ArgoCDKeyRBACPolicyCSV], strings.Join([]string{
+		"g, cluster-admins, role:admin",
+		"g, auditors, role:readonly",
+		"# team-a",
+		"p, role:team-a, applications, *, team-a/*, allow",
+		"g, team-a, role:team-a",
+		"# team-b",
+		"g, team-b, role:readonly",
+	}, "\n"))
+
+	// The fragment that is not valid is reported.
+	recorder := r.recorder.(*record.FakeRecorder)
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Assert(t, strings.Contains(<-recorder.Events, "configmap [team-c]"))
+
+	// The fragments are removed with their ConfigMaps.
+	assert.NilError(t, r.client.Delete(context.TODO(), teamA))
+	assert.NilError(t, r.reconcileRBAC(a))
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRBACConfigMapName, Namespace: testNamespace}, cm))
+	assert.Assert(t, !strings.Contains(cm.Data[common.ArgoCDKeyRBACPolicyCSV], "team-a"))
+}
+
+func TestReconcileArgoCD_getRBACGroupMappingsPolicy(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.RBAC.GroupMappings = []argoprojv1beta1.ArgoCDRBACGroupMappingSpec{
+			{Groups: []string{"cluster-admins"}, Role: "admin"},
+			{
+				Groups:   []string{"team-a"},
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"argocd.example.com/team": "true"}},
+				Role:     "readonly",
+			},
+		}
+	})
+
+	// The named groups are bound as they are without the OpenShift user API.
+	r := makeTestReconciler(t, a)
+	policy, err := r.getRBACGroupMappingsPolicy(a)
+	assert.NilError(t, err)
+	assert.Equal(t, policy, "g, cluster-admins, role:admin\ng, team-a, role:readonly")
+
+	// Only the Groups with members are bound on OpenShift.
+	enableUserAPI(t)
+	team := map[string]string{"argocd.example.com/team": "true"}
+	r = makeTestReconciler(t, a,
+		makeTestGroup("cluster-admins", nil),
+		makeTestGroup("team-a", team, "alice"),
+		makeTestGroup("team-b", team, "bob"),
+		makeTestGroup("team-c", team),
+		makeTestGroup("other", nil, "carol"),
+	)
+	policy, err = r.getRBACGroupMappingsPolicy(a)
+	assert.NilError(t, err)
+	assert.Equal(t, policy, "g, team-a, role:readonly\ng, team-b, role:readonly")
+
+	// The policy is updated when a mapped Group gains members.
+	admins := &userv1.Group{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster-admins"}, admins))
+	admins.Users = []string{"dave"}
+	assert.NilError(t, r.client.Update(context.TODO(), admins))
+	policy, err = r.getRBACGroupMappingsPolicy(a)
+	assert.NilError(t, err)
+	assert.Equal(t, policy, "g, cluster-admins, role:admin\ng, team-a, role:readonly\ng, team-b, role:readonly")
+}
@@ -0,0 +1,363 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-password/password"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// isRedisExternal returns true if the Argo CD components use an external Redis for the given ArgoCD.
+func isRedisExternal(cr *argoprojv1b1.ArgoCD) bool {
+	return cr.Spec.Redis.External != nil
+}
+
+// isRedisAuthEnabled returns true if Redis requires a password for the given ArgoCD.
+func isRedisAuthEnabled(cr *argoprojv1b1.ArgoCD) bool {
+	if isRedisExternal(cr) {
+		return cr.Spec.Redis.External.PasswordSecretRef != nil
+	}
+	return !cr.Spec.Redis.DisableAuth
+}
+
+// isRedisTLSEnabled returns true if Redis, Sentinel and HAProxy serve TLS for the given ArgoCD.
+func isRedisTLSEnabled(cr *argoprojv1b1.ArgoCD) bool {
+	return cr.Spec.Redis.EnableTLS
+}
+
+// generateRedisPassword will generate and return the Redis password.
+func generateRedisPassword() ([]byte, error) {
+	pass, err := password.Generate(
+		common.ArgoCDDefaultRedisPasswordLength,
+		common.ArgoCDDefaultRedisPasswordNumDigits,
+		common.ArgoCDDefaultRedisPasswordNumSymbols,
+		false, false)
+
+	return []byte(pass), err
+}
+
+// getRedisAuthSecretName will return the name of the Secret that holds the Redis password for the given ArgoCD.
+func getRedisAuthSecretName(cr *argoprojv1b1.ArgoCD) string {
+	return nameWithSuffix("redis", cr)
+}
+
+// getRedisAuthEnv will return an environment variable with the given name that is set to the Redis password, or no
+// environment variables when Redis authentication is disabled for the given ArgoCD.
+func getRedisAuthEnv(name string, cr *argoprojv1b1.ArgoCD) []corev1.EnvVar {
+	if !isRedisAuthEnabled(cr) {
+		return nil
+	}
+
+	ref := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: getRedisAuthSecretName(cr),
+		},
+		Key: common.ArgoCDKeyRedisAuth,
+	}
+	if isRedisExternal(cr) {
+		ref = cr.Spec.Redis.External.PasswordSecretRef.DeepCopy()
+	}
+
+	return []corev1.EnvVar{{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: ref,
+		},
+	}}
+}
+
+// getRedisExternalAddress will return the address of the external Redis server, or of the external Redis Sentinel
+// when a master group is set, for the given ArgoCD.
+func getRedisExternalAddress(cr *argoprojv1b1.ArgoCD) string {
+	external := cr.Spec.Redis.External
+	port := external.Port
+	if port == 0 {
+		port = common.ArgoCDDefaultRedisPort
+		if len(external.SentinelMaster) > 0 {
+			port = common.ArgoCDDefaultRedisSentinelPort
+		}
+	}
+	return net.JoinHostPort(external.Host, strconv.Itoa(int(port)))
+}
+
+// getRedisServerArgs will return the arguments with the address of Redis for the Argo CD components.
+func getRedisServerArgs(cr *argoprojv1b1.ArgoCD) []string {
+	if isRedisExternal(cr) && len(cr.Spec.Redis.External.SentinelMaster) > 0 {
+		return []string{
+			"--sentinel", getRedisServerAddress(cr),
+			"--sentinelmaster", cr.Spec.Redis.External.SentinelMaster,
+		}
+	}
+	return []string{"--redis", getRedisServerAddress(cr)}
+}
+
+// getRedisArgs will return the arguments for the Redis server when HA is disabled for the given ArgoCD.
+func getRedisArgs(cr *argoprojv1b1.ArgoCD) []string {
+	args := []string{
+		"--save",
+		"",
+		"--appendonly",
+		"no",
+	}
+
+	if isRedisAuthEnabled(cr) {
+		// Kubernetes expands the reference to the environment variable of the container.
+		args = append(args, "--requirepass", fmt.Sprintf("$(%s)", common.ArgoCDRedisPasswordEnvName))
+	}
+
+	if isRedisTLSEnabled(cr) {
+		tlsPath := common.ArgoCDDefaultRedisTLSPath
+		args = append(args,
+			"--port", "0",
+			"--tls-port", strconv.Itoa(common.ArgoCDDefaultRedisPort),
+			"--tls-cert-file", fmt.Sprintf("%s/%s", tlsPath, common.ArgoCDKeyTLSCert),
+			"--tls-key-file", fmt.Sprintf("%s/%s", tlsPath, common.ArgoCDKeyTLSPrivateKey),
+			"--tls-ca-cert-file", fmt.Sprintf("%s/%s", tlsPath, common.ArgoCDKeyTLSCACert),
+			"--tls-auth-clients", "no")
+	}
+	return args
+}
+
+// getRedisClientArgs will return the arguments that configure the connection to Redis for the Argo CD components.
+func getRedisClientArgs(cr *argoprojv1b1.ArgoCD) []string {
+	if !isRedisTLSEnabled(cr) {
+		return nil
+	}
+
+	args := []string{"--redis-use-tls"}
+	if cr.Spec.Redis.DisableTLSVerification {
+		return append(args, "--redis-insecure-skip-tls-verify")
+	}
+	if len(getRedisTLSSecretName(cr)) == 0 {
+		return args // The system CA certificates verify the external Redis.
+	}
+	return append(args, "--redis-ca-certificate", fmt.Sprintf("%s/%s", common.ArgoCDDefaultRedisTLSPath, common.ArgoCDKeyTLSCACert))
+}
+
+// getRedisTLSSecretName will return the name of the Secret with the Redis certificates for the given ArgoCD, or an
+// empty name when no Secret is set for the external Redis.
+func getRedisTLSSecretName(cr *argoprojv1b1.ArgoCD) string {
+	if !isRedisExternal(cr) {
+		return common.ArgoCDRedisServerTLSSecretName
+	}
+	if cr.Spec.Redis.External.TLSSecretRef == nil {
+		return ""
+	}
+	return cr.Spec.Redis.External.TLSSecretRef.Name
+}
+
+// getRedisTLSVolumes will return the volume for the Redis TLS secret, or no volumes when TLS is disabled for Redis.
+func getRedisTLSVolumes(cr *argoprojv1b1.ArgoCD) []corev1.Volume {
+	if !isRedisTLSEnabled(cr) || len(getRedisTLSSecretName(cr)) == 0 {
+		return nil
+	}
+	return []corev1.Volume{{
+		Name: common.ArgoCDRedisServerTLSSecretName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: getRedisTLSSecretName(cr),
+			},
+		},
+	}}
+}
+
+// getRedisTLSVolumeMounts will return the mount of the Redis TLS secret, or no mounts when TLS is disabled for Redis.
+func getRedisTLSVolumeMounts(cr *argoprojv1b1.ArgoCD) []corev1.VolumeMount {
+	if !isRedisTLSEnabled(cr) || len(getRedisTLSSecretName(cr)) == 0 {
+		return nil
+	}
+	return []corev1.VolumeMount{{
+		Name:      common.ArgoCDRedisServerTLSSecretName,
+		MountPath: common.ArgoCDDefaultRedisTLSPath,
+		ReadOnly:  true,
+	}}
+}
+
+// getRedisHATemplateVars will return the parameters for the Redis HA configuration templates for the given ArgoCD.
+// The parameters for the disabled features are left unset, they are empty and false in the templates. The replicas are
+// the indexes of the Redis HA replicas, the templates range over them.
+func getRedisHATemplateVars(cr *argoprojv1b1.ArgoCD) map[string]interface{} {
+	replicas := make([]int, *getRedisHAReplicas(cr))
+	for i := range replicas {
+		replicas[i] = i
+	}
+
+	vars := map[string]interface{}{
+		"Persistence": cr.Spec.HA.VolumeClaimTemplate != nil,
+		"Quorum":      getRedisHAQuorum(cr),
+		"Replicas":    replicas,
+		"ServiceName": nameWithSuffix("redis-ha", cr),
+	}
+	if isRedisAuthEnabled(cr) {
+		vars["AuthEnabled"] = true
+	}
+	if isRedisTLSEnabled(cr) {
+		vars["TLSEnabled"] = true
+		vars["TLSPath"] = common.ArgoCDDefaultRedisTLSPath
+	}
+	return vars
+}
+
+// deleteRedisWorkloads will delete the Redis Deployment, HAProxy Deployment and HA StatefulSet of the given ArgoCD.
+func (r *ReconcileArgoCD) deleteRedisWorkloads(cr *argoprojv1b1.ArgoCD) error {
+	for _, deploy := range []*appsv1.Deployment{
+		newDeploymentWithSuffix("redis", "redis", cr),
+		newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr),
+	} {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) {
+			log.Info(fmt.Sprintf("deleting deployment [%s], an external redis is used", deploy.Name))
+			if err := r.client.Delete(context.TODO(), deploy); err != nil {
+				return err
+			}
+		}
+	}
+
+	ss := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, ss.Name, ss) {
+		log.Info(fmt.Sprintf("deleting statefulset [%s], an external redis is used", ss.Name))
+		return r.client.Delete(context.TODO(), ss)
+	}
+	return nil
+}
+
+// getRedisExternalTLSConfig will return the TLS configuration for the connection to the external Redis of the given
+// ArgoCD, or nil when TLS is disabled.
+func (r *ReconcileArgoCD) getRedisExternalTLSConfig(cr *argoprojv1b1.ArgoCD) (*tls.Config, error) {
+	if !isRedisTLSEnabled(cr) {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: cr.Spec.Redis.DisableTLSVerification,
+		ServerName:         cr.Spec.Redis.External.Host,
+	}
+
+	if name := getRedisTLSSecretName(cr); len(name) > 0 && !config.InsecureSkipVerify {
+		secret, err := argoutil.FetchSecret(r.client, cr.ObjectMeta, name)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(secret.Data[common.ArgoCDKeyTLSCACert]) {
+			return nil, fmt.Errorf("no CA certificate found in secret [%s]", name)
+		}
+	}
+	return config, nil
+}
+
+// getRedisExternalPassword will return the password for the external Redis of the given ArgoCD, or an empty password
+// when none is set.
+func (r *ReconcileArgoCD) getRedisExternalPassword(cr *argoprojv1b1.ArgoCD) (string, error) {
+	ref := cr.Spec.Redis.External.PasswordSecretRef
+	if ref == nil {
+		return "", nil
+	}
+
+	secret, err := argoutil.FetchSecret(r.client, cr.ObjectMeta, ref.Name)
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key [%s] not found in secret [%s]", ref.Key, ref.Name)
+	}
+	return string(value), nil
+}
+
+// pingRedisExternal will verify that the external Redis of the given ArgoCD accepts connections. The Redis Sentinel
+// is checked when a master group is set, without authentication.
+func (r *ReconcileArgoCD) pingRedisExternal(cr *argoprojv1b1.ArgoCD) error {
+	tlsConfig, err := r.getRedisExternalTLSConfig(cr)
+	if err != nil {
+		return err
+	}
+
+	auth := ""
+	if len(cr.Spec.Redis.External.SentinelMaster) == 0 {
+		if auth, err = r.getRedisExternalPassword(cr); err != nil {
+			return err
+		}
+	}
+
+	return pingRedis(getRedisExternalAddress(cr), auth, tlsConfig, common.ArgoCDDefaultRedisPingTimeout)
+}
+
+// pingRedis will connect to the Redis server at the given address, authenticate with the given password when it is
+// not empty, and send a PING command.
+func pingRedis(address, auth string, tlsConfig *tls.Config, timeout time.Duration) error {
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	if len(auth) > 0 {
+		if err := sendRedisCommand(conn, reader, "+OK", "AUTH", auth); err != nil {
+			return err
+		}
+	}
+	return sendRedisCommand(conn, reader, "+PONG", "PING")
+}
+
+// sendRedisCommand will send the command with the given arguments in the Redis protocol and verify that the reply
+// is the wanted simple string.
+func sendRedisCommand(w io.Writer, reader *bufio.Reader, want string, args ...string) error {
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(w, cmd.String()); err != nil {
+		return err
+	}
+
+	reply, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if reply = strings.TrimSuffix(reply, "\r\n"); reply != want {
+		return fmt.Errorf("unexpected reply to %s: %s", args[0], reply)
+	}
+	return nil
+}
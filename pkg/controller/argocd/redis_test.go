@@ -0,0 +1,482 @@
+package argocd
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+func withRedisTLS(a *argoprojv1beta1.ArgoCD) {
+	a.Spec.Redis.EnableTLS = true
+}
+
+// withRedisExternal configures the external Redis at the given address.
+func withRedisExternal(t *testing.T, address string) argoCDOpt {
+	t.Helper()
+	host, port, err := net.SplitHostPort(address)
+	assert.NilError(t, err)
+	p, err := strconv.Atoi(port)
+	assert.NilError(t, err)
+	return func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Redis.External = &argoprojv1beta1.ArgoCDRedisExternalSpec{Host: host, Port: int32(p)}
+	}
+}
+
+// startTestRedis starts a local stand-in for Redis that answers the AUTH and PING commands, and returns its address.
+// The stand-in requires the given password when it is not empty.
+func startTestRedis(t *testing.T, auth string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestRedis(conn, auth)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveTestRedis(conn net.Conn, auth string) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	authenticated := len(auth) == 0
+	for {
+		args, err := readTestRedisCommand(reader)
+		if err != nil {
+			return
+		}
+
+		reply := "-ERR unknown command"
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			reply = "-WRONGPASS invalid password"
+			if len(args) == 2 && args[1] == auth {
+				authenticated = true
+				reply = "+OK"
+			}
+		case "PING":
+			reply = "-NOAUTH Authentication required."
+			if authenticated {
+				reply = "+PONG"
+			}
+		}
+		if _, err := io.WriteString(conn, reply+"\r\n"); err != nil {
+			return
+		}
+	}
+}
+
+// readTestRedisCommand reads a command sent as an array of bulk strings.
+func readTestRedisCommand(reader *bufio.Reader) ([]string, error) {
+	n, err := readTestRedisLength(reader, "*")
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		size, err := readTestRedisLength(reader, "$")
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readTestRedisLength(reader *bufio.Reader, prefix string) (int, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(line, prefix) {
+		return 0, fmt.Errorf("unexpected line %q", line)
+	}
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, prefix), "\r\n"))
+}
+
+// useRedisConfigTemplates points the Redis configuration path to the templates in the repository.
+func useRedisConfigTemplates(t *testing.T) {
+	t.Helper()
+	assert.NilError(t, os.Setenv("REDIS_CONFIG_PATH", "../../../build/redis"))
+	t.Cleanup(func() {
+		os.Unsetenv("REDIS_CONFIG_PATH")
+	})
+}
+
+func Test_getRedisAuthEnv(t *testing.T) {
+	a := makeTestArgoCD()
+	want := []corev1.EnvVar{{
+		Name: "REDIS_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "argocd-redis"},
+				Key:                  "auth",
+			},
+		},
+	}}
+	assert.DeepEqual(t, getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a), want)
+
+	a.Spec.Redis.DisableAuth = true
+	assert.Assert(t, getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a) == nil)
+}
+
+func Test_getRedisArgs(t *testing.T) {
+	a := makeTestArgoCD()
+	assert.DeepEqual(t, getRedisArgs(a), []string{
+		"--save", "", "--appendonly", "no",
+		"--requirepass", "$(REDIS_PASSWORD)",
+	})
+
+	a = makeTestArgoCD(withRedisTLS, func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Redis.DisableAuth = true
+	})
+	assert.DeepEqual(t, getRedisArgs(a), []string{
+		"--save", "", "--appendonly", "no",
+		"--port", "0",
+		"--tls-port", "6379",
+		"--tls-cert-file", "/app/config/redis/tls/tls.crt",
+		"--tls-key-file", "/app/config/redis/tls/tls.key",
+		"--tls-ca-cert-file", "/app/config/redis/tls/ca.crt",
+		"--tls-auth-clients", "no",
+	})
+}
+
+func Test_getRedisClientArgs(t *testing.T) {
+	a := makeTestArgoCD()
+	assert.Assert(t, getRedisClientArgs(a) == nil)
+
+	a = makeTestArgoCD(withRedisTLS)
+	assert.DeepEqual(t, getRedisClientArgs(a), []string{
+		"--redis-use-tls",
+		"--redis-ca-certificate", "/app/config/redis/tls/ca.crt",
+	})
+
+	a.Spec.Redis.DisableTLSVerification = true
+	assert.DeepEqual(t, getRedisClientArgs(a), []string{
+		"--redis-use-tls",
+		"--redis-insecure-skip-tls-verify",
+	})
+}
+
+func TestReconcileArgoCD_reconcileRedisAuthSecret(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisAuthSecret(a))
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: "argocd-redis", Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, len(secret.Data[common.ArgoCDKeyRedisAuth]), common.ArgoCDDefaultRedisPasswordLength)
+
+	// The password in an existing secret is kept.
+	secret.Data[common.ArgoCDKeyRedisAuth] = []byte("custom-password")
+	assert.NilError(t, r.client.Update(context.TODO(), secret))
+	assert.NilError(t, r.reconcileRedisAuthSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyRedisAuth]), "custom-password")
+}
+
+func TestReconcileArgoCD_reconcileRedisAuthSecret_disabled(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Redis.DisableAuth = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisAuthSecret(a))
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis", Namespace: testNamespace}, &corev1.Secret{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileClusterRedisTLSSecret(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+
+	// The secret is only issued by the operator when TLS is enabled.
+	assert.NilError(t, r.reconcileClusterRedisTLSSecret(a))
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: common.ArgoCDRedisServerTLSSecretName, Namespace: testNamespace}
+	assert.Assert(t, apierrors.IsNotFound(r.client.Get(context.TODO(), key, secret)))
+
+	withRedisTLS(a)
+	assert.NilError(t, r.reconcileClusterRedisTLSSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, secret.Type, corev1.SecretTypeTLS)
+
+	// The certificate is valid for the Redis service.
+	caCerts, err := argoutil.ParsePEMEncodedCerts(secret.Data[common.ArgoCDKeyTLSCACert])
+	assert.NilError(t, err)
+	roots := x509.NewCertPool()
+	for _, caCert := range caCerts {
+		roots.AddCert(caCert)
+	}
+	cert := getTestSecretCertificate(t, r, common.ArgoCDRedisServerTLSSecretName)
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "argocd-redis.argocd.svc", Roots: roots})
+	assert.NilError(t, err)
+}
+
+func TestReconcileArgoCD_reconcileRedisDeployment_tls(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withRedisTLS)
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+	assert.NilError(t, r.reconcileServerDeployment(a))
+
+	for _, name := range []string{"argocd-redis", "argocd-server"} {
+		deployment := &appsv1.Deployment{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, deployment))
+
+		podSpec := deployment.Spec.Template.Spec
+		assert.DeepEqual(t, podSpec.Volumes[len(podSpec.Volumes)-1], getRedisTLSVolumes(a)[0])
+		mounts := podSpec.Containers[0].VolumeMounts
+		assert.DeepEqual(t, mounts[len(mounts)-1], corev1.VolumeMount{
+			Name:      common.ArgoCDRedisServerTLSSecretName,
+			MountPath: "/app/config/redis/tls",
+			ReadOnly:  true,
+		})
+	}
+}
+
+func TestReconcileArgoCD_reconcileRedisHAConfigMap(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	useRedisConfigTemplates(t)
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisHAConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: common.ArgoCDRedisHAConfigMapName, Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, cm))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "\nport 6379\n"))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "\nrequirepass replace-default-auth\n"))
+	assert.Assert(t, strings.Contains(cm.Data["sentinel.conf"], "sentinel auth-pass argocd replace-default-auth\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], `tcp-check send AUTH\ REPLACE_AUTH_SECRET\r\n`))
+	assert.Assert(t, !strings.Contains(cm.Data["haproxy.cfg"], "ssl"))
+
+	// Enabling TLS updates the existing ConfigMap.
+	withRedisTLS(a)
+	assert.NilError(t, r.reconcileRedisHAConfigMap(a))
+
+	assert.NilError(t, r.client.Get(context.TODO(), key, cm))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "\nport 0\ntls-port 6379\n"))
+	assert.Assert(t, strings.Contains(cm.Data["sentinel.conf"], "tls-port 26379\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "bind *:6379 ssl crt /usr/local/etc/haproxy/redis.pem\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy_init.sh"], "cat /app/config/redis/tls/tls.crt /app/config/redis/tls/tls.key > /data/redis.pem\n"))
+	assert.Assert(t, strings.Contains(cm.Data["init.sh"], `REDIS_CLI_OPTS="--tls --cacert /app/config/redis/tls/ca.crt"`))
+}
+
+func TestReconcileArgoCD_reconcileRedisHAConfigMap_topology(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	useRedisConfigTemplates(t)
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.HA.Replicas = int32Ptr(5)
+		a.Spec.HA.SentinelQuorum = int32Ptr(2)
+		a.Spec.HA.VolumeClaimTemplate = &corev1.PersistentVolumeClaimSpec{}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisHAConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: common.ArgoCDRedisHAConfigMapName, Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, cm))
+	assert.Assert(t, strings.Contains(cm.Data["redis.conf"], "\nappendonly yes\n"))
+	assert.Assert(t, strings.Contains(cm.Data["init.sh"], `QUORUM="2"`))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "backend check_if_redis_is_master_4\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "server R4 argocd-redis-ha-announce-4:26379 check inter 3s\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy.cfg"], "{ nbsrv(check_if_redis_is_master_4) ge 2 }\n"))
+	assert.Assert(t, strings.Contains(cm.Data["haproxy_init.sh"], "s/REPLACE_ANNOUNCE4$/"))
+}
+
+func TestReconcileArgoCD_reconcileRedisHAAnnounceServices(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.HA.Replicas = int32Ptr(5)
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisHAAnnounceServices(a))
+	key := types.NamespacedName{Name: "argocd-redis-ha-announce-4", Namespace: testNamespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, &corev1.Service{}))
+
+	// The announce Services of the removed replicas are deleted on a scale down.
+	a.Spec.HA.Replicas = int32Ptr(3)
+	assert.NilError(t, r.reconcileRedisHAAnnounceServices(a))
+	for _, name := range []string{"argocd-redis-ha-announce-3", "argocd-redis-ha-announce-4"} {
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, &corev1.Service{})
+		assert.Assert(t, apierrors.IsNotFound(err))
+	}
+	key.Name = "argocd-redis-ha-announce-2"
+	assert.NilError(t, r.client.Get(context.TODO(), key, &corev1.Service{}))
+}
+
+func Test_getRedisServerArgs_external(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.Redis.External = &argoprojv1beta1.ArgoCDRedisExternalSpec{Host: "redis.example.com"}
+	})
+	assert.DeepEqual(t, getRedisServerArgs(a), []string{"--redis", "redis.example.com:6379"})
+
+	a.Spec.Redis.External.SentinelMaster = "argocd"
+	assert.DeepEqual(t, getRedisServerArgs(a), []string{
+		"--sentinel", "redis.example.com:26379",
+		"--sentinelmaster", "argocd",
+	})
+
+	a.Spec.Redis.External.Port = 6380
+	assert.Equal(t, getRedisServerAddress(a), "redis.example.com:6380")
+}
+
+func Test_getRedisAuthEnv_external(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Redis.External = &argoprojv1beta1.ArgoCDRedisExternalSpec{Host: "redis.example.com"}
+	})
+	assert.Assert(t, getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a) == nil)
+
+	ref := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "managed-redis"},
+		Key:                  "password",
+	}
+	a.Spec.Redis.External.PasswordSecretRef = ref
+	assert.DeepEqual(t, getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a), []corev1.EnvVar{{
+		Name:      "REDIS_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: ref},
+	}})
+}
+
+func Test_getRedisClientArgs_external(t *testing.T) {
+	a := makeTestArgoCD(withRedisTLS, func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Redis.External = &argoprojv1beta1.ArgoCDRedisExternalSpec{Host: "redis.example.com"}
+	})
+	assert.DeepEqual(t, getRedisClientArgs(a), []string{"--redis-use-tls"})
+	assert.Assert(t, getRedisTLSVolumes(a) == nil)
+
+	a.Spec.Redis.External.TLSSecretRef = &corev1.LocalObjectReference{Name: "managed-redis-ca"}
+	assert.DeepEqual(t, getRedisClientArgs(a), []string{
+		"--redis-use-tls",
+		"--redis-ca-certificate", "/app/config/redis/tls/ca.crt",
+	})
+	assert.Equal(t, getRedisTLSVolumes(a)[0].Secret.SecretName, "managed-redis-ca")
+}
+
+func TestReconcileArgoCD_reconcileRedisComponent_external(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileRedisDeployment(a))
+
+	// The Redis deployed by the operator is removed when an external Redis is used.
+	withRedisExternal(t, "redis.example.com:6379")(a)
+	assert.NilError(t, r.reconcileRedisComponent(a))
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis", Namespace: testNamespace}, &appsv1.Deployment{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+	assert.NilError(t, r.reconcileRedisAuthSecret(a))
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis", Namespace: testNamespace}, &corev1.Secret{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileArgoCD_reconcileStatusRedis_external(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	secret := argoutil.NewSecretWithName(makeTestArgoCD().ObjectMeta, "managed-redis")
+	secret.Data = map[string][]byte{"password": []byte("redis-password")}
+	a := makeTestArgoCD(withRedisExternal(t, startTestRedis(t, "redis-password")), func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Redis.External.PasswordSecretRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "managed-redis"},
+			Key:                  "password",
+		}
+	})
+	r := makeTestReconciler(t, a, secret)
+
+	assert.NilError(t, r.reconcileStatusRedis(a))
+	assert.Equal(t, a.Status.Redis, "Running")
+
+	// The status is failed when the password is rejected.
+	secret.Data["password"] = []byte("wrong-password")
+	assert.NilError(t, r.client.Update(context.TODO(), secret))
+	assert.NilError(t, r.reconcileStatusRedis(a))
+	assert.Equal(t, a.Status.Redis, "Failed")
+
+	// The status is failed when Redis is unreachable.
+	withRedisExternal(t, "127.0.0.1:1")(a)
+	assert.NilError(t, r.reconcileStatusRedis(a))
+	assert.Equal(t, a.Status.Redis, "Failed")
+}
+
+func TestReconcileArgoCD_redisHAConfigChecksum(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	useRedisConfigTemplates(t)
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	getChecksums := func() (string, string) {
+		assert.NilError(t, r.reconcileRedisStatefulSet(a))
+		assert.NilError(t, r.reconcileRedisHAProxyDeployment(a))
+
+		ss := &appsv1.StatefulSet{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-server", Namespace: testNamespace}, ss))
+		deploy := &appsv1.Deployment{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis-ha-haproxy", Namespace: testNamespace}, deploy))
+		return ss.Spec.Template.Annotations[common.ArgoCDKeyRedisHAConfigChecksum], deploy.Spec.Template.Annotations[common.ArgoCDKeyRedisHAConfigChecksum]
+	}
+
+	server, haproxy := getChecksums()
+	checksum := getRedisHAConfigChecksum(a)
+	assert.Equal(t, len(checksum), 64)
+	assert.Equal(t, server, checksum)
+	assert.Equal(t, haproxy, checksum)
+
+	// The pods are rolled out when the configuration changes.
+	a.Spec.HA.SentinelQuorum = int32Ptr(3)
+	server, haproxy = getChecksums()
+	assert.Assert(t, server != checksum)
+	assert.Equal(t, server, getRedisHAConfigChecksum(a))
+	assert.Equal(t, haproxy, server)
+}
@@ -0,0 +1,173 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// getRepositorySecretName will return the name of the Secret for the repository or credential template with the
+// given URL. The name is derived from a hash of the URL, it does not match the name Argo CD gives the Secrets it
+// creates for the same URL.
+func getRepositorySecretName(cr *argoprojv1b1.ArgoCD, prefix string, url string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(url))
+	return nameWithSuffix(fmt.Sprintf("%s-%d", prefix, h.Sum32()), cr)
+}
+
+// getRepositorySecretRefs will return the Secret keys referenced by the given repository, keyed by the field of the
+// Argo CD repository Secret that holds their value.
+func getRepositorySecretRefs(repo *argoprojv1b1.ArgoCDRepositorySpec) map[string]*corev1.SecretKeySelector {
+	refs := make(map[string]*corev1.SecretKeySelector)
+	if repo.UsernameSecret != nil {
+		refs["username"] = repo.UsernameSecret
+	}
+	if repo.PasswordSecret != nil {
+		refs["password"] = repo.PasswordSecret
+	}
+	if repo.SSHPrivateKeySecret != nil {
+		refs["sshPrivateKey"] = repo.SSHPrivateKeySecret
+	}
+	if repo.TLSClientCertDataSecret != nil {
+		refs["tlsClientCertData"] = repo.TLSClientCertDataSecret
+	}
+	if repo.TLSClientCertKeySecret != nil {
+		refs["tlsClientCertKey"] = repo.TLSClientCertKeySecret
+	}
+	if repo.GitHubApp != nil {
+		refs["githubAppPrivateKey"] = &repo.GitHubApp.PrivateKeySecret
+	}
+	return refs
+}
+
+// getRepositorySecret will return the Argo CD Secret of the given secret type for the given repository or credential
+// template, holding the values of the Secrets it references.
+func (r *ReconcileArgoCD) getRepositorySecret(cr *argoprojv1b1.ArgoCD, repo *argoprojv1b1.ArgoCDRepositorySpec, secretType string, prefix string) (*corev1.Secret, error) {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, getRepositorySecretName(cr, prefix, repo.URL))
+	secret.Labels[common.ArgoCDSecretTypeLabel] = secretType
+	secret.Data = map[string][]byte{
+		"url": []byte(repo.URL),
+	}
+
+	values := map[string]string{
+		"name":    repo.Name,
+		"project": repo.Project,
+		"type":    repo.Type,
+	}
+	if repo.GitHubApp != nil {
+		values["githubAppID"] = strconv.FormatInt(repo.GitHubApp.ID, 10)
+		values["githubAppInstallationID"] = strconv.FormatInt(repo.GitHubApp.InstallationID, 10)
+		values["githubAppEnterpriseBaseUrl"] = repo.GitHubApp.EnterpriseBaseURL
+	}
+	if repo.EnableLFS {
+		values["enableLfs"] = "true"
+	}
+	if repo.Insecure {
+		values["insecure"] = "true"
+	}
+	if repo.InsecureIgnoreHostKey {
+		values["insecureIgnoreHostKey"] = "true"
+	}
+	for key, value := range values {
+		if len(value) > 0 {
+			secret.Data[key] = []byte(value)
+		}
+	}
+
+	for key, ref := range getRepositorySecretRefs(repo) {
+		value, err := r.getSecretKeyValue(cr, ref)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read secret for repository [%s]: %w", repo.URL, err)
+		}
+		secret.Data[key] = value
+	}
+	return secret, nil
+}
+
+// reconcileRepositorySecrets will ensure that the Argo CD repository and repo-creds Secrets are present for the
+// repositories and credential templates of the given ArgoCD, and removes the Secrets that are no longer needed.
+func (r *ReconcileArgoCD) reconcileRepositorySecrets(cr *argoprojv1b1.ArgoCD) error {
+	secrets := make(map[string]*corev1.Secret)
+	add := func(repos []argoprojv1b1.ArgoCDRepositorySpec, secretType string, prefix string) error {
+		for i := range repos {
+			secret, err := r.getRepositorySecret(cr, &repos[i], secretType, prefix)
+			if err != nil {
+				return err
+			}
+			secrets[secret.Name] = secret
+		}
+		return nil
+	}
+
+	if err := add(cr.Spec.Repositories, common.ArgoCDSecretTypeRepository, "repo"); err != nil {
+		return err
+	}
+	if err := add(cr.Spec.RepositoryCredentialTemplates, common.ArgoCDSecretTypeRepositoryCredentials, "creds"); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := r.applyObject(cr, secrets[name]); err != nil {
+			return err
+		}
+	}
+
+	existing := &corev1.SecretList{}
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			common.ArgoCDKeyManagedBy: cr.Name,
+		}),
+		Namespace: cr.Namespace,
+	}
+	if err := r.client.List(context.TODO(), existing, opts); err != nil {
+		return err
+	}
+
+	for i := range existing.Items {
+		secret := &existing.Items[i]
+		switch secret.Labels[common.ArgoCDSecretTypeLabel] {
+		case common.ArgoCDSecretTypeRepository, common.ArgoCDSecretTypeRepositoryCredentials:
+		default:
+			continue
+		}
+		if _, ok := secrets[secret.Name]; ok || !metav1.IsControlledBy(secret, cr) {
+			continue
+		}
+		log.Info(fmt.Sprintf("deleting repository secret [%s], it is no longer needed", secret.Name))
+		if err := r.client.Delete(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func makeTestRepositoryCredentials() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-creds", Namespace: testNamespace},
+		Data: map[string][]byte{
+			"username":      []byte("deployer"),
+			"password":      []byte("s3cr3t"),
+			"sshPrivateKey": []byte("private-key"),
+		},
+	}
+}
+
+func makeTestRepositoryCredentialsRef(key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "git-creds"},
+		Key:                  key,
+	}
+}
+
+func TestReconcileArgoCD_reconcileRepositorySecrets(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Repositories = []argoprojv1beta1.ArgoCDRepositorySpec{
+			{
+				URL:            "https://github.com/argoproj/argocd-example-apps.git",
+				Project:        "team-a",
+				UsernameSecret: makeTestRepositoryCredentialsRef("username"),
+				PasswordSecret: makeTestRepositoryCredentialsRef("password"),
+			},
+			{
+				URL:  "https://argoproj.github.io/argo-helm",
+				Type: "helm",
+				Name: "argo",
+			},
+		}
+		a.Spec.RepositoryCredentialTemplates = []argoprojv1beta1.ArgoCDRepositorySpec{{
+			URL:                 "git@github.com:argoproj",
+			SSHPrivateKeySecret: makeTestRepositoryCredentialsRef("sshPrivateKey"),
+		}}
+	})
+	creds := makeTestRepositoryCredentials()
+	r := makeTestReconciler(t, a, creds)
+
+	assert.NilError(t, r.reconcileRepositorySecrets(a))
+
+	repoName := getRepositorySecretName(a, "repo", "https://github.com/argoproj/argocd-example-apps.git")
+	repo := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: repoName, Namespace: testNamespace}, repo))
+	assert.Equal(t, repo.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeRepository)
+	assert.Assert(t, metav1.IsControlledBy(repo, a))
+	assert.Equal(t, string(repo.Data["url"]), "https://github.com/argoproj/argocd-example-apps.git")
+	assert.Equal(t, string(repo.Data["project"]), "team-a")
+	assert.Equal(t, string(repo.Data["username"]), "deployer")
+	assert.Equal(t, string(repo.Data["password"]), "s3cr3t")
+	_, ok := repo.Data["type"]
+	assert.Assert(t, !ok)
+
+	helm := &corev1.Secret{}
+	helmName := getRepositorySecretName(a, "repo", "https://argoproj.github.io/argo-helm")
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: helmName, Namespace: testNamespace}, helm))
+	assert.Equal(t, string(helm.Data["type"]), "helm")
+	assert.Equal(t, string(helm.Data["name"]), "argo")
+
+	template := &corev1.Secret{}
+	templateName := getRepositorySecretName(a, "creds", "git@github.com:argoproj")
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: templateName, Namespace: testNamespace}, template))
+	assert.Equal(t, template.Labels[common.ArgoCDSecretTypeLabel], common.ArgoCDSecretTypeRepositoryCredentials)
+	assert.Equal(t, string(template.Data["sshPrivateKey"]), "private-key")
+
+	// The repository Secrets follow the Secrets they reference.
+	creds.Data["password"] = []byte("rotated")
+	assert.NilError(t, r.client.Update(context.TODO(), creds))
+	assert.NilError(t, r.reconcileRepositorySecrets(a))
+	repo = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: repoName, Namespace: testNamespace}, repo))
+	assert.Equal(t, string(repo.Data["password"]), "rotated")
+
+	// The Secrets are removed with their repositories.
+	a.Spec.Repositories = a.Spec.Repositories[:1]
+	a.Spec.RepositoryCredentialTemplates = nil
+	assert.NilError(t, r.reconcileRepositorySecrets(a))
+	for _, name := range []string{helmName, templateName} {
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, &corev1.Secret{})
+		assert.Assert(t, apierrors.IsNotFound(err))
+	}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: repoName, Namespace: testNamespace}, repo))
+}
+
+func TestReconcileArgoCD_reconcileRepositorySecrets_missingSecret(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Repositories = []argoprojv1beta1.ArgoCDRepositorySpec{{
+			URL:            "https://github.com/argoproj/argocd-example-apps.git",
+			PasswordSecret: makeTestRepositoryCredentialsRef("password"),
+		}}
+	})
+	r := makeTestReconciler(t, a)
+
+	err := r.reconcileRepositorySecrets(a)
+	assert.ErrorContains(t, err, "unable to read secret for repository [https://github.com/argoproj/argocd-example-apps.git]")
+}
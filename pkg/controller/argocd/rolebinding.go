@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"reflect"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
@@ -17,7 +17,7 @@ import (
 )
 
 // newClusterRoleBinding returns a new ClusterRoleBinding instance.
-func newClusterRoleBinding(name string, cr *argoprojv1a1.ArgoCD) *v1.ClusterRoleBinding {
+func newClusterRoleBinding(name string, cr *argoprojv1b1.ArgoCD) *v1.ClusterRoleBinding {
 	return &v1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        cr.Name,
@@ -28,7 +28,7 @@ func newClusterRoleBinding(name string, cr *argoprojv1a1.ArgoCD) *v1.ClusterRole
 }
 
 // newClusterRoleBindingWithname creates a new ClusterRoleBinding with the given name for the given ArgCD.
-func newClusterRoleBindingWithname(name string, cr *argoprojv1a1.ArgoCD) *v1.ClusterRoleBinding {
+func newClusterRoleBindingWithname(name string, cr *argoprojv1b1.ArgoCD) *v1.ClusterRoleBinding {
 	roleBinding := newClusterRoleBinding(name, cr)
 	roleBinding.Name = GenerateUniqueResourceName(name, cr)
 
@@ -40,7 +40,7 @@ func newClusterRoleBindingWithname(name string, cr *argoprojv1a1.ArgoCD) *v1.Clu
 }
 
 // newRoleBinding returns a new RoleBinding instance.
-func newRoleBinding(cr *argoprojv1a1.ArgoCD) *v1.RoleBinding {
+func newRoleBinding(cr *argoprojv1b1.ArgoCD) *v1.RoleBinding {
 	return &v1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        cr.Name,
@@ -52,7 +52,7 @@ func newRoleBinding(cr *argoprojv1a1.ArgoCD) *v1.RoleBinding {
 }
 
 // newRoleBindingWithname creates a new RoleBinding with the given name for the given ArgCD.
-func newRoleBindingWithname(name string, cr *argoprojv1a1.ArgoCD) *v1.RoleBinding {
+func newRoleBindingWithname(name string, cr *argoprojv1b1.ArgoCD) *v1.RoleBinding {
 	roleBinding := newRoleBinding(cr)
 	roleBinding.ObjectMeta.Name = fmt.Sprintf("%s-%s", cr.Name, name)
 
@@ -64,7 +64,7 @@ func newRoleBindingWithname(name string, cr *argoprojv1a1.ArgoCD) *v1.RoleBindin
 }
 
 // reconcileRoleBindings will ensure that all ArgoCD RoleBindings are configured.
-func (r *ReconcileArgoCD) reconcileRoleBindings(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRoleBindings(cr *argoprojv1b1.ArgoCD) error {
 	if err := r.reconcileRoleBinding(applicationController, policyRuleForApplicationController(), cr); err != nil {
 		return fmt.Errorf("error reconciling roleBinding for %q: %w", applicationController, err)
 	}
@@ -82,7 +82,7 @@ func (r *ReconcileArgoCD) reconcileRoleBindings(cr *argoprojv1a1.ArgoCD) error {
 	return nil
 }
 
-func (r *ReconcileArgoCD) reconcileRoleBinding(name string, rules []v1.PolicyRule, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRoleBinding(name string, rules []v1.PolicyRule, cr *argoprojv1b1.ArgoCD) error {
 	var role *v1.Role
 	var sa *corev1.ServiceAccount
 	var error error
@@ -147,7 +147,7 @@ func (r *ReconcileArgoCD) reconcileRoleBinding(name string, rules []v1.PolicyRul
 	return r.client.Create(context.TODO(), roleBinding)
 }
 
-func (r *ReconcileArgoCD) reconcileClusterRoleBinding(name string, role *v1.ClusterRole, sa *corev1.ServiceAccount, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileClusterRoleBinding(name string, role *v1.ClusterRole, sa *corev1.ServiceAccount, cr *argoprojv1b1.ArgoCD) error {
 
 	// get expected name
 	roleBinding := newClusterRoleBindingWithname(name, cr)
@@ -18,13 +18,12 @@ import (
 	"context"
 	"fmt"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	routev1 "github.com/openshift/api/route/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 var routeAPIFound = false
@@ -45,7 +44,7 @@ func verifyRouteAPI() error {
 }
 
 // newRoute returns a new Route instance for the given ArgoCD.
-func newRoute(cr *argoprojv1a1.ArgoCD) *routev1.Route {
+func newRoute(cr *argoprojv1b1.ArgoCD) *routev1.Route {
 	return &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -56,7 +55,7 @@ func newRoute(cr *argoprojv1a1.ArgoCD) *routev1.Route {
 }
 
 // newRouteWithName returns a new Route with the given name and ArgoCD.
-func newRouteWithName(name string, cr *argoprojv1a1.ArgoCD) *routev1.Route {
+func newRouteWithName(name string, cr *argoprojv1b1.ArgoCD) *routev1.Route {
 	route := newRoute(cr)
 	route.ObjectMeta.Name = name
 
@@ -68,38 +67,18 @@ func newRouteWithName(name string, cr *argoprojv1a1.ArgoCD) *routev1.Route {
 }
 
 // newRouteWithSuffix returns a new Route with the given name suffix for the ArgoCD.
-func newRouteWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *routev1.Route {
+func newRouteWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) *routev1.Route {
 	return newRouteWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), cr)
 }
 
-// reconcileRoutes will ensure that all ArgoCD Routes are present.
-func (r *ReconcileArgoCD) reconcileRoutes(cr *argoprojv1a1.ArgoCD) error {
-	if err := r.reconcileGrafanaRoute(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcilePrometheusRoute(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileServerRoute(cr); err != nil {
-		return err
-	}
-	return nil
-}
-
 // reconcileGrafanaRoute will ensure that the ArgoCD Grafana Route is present.
-func (r *ReconcileArgoCD) reconcileGrafanaRoute(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGrafanaRoute(cr *argoprojv1b1.ArgoCD) error {
 	route := newRouteWithSuffix("grafana", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
-		if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
+	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.client.Delete(context.TODO(), route)
 		}
-		return nil // Route found, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
 		return nil // Grafana itself or Route not enabled, do nothing.
 	}
 
@@ -135,24 +114,17 @@ func (r *ReconcileArgoCD) reconcileGrafanaRoute(cr *argoprojv1a1.ArgoCD) error {
 		route.Spec.WildcardPolicy = *cr.Spec.Grafana.Route.WildcardPolicy
 	}
 
-	if err := controllerutil.SetControllerReference(cr, route, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), route)
+	return r.applyObject(cr, route)
 }
 
 // reconcilePrometheusRoute will ensure that the ArgoCD Prometheus Route is present.
-func (r *ReconcileArgoCD) reconcilePrometheusRoute(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcilePrometheusRoute(cr *argoprojv1b1.ArgoCD) error {
 	route := newRouteWithSuffix("prometheus", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
-		if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
+	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.client.Delete(context.TODO(), route)
 		}
-		return nil // Route found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
 		return nil // Prometheus itself or Route not enabled, do nothing.
 	}
 
@@ -183,25 +155,17 @@ func (r *ReconcileArgoCD) reconcilePrometheusRoute(cr *argoprojv1a1.ArgoCD) erro
 		route.Spec.WildcardPolicy = *cr.Spec.Prometheus.Route.WildcardPolicy
 	}
 
-	if err := controllerutil.SetControllerReference(cr, route, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), route)
+	return r.applyObject(cr, route)
 }
 
 // reconcileServerRoute will ensure that the ArgoCD Server Route is present.
-func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
-
+func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1b1.ArgoCD) error {
 	route := newRouteWithSuffix("server", cr)
-	found := argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route)
-	if found {
-		if !cr.Spec.Server.Route.Enabled {
+	if !cr.Spec.Server.Route.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, route.Name, route) {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.client.Delete(context.TODO(), route)
 		}
-	}
-
-	if !cr.Spec.Server.Route.Enabled {
 		return nil // Route not enabled, move along...
 	}
 
@@ -248,11 +212,5 @@ func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
 		route.Spec.WildcardPolicy = *cr.Spec.Server.Route.WildcardPolicy
 	}
 
-	if err := controllerutil.SetControllerReference(cr, route, r.scheme); err != nil {
-		return err
-	}
-	if !found {
-		return r.client.Create(context.TODO(), route)
-	}
-	return r.client.Update(context.TODO(), route)
+	return r.applyObject(cr, route)
 }
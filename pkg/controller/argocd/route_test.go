@@ -11,12 +11,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 
-	argov1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argov1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/google/go-cmp/cmp"
 	routev1 "github.com/openshift/api/route/v1"
 )
@@ -25,7 +26,7 @@ func TestReconcileRouteSetsInsecure(t *testing.T) {
 	routeAPIFound = true
 	ctx := context.Background()
 	logf.SetLogger(logf.ZapLogger(true))
-	argoCD := makeArgoCD(func(a *argov1alpha1.ArgoCD) {
+	argoCD := makeArgoCD(func(a *argov1beta1.ArgoCD) {
 		a.Spec.Server.Route.Enabled = true
 	})
 	objs := []runtime.Object{
@@ -95,7 +96,7 @@ func TestReconcileRouteUnsetsInsecure(t *testing.T) {
 	routeAPIFound = true
 	ctx := context.Background()
 	logf.SetLogger(logf.ZapLogger(true))
-	argoCD := makeArgoCD(func(a *argov1alpha1.ArgoCD) {
+	argoCD := makeArgoCD(func(a *argov1beta1.ArgoCD) {
 		a.Spec.Server.Route.Enabled = true
 		a.Spec.Server.Insecure = true
 	})
@@ -162,25 +163,26 @@ func TestReconcileRouteUnsetsInsecure(t *testing.T) {
 	}
 }
 
-func makeReconciler(t *testing.T, acd *argov1alpha1.ArgoCD, objs ...runtime.Object) *ReconcileArgoCD {
+func makeReconciler(t *testing.T, acd *argov1beta1.ArgoCD, objs ...runtime.Object) *ReconcileArgoCD {
 	t.Helper()
 	s := scheme.Scheme
-	s.AddKnownTypes(argov1alpha1.SchemeGroupVersion, acd)
+	s.AddKnownTypes(argov1beta1.SchemeGroupVersion, acd)
 	routev1.Install(s)
 	cl := fake.NewFakeClient(objs...)
 	return &ReconcileArgoCD{
-		client: cl,
-		scheme: s,
+		client:   &applyClient{Client: cl},
+		scheme:   s,
+		recorder: record.NewFakeRecorder(100),
 	}
 }
 
-func makeArgoCD(opts ...func(*argov1alpha1.ArgoCD)) *argov1alpha1.ArgoCD {
-	argoCD := &argov1alpha1.ArgoCD{
+func makeArgoCD(opts ...func(*argov1beta1.ArgoCD)) *argov1beta1.ArgoCD {
+	argoCD := &argov1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      testArgoCDName,
 			Namespace: testNamespace,
 		},
-		Spec: argov1alpha1.ArgoCDSpec{},
+		Spec: argov1beta1.ArgoCDSpec{},
 	}
 	for _, o := range opts {
 		o(argoCD)
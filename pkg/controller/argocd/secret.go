@@ -15,16 +15,18 @@
 package argocd
 
 import (
+	"bytes"
 	"context"
-	"crypto/rsa"
+	"crypto"
 	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"fmt"
 	"os"
 	"time"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	argopass "github.com/argoproj/argo-cd/util/password"
@@ -32,6 +34,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -81,15 +84,25 @@ func nowNano() string {
 }
 
 // newCASecret creates a new CA secret with the given suffix for the given ArgoCD.
-func newCASecret(cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
+func newCASecret(cr *argoprojv1b1.ArgoCD) (*corev1.Secret, error) {
 	secret := argoutil.NewTLSSecret(cr.ObjectMeta, "ca")
 
-	key, err := argoutil.NewPrivateKey()
+	key, err := newPrivateKey(cr)
 	if err != nil {
 		return nil, err
 	}
 
-	cert, err := argoutil.NewSelfSignedCACertificate(key)
+	subject := pkix.Name{
+		CommonName:   secret.Name,
+		Organization: getCertificateOrganizations(cr, nil),
+	}
+
+	cert, err := argoutil.NewSelfSignedCACertificate(subject, key, getCertificateDuration(cr.Spec.TLS.CA.Duration))
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := argoutil.EncodePrivateKeyPEM(key)
 	if err != nil {
 		return nil, err
 	}
@@ -98,28 +111,14 @@ func newCASecret(cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
 	secret.Data = map[string][]byte{
 		corev1.TLSCertKey:              argoutil.EncodeCertificatePEM(cert),
 		corev1.ServiceAccountRootCAKey: argoutil.EncodeCertificatePEM(cert),
-		corev1.TLSPrivateKeyKey:        argoutil.EncodePrivateKeyPEM(key),
+		corev1.TLSPrivateKeyKey:        keyPEM,
 	}
 
 	return secret, nil
 }
 
-// newCertificateSecret creates a new secret using the given name suffix for the given TLS certificate.
-func newCertificateSecret(suffix string, caCert *x509.Certificate, caKey *rsa.PrivateKey, cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
-	secret := argoutil.NewTLSSecret(cr.ObjectMeta, suffix)
-
-	key, err := argoutil.NewPrivateKey()
-	if err != nil {
-		return nil, err
-	}
-
-	cfg := &tlsutil.CertConfig{
-		CertName:     secret.Name,
-		CertType:     tlsutil.ClientAndServingCert,
-		CommonName:   secret.Name,
-		Organization: []string{cr.ObjectMeta.Namespace},
-	}
-
+// getArgoServerDNSNames will return the DNS names for the Argo CD server certificate.
+func getArgoServerDNSNames(cr *argoprojv1b1.ArgoCD) []string {
 	dnsNames := []string{
 		cr.ObjectMeta.Name,
 		nameWithSuffix("grpc", cr),
@@ -132,22 +131,58 @@ func newCertificateSecret(suffix string, caCert *x509.Certificate, caKey *rsa.Pr
 	if cr.Spec.Prometheus.Enabled {
 		dnsNames = append(dnsNames, getPrometheusHost(cr))
 	}
+	return dnsNames
+}
+
+// newCertificateSecret creates a new TLS secret with the given name, holding a certificate for the given DNS names
+// signed by the CA in the given CA secret. The CA bundle is included, so that clients can verify the certificate.
+func newCertificateSecret(name string, dnsNames []string, caSecret *corev1.Secret, cr *argoprojv1b1.ArgoCD) (*corev1.Secret, error) {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
+	secret.Type = corev1.SecretTypeTLS
+
+	caCert, err := argoutil.ParsePEMEncodedCert(caSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, err
+	}
+
+	caKey, err := argoutil.ParsePEMEncodedPrivateKey(caSecret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, err
+	}
 
-	cert, err := argoutil.NewSignedCertificate(cfg, dnsNames, key, caCert, caKey)
+	key, err := newPrivateKey(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tlsutil.CertConfig{
+		CertName:     secret.Name,
+		CertType:     tlsutil.ClientAndServingCert,
+		CommonName:   secret.Name,
+		Organization: getCertificateOrganizations(cr, []string{cr.ObjectMeta.Namespace}),
+	}
+
+	cert, err := argoutil.NewSignedCertificate(cfg, dnsNames, key, caCert, caKey, getCertificateDuration(cr.Spec.TLS.Duration))
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := argoutil.EncodePrivateKeyPEM(key)
 	if err != nil {
 		return nil, err
 	}
 
 	secret.Data = map[string][]byte{
-		corev1.TLSCertKey:       argoutil.EncodeCertificatePEM(cert),
-		corev1.TLSPrivateKeyKey: argoutil.EncodePrivateKeyPEM(key),
+		corev1.ServiceAccountRootCAKey: getCABundle(caSecret),
+		corev1.TLSCertKey:              argoutil.EncodeCertificatePEM(cert),
+		corev1.TLSPrivateKeyKey:        keyPEM,
 	}
 
 	return secret, nil
 }
 
 // reconcileArgoSecret will ensure that the Argo CD Secret is present.
-func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1b1.ArgoCD) error {
 	clusterSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "cluster")
 	secret := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDSecretName)
 
@@ -191,14 +226,96 @@ func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1a1.ArgoCD) error {
 	return r.client.Create(context.TODO(), secret)
 }
 
+// isAdminPasswordFromSecret will return true when the admin password for the given ArgoCD is read from a Secret.
+func isAdminPasswordFromSecret(cr *argoprojv1b1.ArgoCD) bool {
+	return cr.Spec.AdminPassword != nil && cr.Spec.AdminPassword.SecretRef != nil
+}
+
+// getAdminPasswordRotationInterval will return the interval at which the admin password for the given ArgoCD is
+// rotated, or zero when it is not rotated.
+func getAdminPasswordRotationInterval(cr *argoprojv1b1.ArgoCD) time.Duration {
+	if cr.Spec.AdminPassword == nil || cr.Spec.AdminPassword.RotationInterval == nil || isAdminPasswordFromSecret(cr) {
+		return 0
+	}
+	return cr.Spec.AdminPassword.RotationInterval.Duration
+}
+
+// getAdminPasswordRotationReason will return the reason to generate a new admin password for the given ArgoCD, or an
+// empty string when the password in the given cluster Secret is kept. The password was last rotated when the cluster
+// Secret was created if no rotation was recorded in the status.
+func getAdminPasswordRotationReason(cr *argoprojv1b1.ArgoCD, clusterSecret *corev1.Secret) string {
+	if _, ok := cr.Annotations[common.AnnotationResetAdminPassword]; ok {
+		return "a reset was requested"
+	}
+
+	return getRotationReason(getAdminPasswordRotationInterval(cr), cr.Status.AdminPasswordRotationTime, clusterSecret)
+}
+
+// getServerSecretKeyRotationReason will return the reason to generate a new server secret key for the given ArgoCD,
+// or an empty string when the key in the given Argo CD Secret is kept. The key was last rotated when the Argo CD Secret
+// was created if no rotation was recorded in the status.
+func getServerSecretKeyRotationReason(cr *argoprojv1b1.ArgoCD, argoSecret *corev1.Secret) string {
+	if _, ok := cr.Annotations[common.AnnotationRotateServerSecretKey]; ok {
+		return "a rotation was requested"
+	}
+	return getRotationReason(getServerSecretKeyRotationInterval(cr), cr.Status.ServerSecretKeyRotationTime, argoSecret)
+}
+
+// getServerSecretKeyRotationInterval will return the interval at which the server secret key for the given ArgoCD is
+// rotated, or zero when it is not rotated.
+func getServerSecretKeyRotationInterval(cr *argoprojv1b1.ArgoCD) time.Duration {
+	if cr.Spec.Server.SecretKey.RotationInterval == nil {
+		return 0
+	}
+	return cr.Spec.Server.SecretKey.RotationInterval.Duration
+}
+
+// getRotationReason will return the reason to rotate a value held by the given Secret once the given interval has
+// passed, or an empty string when the value is kept. The value was last rotated at the given time, or when the Secret
+// was created if the time is not known.
+func getRotationReason(interval time.Duration, rotated *metav1.Time, secret *corev1.Secret) string {
+	if interval == 0 {
+		return ""
+	}
+
+	last := secret.CreationTimestamp.Time
+	if rotated != nil {
+		last = rotated.Time
+	}
+	if time.Since(last) < interval {
+		return ""
+	}
+	return fmt.Sprintf("it was last rotated at %s", last.UTC().Format(time.RFC3339))
+}
+
+// getAdminPassword will return the admin password for the given ArgoCD, read from the admin password Secret when
+// given, otherwise a newly generated password.
+func (r *ReconcileArgoCD) getAdminPassword(cr *argoprojv1b1.ArgoCD) ([]byte, error) {
+	if !isAdminPasswordFromSecret(cr) {
+		return generateArgoAdminPassword()
+	}
+
+	ref := cr.Spec.AdminPassword.SecretRef
+	secret, err := argoutil.FetchSecret(r.client, cr.ObjectMeta, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read admin password secret [%s]: %w", ref.Name, err)
+	}
+
+	password, ok := secret.Data[ref.Key]
+	if !ok || len(password) == 0 {
+		return nil, fmt.Errorf("admin password secret [%s] has no key [%s]", ref.Name, ref.Key)
+	}
+	return password, nil
+}
+
 // reconcileClusterMainSecret will ensure that the main Secret is present for the Argo CD cluster.
-func (r *ReconcileArgoCD) reconcileClusterMainSecret(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileClusterMainSecret(cr *argoprojv1b1.ArgoCD) error {
 	secret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "cluster")
 	if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
-		return nil // Secret found, do nothing
+		return r.reconcileExistingClusterMainSecret(cr, secret)
 	}
 
-	adminPassword, err := generateArgoAdminPassword()
+	adminPassword, err := r.getAdminPassword(cr)
 	if err != nil {
 		return err
 	}
@@ -213,46 +330,185 @@ func (r *ReconcileArgoCD) reconcileClusterMainSecret(cr *argoprojv1a1.ArgoCD) er
 	return r.client.Create(context.TODO(), secret)
 }
 
+// reconcileExistingClusterMainSecret will ensure that the admin password in the given cluster Secret matches the
+// admin password Secret, or is replaced when a reset was requested or the rotation interval has passed. A reset has no
+// effect when the password is read from a Secret, the reset annotation is removed from the ArgoCD once handled. The
+// Argo CD and Grafana Secrets are updated from the cluster Secret by reconcileArgoSecret and reconcileGrafanaSecret.
+func (r *ReconcileArgoCD) reconcileExistingClusterMainSecret(cr *argoprojv1b1.ArgoCD, secret *corev1.Secret) error {
+	var password []byte
+	var err error
+	reason := ""
+	if isAdminPasswordFromSecret(cr) {
+		if password, err = r.getAdminPassword(cr); err != nil {
+			return err
+		}
+		if !bytes.Equal(password, secret.Data[common.ArgoCDKeyAdminPassword]) {
+			reason = "the admin password secret has changed"
+		}
+	} else if reason = getAdminPasswordRotationReason(cr, secret); len(reason) > 0 {
+		if password, err = generateArgoAdminPassword(); err != nil {
+			return err
+		}
+	}
+
+	if len(reason) > 0 {
+		log.Info(fmt.Sprintf("updating admin password in cluster secret [%s], %s", secret.Name, reason))
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[common.ArgoCDKeyAdminPassword] = password
+		if err := r.client.Update(context.TODO(), secret); err != nil {
+			return err
+		}
+
+		now := metav1.Now()
+		cr.Status.AdminPasswordRotationTime = &now
+		if err := r.client.Status().Update(context.TODO(), cr); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := cr.Annotations[common.AnnotationResetAdminPassword]; ok {
+		delete(cr.Annotations, common.AnnotationResetAdminPassword)
+		return r.client.Update(context.TODO(), cr)
+	}
+	return nil
+}
+
 // reconcileClusterTLSSecret ensures the TLS Secret is created for the ArgoCD cluster.
-func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1a1.ArgoCD) error {
-	secret := argoutil.NewTLSSecret(cr.ObjectMeta, "tls")
+func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1b1.ArgoCD) error {
+	if useCertManager(cr) {
+		return nil // Secret issued by cert-manager, do nothing
+	}
+	return r.reconcileCertificateSecret(cr, nameWithSuffix("tls", cr), getArgoServerDNSNames(cr))
+}
+
+// reconcileClusterRepoServerTLSSecret ensures the repo server TLS Secret is issued by the operator CA when requested
+// with AutoTLS.
+func (r *ReconcileArgoCD) reconcileClusterRepoServerTLSSecret(cr *argoprojv1b1.ArgoCD) error {
+	if cr.Spec.Repo.AutoTLS != common.ArgoCDRepoServerAutoTLSOperator {
+		return nil
+	}
+	return r.reconcileCertificateSecret(cr, common.ArgoCDRepoServerTLSSecretName, getRepoServerDNSNames(cr))
+}
+
+// reconcileClusterRedisTLSSecret ensures the Redis TLS Secret is issued by the operator CA when TLS is enabled for
+// the Redis deployed by the operator and the certificate is not issued by cert-manager.
+func (r *ReconcileArgoCD) reconcileClusterRedisTLSSecret(cr *argoprojv1b1.ArgoCD) error {
+	if !isRedisTLSEnabled(cr) || isRedisExternal(cr) || useCertManager(cr) {
+		return nil
+	}
+	return r.reconcileCertificateSecret(cr, common.ArgoCDRedisServerTLSSecretName, getRedisDNSNames(cr))
+}
+
+// reconcileRedisAuthSecret will ensure that the Secret with the Redis password is present when Redis authentication
+// is enabled for the Redis deployed by the operator. The password in an existing Secret is kept, so that it can be set
+// by the user.
+func (r *ReconcileArgoCD) reconcileRedisAuthSecret(cr *argoprojv1b1.ArgoCD) error {
+	if !isRedisAuthEnabled(cr) || isRedisExternal(cr) {
+		return nil
+	}
+
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, getRedisAuthSecretName(cr))
 	if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
 		return nil // Secret found, do nothing
 	}
 
+	redisPassword, err := generateRedisPassword()
+	if err != nil {
+		return err
+	}
+
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyRedisAuth: redisPassword,
+	}
+
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), secret)
+}
+
+// reconcileCertificateSecret ensures the TLS Secret with the given name holds a certificate for the given DNS names,
+// signed by the operator CA.
+func (r *ReconcileArgoCD) reconcileCertificateSecret(cr *argoprojv1b1.ArgoCD, name string, dnsNames []string) error {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+		return r.reconcileExistingCertificateSecret(cr, secret, dnsNames)
+	}
+
 	caSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
 	caSecret, err := argoutil.FetchSecret(r.client, cr.ObjectMeta, caSecret.Name)
 	if err != nil {
 		return err
 	}
 
-	caCert, err := argoutil.ParsePEMEncodedCert(caSecret.Data[corev1.TLSCertKey])
+	secret, err = newCertificateSecret(name, dnsNames, caSecret, cr)
 	if err != nil {
 		return err
 	}
 
-	caKey, err := argoutil.ParsePEMEncodedPrivateKey(caSecret.Data[corev1.TLSPrivateKeyKey])
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return err
+	}
+
+	return r.client.Create(context.TODO(), secret)
+}
+
+// reconcileExistingCertificateSecret will renew the certificate in the given TLS Secret when it expires within the
+// renewal window, was signed by a previous CA or has a key that does not match the private key options. Certificates
+// that were not signed by the operator CA are left as is.
+func (r *ReconcileArgoCD) reconcileExistingCertificateSecret(cr *argoprojv1b1.ArgoCD, secret *corev1.Secret, dnsNames []string) error {
+	caSecret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, caSecret.Name, caSecret) {
+		log.Info(fmt.Sprintf("ca secret [%s] not found, unable to renew tls secret [%s]", caSecret.Name, secret.Name))
+		return nil
+	}
+
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
 	if err != nil {
 		return err
 	}
 
-	secret, err = newCertificateSecret("tls", caCert, caKey, cr)
+	caCert, err := argoutil.ParsePEMEncodedCert(caSecret.Data[corev1.TLSCertKey])
 	if err != nil {
 		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+	caBundle, err := argoutil.ParsePEMEncodedCerts(getCABundle(caSecret))
+	if err != nil {
 		return err
 	}
 
-	return r.client.Create(context.TODO(), secret)
+	if !isSignedByAny(cert, caBundle) {
+		return nil // Certificate not issued by the operator, do nothing
+	}
+
+	reason := getCertificateRenewalReason(cert, cr)
+	if len(reason) == 0 && cert.CheckSignatureFrom(caCert) != nil {
+		reason = "it was signed by a previous CA"
+	}
+	if len(reason) == 0 {
+		return nil // Certificate valid and signed by the current CA, do nothing
+	}
+
+	log.Info(fmt.Sprintf("renewing certificate in tls secret [%s], %s", secret.Name, reason))
+	renewed, err := newCertificateSecret(secret.Name, dnsNames, caSecret, cr)
+	if err != nil {
+		return err
+	}
+
+	// The components using the certificate are rolled out by reconcileArgoSecret for the server certificate, and by
+	// reconcileRepoServerTLSSecret for the repo server certificate.
+	secret.Data = renewed.Data
+	return r.client.Update(context.TODO(), secret)
 }
 
 // reconcileClusterCASecret ensures the CA Secret is created for the ArgoCD cluster.
-func (r *ReconcileArgoCD) reconcileClusterCASecret(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileClusterCASecret(cr *argoprojv1b1.ArgoCD) error {
 	secret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "ca")
 	if argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
-		return nil // Secret found, do nothing
+		return r.reconcileExistingClusterCASecret(cr, secret)
 	}
 
 	secret, err := newCASecret(cr)
@@ -266,8 +522,158 @@ func (r *ReconcileArgoCD) reconcileClusterCASecret(cr *argoprojv1a1.ArgoCD) erro
 	return r.client.Create(context.TODO(), secret)
 }
 
+// reconcileExistingClusterCASecret will renew the CA certificate in the given Secret when it expires within the
+// renewal window or has a key that does not match the private key options. The previous CA certificates are kept in
+// the CA bundle until they expire, so that the certificates they signed are trusted until those are renewed.
+func (r *ReconcileArgoCD) reconcileExistingClusterCASecret(cr *argoprojv1b1.ArgoCD, secret *corev1.Secret) error {
+	caCert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return err
+	}
+
+	reason := getCertificateRenewalReason(caCert, cr)
+	if len(reason) == 0 {
+		return nil // CA certificate valid, do nothing
+	}
+
+	previous, err := argoutil.ParsePEMEncodedCerts(getCABundle(secret))
+	if err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("renewing certificate in ca secret [%s], %s", secret.Name, reason))
+	renewed, err := newCASecret(cr)
+	if err != nil {
+		return err
+	}
+
+	bundle := renewed.Data[corev1.ServiceAccountRootCAKey]
+	for _, cert := range previous {
+		if time.Now().Before(cert.NotAfter) {
+			bundle = append(bundle, argoutil.EncodeCertificatePEM(cert)...)
+		}
+	}
+	renewed.Data[corev1.ServiceAccountRootCAKey] = bundle
+
+	secret.Data = renewed.Data
+	return r.client.Update(context.TODO(), secret)
+}
+
+// getCABundle will return the PEM encoded CA certificates trusted for the given CA Secret. The bundle holds the
+// current CA certificate and the previous ones that have not expired yet.
+func getCABundle(caSecret *corev1.Secret) []byte {
+	if bundle, ok := caSecret.Data[corev1.ServiceAccountRootCAKey]; ok {
+		return bundle
+	}
+	return caSecret.Data[corev1.TLSCertKey]
+}
+
+// getCertificateRenewalReason will return why the given operator-issued certificate must be renewed for the given
+// ArgoCD, or an empty string when the certificate is valid. When the private key options are set, certificates with a
+// key that does not match the options are renewed, so that existing keys are migrated when the options change.
+func getCertificateRenewalReason(cert *x509.Certificate, cr *argoprojv1b1.ArgoCD) string {
+	if argoutil.CertificateNeedsRenewal(cert, getCertificateRenewBefore(cr)) {
+		return fmt.Sprintf("it expires at %s", cert.NotAfter)
+	}
+
+	if cr.Spec.TLS.PrivateKey == nil {
+		return ""
+	}
+
+	algorithm, size := getPrivateKeyOptions(cr)
+	certAlgorithm, certSize := argoutil.GetPublicKeyAlgorithm(cert.PublicKey)
+	if certAlgorithm != algorithm || certSize != size {
+		return fmt.Sprintf("its %s %d key does not match the %s %d private key options", certAlgorithm, certSize, algorithm, size)
+	}
+	return ""
+}
+
+// getPrivateKeyOptions will return the algorithm and size in bits of the private keys generated for the given ArgoCD.
+// The size is zero for Ed25519 keys.
+func getPrivateKeyOptions(cr *argoprojv1b1.ArgoCD) (string, int) {
+	algorithm := common.ArgoCDDefaultTLSKeyAlgorithm
+	size := 0
+	if cr.Spec.TLS.PrivateKey != nil {
+		if len(cr.Spec.TLS.PrivateKey.Algorithm) > 0 {
+			algorithm = cr.Spec.TLS.PrivateKey.Algorithm
+		}
+		size = cr.Spec.TLS.PrivateKey.Size
+	}
+
+	switch algorithm {
+	case common.ArgoCDTLSKeyAlgorithmRSA:
+		if size == 0 {
+			size = common.ArgoCDDefaultRSAKeySize
+		}
+	case common.ArgoCDTLSKeyAlgorithmECDSA:
+		if size == 0 {
+			size = common.ArgoCDDefaultECDSAKeySize
+		}
+	case common.ArgoCDTLSKeyAlgorithmEd25519:
+		size = 0
+	}
+	return algorithm, size
+}
+
+// newPrivateKey will return a new private key using the private key options for the given ArgoCD.
+func newPrivateKey(cr *argoprojv1b1.ArgoCD) (crypto.Signer, error) {
+	algorithm, size := getPrivateKeyOptions(cr)
+	return argoutil.NewPrivateKey(algorithm, size)
+}
+
+// getCertificateOrganizations will return the subject organizations of the certificates generated for the given
+// ArgoCD, or the given defaults when not specified.
+func getCertificateOrganizations(cr *argoprojv1b1.ArgoCD, defaults []string) []string {
+	if cr.Spec.TLS.Subject != nil && len(cr.Spec.TLS.Subject.Organizations) > 0 {
+		return cr.Spec.TLS.Subject.Organizations
+	}
+	return defaults
+}
+
+// getCertificateDuration will return the given certificate validity, or the default when not specified.
+func getCertificateDuration(duration *metav1.Duration) time.Duration {
+	if duration != nil && duration.Duration > 0 {
+		return duration.Duration
+	}
+	return common.ArgoCDDefaultCertificateDuration
+}
+
+// getCertificateRenewBefore will return how long before expiry the operator-issued certificates for the given ArgoCD
+// are renewed. The default is used when the configured window is not shorter than the validity of the certificates,
+// and is shortened to a third of the validity for certificates that are valid for less than the default window.
+func getCertificateRenewBefore(cr *argoprojv1b1.ArgoCD) time.Duration {
+	validity := getCertificateDuration(cr.Spec.TLS.CA.Duration)
+	if d := getCertificateDuration(cr.Spec.TLS.Duration); d < validity {
+		validity = d
+	}
+
+	renewBefore := common.ArgoCDDefaultCertificateRenewBefore
+	if renewBefore >= validity {
+		renewBefore = validity / 3
+	}
+
+	if cr.Spec.TLS.RenewBefore != nil {
+		if d := cr.Spec.TLS.RenewBefore.Duration; d > 0 && d < validity {
+			renewBefore = d
+		} else {
+			log.Info(fmt.Sprintf("invalid certificate renewal window %s, using the default of %s", d, renewBefore))
+		}
+	}
+	return renewBefore
+}
+
+// isSignedByAny returns true if the given certificate was signed by one of the given CA certificates.
+func isSignedByAny(cert *x509.Certificate, caCerts []*x509.Certificate) bool {
+	for _, caCert := range caCerts {
+		if cert.CheckSignatureFrom(caCert) == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // reconcileClusterSecrets will reconcile all Secret resources for the ArgoCD cluster.
-func (r *ReconcileArgoCD) reconcileClusterSecrets(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileClusterSecrets(cr *argoprojv1b1.ArgoCD) error {
 	if err := r.reconcileClusterMainSecret(cr); err != nil {
 		return err
 	}
@@ -280,10 +686,26 @@ func (r *ReconcileArgoCD) reconcileClusterSecrets(cr *argoprojv1a1.ArgoCD) error
 		return err
 	}
 
+	if err := r.reconcileClusterRepoServerTLSSecret(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileClusterRedisTLSSecret(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileRedisAuthSecret(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileClusterPermissionsSecret(cr); err != nil {
 		return err
 	}
 
+	if err := r.reconcileClusterShards(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileGrafanaSecret(cr); err != nil {
 		return err
 	}
@@ -292,7 +714,7 @@ func (r *ReconcileArgoCD) reconcileClusterSecrets(cr *argoprojv1a1.ArgoCD) error
 }
 
 // reconcileExistingArgoSecret will ensure that the Argo CD Secret is up to date.
-func (r *ReconcileArgoCD) reconcileExistingArgoSecret(cr *argoprojv1a1.ArgoCD, secret *corev1.Secret, clusterSecret *corev1.Secret, tlsSecret *corev1.Secret) error {
+func (r *ReconcileArgoCD) reconcileExistingArgoSecret(cr *argoprojv1b1.ArgoCD, secret *corev1.Secret, clusterSecret *corev1.Secret, tlsSecret *corev1.Secret) error {
 	changed := false
 
 	if hasArgoAdminPasswordChanged(secret, clusterSecret) {
@@ -312,12 +734,30 @@ func (r *ReconcileArgoCD) reconcileExistingArgoSecret(cr *argoprojv1a1.ArgoCD, s
 		changed = true
 	}
 
+	keyRotation := getServerSecretKeyRotationReason(cr, secret)
+	if len(keyRotation) > 0 {
+		sessionKey, err := generateArgoServerSessionKey()
+		if err != nil {
+			return err
+		}
+
+		log.Info(fmt.Sprintf("rotating server secret key in argo secret [%s], %s", secret.Name, keyRotation))
+		secret.Data[common.ArgoCDKeyServerSecretKey] = sessionKey
+		changed = true
+	}
+
 	if changed {
 		log.Info("updating argo secret")
 		if err := r.client.Update(context.TODO(), secret); err != nil {
 			return err
 		}
 
+		if len(keyRotation) > 0 {
+			if err := r.reconcileServerSecretKeyRotated(cr, keyRotation); err != nil {
+				return err
+			}
+		}
+
 		// Trigger rollout of Argo Server Deployment
 		deploy := newDeploymentWithSuffix("server", "server", cr)
 		return r.triggerRollout(deploy, "secret.changed")
@@ -326,8 +766,27 @@ func (r *ReconcileArgoCD) reconcileExistingArgoSecret(cr *argoprojv1a1.ArgoCD, s
 	return nil
 }
 
+// reconcileServerSecretKeyRotated will record the rotation of the server secret key for the given ArgoCD with the
+// given reason, in the status and as an Event. The rotate annotation is removed from the ArgoCD once handled.
+func (r *ReconcileArgoCD) reconcileServerSecretKeyRotated(cr *argoprojv1b1.ArgoCD, reason string) error {
+	now := metav1.Now()
+	cr.Status.ServerSecretKeyRotationTime = &now
+	if err := r.client.Status().Update(context.TODO(), cr); err != nil {
+		return err
+	}
+
+	r.recorder.Event(cr, corev1.EventTypeNormal, argoprojv1b1.ArgoCDReasonServerSecretKeyRotated,
+		fmt.Sprintf("rotated the server secret key, %s", reason))
+
+	if _, ok := cr.Annotations[common.AnnotationRotateServerSecretKey]; ok {
+		delete(cr.Annotations, common.AnnotationRotateServerSecretKey)
+		return r.client.Update(context.TODO(), cr)
+	}
+	return nil
+}
+
 // reconcileGrafanaSecret will ensure that the Grafana Secret is present.
-func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1b1.ArgoCD) error {
 	if !cr.Spec.Grafana.Enabled {
 		return nil // Grafana not enabled, do nothing.
 	}
@@ -389,7 +848,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1a1.ArgoCD) error
 }
 
 // reconcileClusterPermissionsSecret ensures ArgoCD instance is namespace-scoped
-func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1b1.ArgoCD) error {
 	var clusterConfigInstance bool
 	secret := argoutil.NewSecretWithSuffix(cr.ObjectMeta, "default-cluster-config")
 	secret.Labels[common.ArgoCDSecretTypeLabel] = "cluster"
@@ -401,7 +860,7 @@ func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.Arg
 
 	secret.Data = map[string][]byte{
 		"config":     dataBytes,
-		"name":       []byte("in-cluster"),
+		"name":       []byte(common.ArgoCDInClusterName),
 		"server":     []byte(common.ArgoCDDefaultServer),
 		"namespaces": []byte(cr.Namespace),
 	}
@@ -441,11 +900,27 @@ func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.Arg
 	return r.client.Create(context.TODO(), secret)
 }
 
+// getTLSSecretChecksum will return the SHA256 checksum of the certificate and key in the given TLS secret, or an
+// empty string when either is missing.
+func getTLSSecretChecksum(secret *corev1.Secret) string {
+	// We do the checksum over a concatenated byte stream of cert + key
+	crt, crtOk := secret.Data[corev1.TLSCertKey]
+	key, keyOk := secret.Data[corev1.TLSPrivateKeyKey]
+	if !crtOk || !keyOk {
+		return ""
+	}
+
+	var sumBytes []byte
+	sumBytes = append(sumBytes, crt...)
+	sumBytes = append(sumBytes, key...)
+	return fmt.Sprintf("%x", sha256.Sum256(sumBytes))
+}
+
 // reconcileRepoServerTLSSecret checks whether the argocd-repo-server-tls secret
 // has changed since our last reconciliation loop. It does so by comparing the
 // checksum of tls.crt and tls.key in the status of the ArgoCD CR against the
 // values calculated from the live state in the cluster.
-func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1b1.ArgoCD) error {
 	var tlsSecretObj corev1.Secret
 	var sha256sum string
 
@@ -461,15 +936,7 @@ func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD)
 		// We only process secrets of type kubernetes.io/tls
 		return nil
 	} else {
-		// We do the checksum over a concatenated byte stream of cert + key
-		crt, crtOk := tlsSecretObj.Data[corev1.TLSCertKey]
-		key, keyOk := tlsSecretObj.Data[corev1.TLSPrivateKeyKey]
-		if crtOk && keyOk {
-			var sumBytes []byte
-			sumBytes = append(sumBytes, crt...)
-			sumBytes = append(sumBytes, key...)
-			sha256sum = fmt.Sprintf("%x", sha256.Sum256(sumBytes))
-		}
+		sha256sum = getTLSSecretChecksum(&tlsSecretObj)
 	}
 
 	// The content of the TLS secret has changed since we last looked if the
@@ -504,13 +971,78 @@ func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD)
 		if err != nil {
 			return err
 		}
+
+		// Trigger rollout of ApplicationSet controller, if present
+		appSetDepl := newDeploymentWithSuffix("applicationset-controller", "controller", cr)
+		err = r.triggerRollout(appSetDepl, "repo.tls.cert.changed")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileRedisTLSSecret checks whether the argocd-operator-redis-tls secret
+// has changed since our last reconciliation loop, in the same way as
+// reconcileRepoServerTLSSecret, and rolls out Redis and its clients when it has.
+func (r *ReconcileArgoCD) reconcileRedisTLSSecret(cr *argoprojv1b1.ArgoCD) error {
+	var tlsSecretObj corev1.Secret
+	var sha256sum string
+
+	if isRedisExternal(cr) {
+		return nil // The CA certificate of an external Redis is not tracked, do nothing.
 	}
 
+	log.Info("reconciling redis TLS secret")
+
+	tlsSecretName := types.NamespacedName{Namespace: cr.Namespace, Name: common.ArgoCDRedisServerTLSSecretName}
+	err := r.client.Get(context.TODO(), tlsSecretName, &tlsSecretObj)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if tlsSecretObj.Type != corev1.SecretTypeTLS {
+		// We only process secrets of type kubernetes.io/tls
+		return nil
+	} else {
+		sha256sum = getTLSSecretChecksum(&tlsSecretObj)
+	}
+
+	if cr.Status.RedisTLSChecksum == sha256sum {
+		return nil
+	}
+
+	// We store the value early to prevent a possible restart loop, as for
+	// the repo server TLS secret.
+	cr.Status.RedisTLSChecksum = sha256sum
+	if err := r.client.Status().Update(context.TODO(), cr); err != nil {
+		return err
+	}
+
+	rollouts := []interface{}{
+		newDeploymentWithSuffix("server", "server", cr),
+		newDeploymentWithSuffix("repo-server", "repo-server", cr),
+		newStatefulSetWithSuffix("application-controller", "application-controller", cr),
+	}
+	if cr.Spec.HA.Enabled {
+		rollouts = append(rollouts,
+			newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr),
+			newStatefulSetWithSuffix("redis-ha-server", "redis", cr))
+	} else {
+		rollouts = append(rollouts, newDeploymentWithSuffix("redis", "redis", cr))
+	}
+
+	for _, obj := range rollouts {
+		if err := r.triggerRollout(obj, "redis.tls.cert.changed"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // reconcileSecrets will reconcile all ArgoCD Secret resources.
-func (r *ReconcileArgoCD) reconcileSecrets(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileSecrets(cr *argoprojv1b1.ArgoCD) error {
 	if err := r.reconcileClusterSecrets(cr); err != nil {
 		return err
 	}
@@ -2,28 +2,36 @@ package argocd
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
 	"fmt"
 	"gotest.tools/assert"
+	"math/big"
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 
-	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 )
 
 func Test_newCASecret(t *testing.T) {
-	cr := &argoprojv1alpha1.ArgoCD{
+	cr := &argoprojv1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-argocd",
 			Namespace: "argocd",
@@ -54,7 +62,7 @@ func byteMapKeys(m map[string][]byte) []string {
 }
 
 func Test_ReconcileArgoCD_ReconcileRepoTLSSecret(t *testing.T) {
-	argocd := &v1alpha1.ArgoCD{
+	argocd := &v1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "argocd",
 			Namespace: "argocd-operator",
@@ -207,6 +215,426 @@ func Test_ReconcileArgoCD_ReconcileRepoTLSSecret(t *testing.T) {
 
 }
 
+func Test_ReconcileArgoCD_ReconcileRedisTLSSecret(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	secret := argoutil.NewTLSSecret(a.ObjectMeta, "redis-tls")
+	secret.Name = common.ArgoCDRedisServerTLSSecretName
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       []byte("foo"),
+		corev1.TLSPrivateKeyKey: []byte("bar"),
+	}
+	redisDepl := newDeploymentWithSuffix("redis", "redis", a)
+	serverDepl := newDeploymentWithSuffix("server", "server", a)
+	r := makeTestReconciler(t, a, secret, redisDepl, serverDepl)
+
+	assert.NilError(t, r.reconcileRedisTLSSecret(a))
+	assert.Equal(t, a.Status.RedisTLSChecksum, getTLSSecretChecksum(secret))
+
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: redisDepl.Name, Namespace: a.Namespace}, redisDepl))
+	redisRollout, ok := redisDepl.Spec.Template.ObjectMeta.Labels["redis.tls.cert.changed"]
+	assert.Assert(t, ok, "expected rollout of %s", redisDepl.Name)
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: serverDepl.Name, Namespace: a.Namespace}, serverDepl))
+	_, ok = serverDepl.Spec.Template.ObjectMeta.Labels["redis.tls.cert.changed"]
+	assert.Assert(t, ok, "expected rollout of %s", serverDepl.Name)
+
+	// No rollout when the secret has not changed
+	assert.NilError(t, r.reconcileRedisTLSSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: redisDepl.Name, Namespace: a.Namespace}, redisDepl))
+	assert.Equal(t, redisDepl.Spec.Template.ObjectMeta.Labels["redis.tls.cert.changed"], redisRollout)
+}
+
+// newTestCASecret returns a CA secret for the given ArgoCD with a certificate that expires at the given time.
+func newTestCASecret(t *testing.T, cr *argoprojv1beta1.ArgoCD, notAfter time.Time) *corev1.Secret {
+	t.Helper()
+	key, err := argoutil.NewPrivateKey(common.ArgoCDTLSKeyAlgorithmRSA, 0)
+	assert.NilError(t, err)
+	keyPEM, err := argoutil.EncodePrivateKeyPEM(key)
+	assert.NilError(t, err)
+
+	tmpl := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour).UTC(),
+		NotAfter:              notAfter.UTC(),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+
+	secret := argoutil.NewTLSSecret(cr.ObjectMeta, "ca")
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:              argoutil.EncodeCertificatePEM(cert),
+		corev1.ServiceAccountRootCAKey: argoutil.EncodeCertificatePEM(cert),
+		corev1.TLSPrivateKeyKey:        keyPEM,
+	}
+	return secret
+}
+
+func getTestSecretCertificate(t *testing.T, r *ReconcileArgoCD, name string) *x509.Certificate {
+	t.Helper()
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, secret))
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	return cert
+}
+
+func makeTestClusterMainSecret(a *argoprojv1beta1.ArgoCD, password string) *corev1.Secret {
+	secret := argoutil.NewSecretWithSuffix(a.ObjectMeta, "cluster")
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyAdminPassword: []byte(password),
+	}
+	return secret
+}
+
+func TestReconcileArgoCD_reconcileClusterMainSecret_rotation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	rotated := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.AdminPassword = &argoprojv1beta1.ArgoCDAdminPasswordSpec{
+			RotationInterval: &metav1.Duration{Duration: 24 * time.Hour},
+		}
+		a.Status.AdminPasswordRotationTime = &rotated
+	})
+	r := makeTestReconciler(t, a, makeTestClusterMainSecret(a, "previous"))
+
+	assert.NilError(t, r.reconcileClusterMainSecret(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cluster", Namespace: testNamespace}, secret))
+	password := string(secret.Data[common.ArgoCDKeyAdminPassword])
+	assert.Assert(t, password != "previous")
+	assert.Assert(t, time.Since(a.Status.AdminPasswordRotationTime.Time) < time.Minute)
+
+	// The password is kept until the rotation interval has passed again.
+	assert.NilError(t, r.reconcileClusterMainSecret(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cluster", Namespace: testNamespace}, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyAdminPassword]), password)
+}
+
+func TestReconcileArgoCD_reconcileClusterMainSecret_reset(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Annotations = map[string]string{common.AnnotationResetAdminPassword: "true"}
+	})
+	r := makeTestReconciler(t, a, makeTestClusterMainSecret(a, "previous"))
+
+	assert.NilError(t, r.reconcileClusterMainSecret(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cluster", Namespace: testNamespace}, secret))
+	assert.Assert(t, string(secret.Data[common.ArgoCDKeyAdminPassword]) != "previous")
+
+	// The reset annotation is removed once the password is reset.
+	cr := &argoprojv1beta1.ArgoCD{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}, cr))
+	_, ok := cr.Annotations[common.AnnotationResetAdminPassword]
+	assert.Assert(t, !ok)
+	assert.Assert(t, cr.Status.AdminPasswordRotationTime != nil)
+}
+
+func TestReconcileArgoCD_reconcileClusterMainSecret_secretRef(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.AdminPassword = &argoprojv1beta1.ArgoCDAdminPasswordSpec{
+			SecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "admin-password"},
+				Key:                  "password",
+			},
+		}
+	})
+	external := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin-password", Namespace: testNamespace},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	r := makeTestReconciler(t, a, external)
+
+	assert.NilError(t, r.reconcileClusterMainSecret(a))
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cluster", Namespace: testNamespace}, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyAdminPassword]), "s3cr3t")
+
+	// A change to the admin password Secret is copied to the cluster Secret.
+	external.Data["password"] = []byte("rotated")
+	assert.NilError(t, r.client.Update(context.TODO(), external))
+	assert.NilError(t, r.reconcileClusterMainSecret(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-cluster", Namespace: testNamespace}, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyAdminPassword]), "rotated")
+
+	// The cluster Secret is kept when the key is missing.
+	delete(external.Data, "password")
+	assert.NilError(t, r.client.Update(context.TODO(), external))
+	assert.ErrorContains(t, r.reconcileClusterMainSecret(a), "admin password secret [admin-password] has no key [password]")
+}
+
+func TestReconcileArgoCD_reconcileArgoSecret_secretKeyRotation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Annotations = map[string]string{common.AnnotationRotateServerSecretKey: "true"}
+	})
+	tlsSecret := argoutil.NewSecretWithSuffix(a.ObjectMeta, "tls")
+	tlsSecret.Data = map[string][]byte{
+		corev1.TLSCertKey:       []byte("cert"),
+		corev1.TLSPrivateKeyKey: []byte("key"),
+	}
+	r := makeTestReconciler(t, a, makeTestClusterMainSecret(a, "password"), tlsSecret)
+	key := types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}
+
+	// The key is not rotated when the Argo CD Secret is created.
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	sessionKey := string(secret.Data[common.ArgoCDKeyServerSecretKey])
+
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Assert(t, string(secret.Data[common.ArgoCDKeyServerSecretKey]) != sessionKey)
+	assert.Assert(t, a.Status.ServerSecretKeyRotationTime != nil)
+
+	recorder := r.recorder.(*record.FakeRecorder)
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Assert(t, strings.Contains(<-recorder.Events, "ServerSecretKeyRotated"))
+
+	// The rotate annotation is removed once the key is rotated.
+	cr := &argoprojv1beta1.ArgoCD{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: testArgoCDName, Namespace: testNamespace}, cr))
+	_, ok := cr.Annotations[common.AnnotationRotateServerSecretKey]
+	assert.Assert(t, !ok)
+
+	// The key is kept until the rotation interval has passed.
+	sessionKey = string(secret.Data[common.ArgoCDKeyServerSecretKey])
+	a.Spec.Server.SecretKey.RotationInterval = &metav1.Duration{Duration: 24 * time.Hour}
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Equal(t, string(secret.Data[common.ArgoCDKeyServerSecretKey]), sessionKey)
+
+	rotated := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+	a.Status.ServerSecretKeyRotationTime = &rotated
+	assert.NilError(t, r.reconcileArgoSecret(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, secret))
+	assert.Assert(t, string(secret.Data[common.ArgoCDKeyServerSecretKey]) != sessionKey)
+}
+
+func TestReconcileArgoCD_reconcileClusterCASecret_renewal(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	expiring := newTestCASecret(t, a, time.Now().Add(24*time.Hour))
+	r := makeTestReconciler(t, a, expiring)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: expiring.Name, Namespace: testNamespace}, secret))
+	caCert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	assert.Assert(t, !argoutil.CertificateNeedsRenewal(caCert, getCertificateRenewBefore(a)))
+
+	// The previous CA certificate is still trusted until it expires.
+	bundle, err := argoutil.ParsePEMEncodedCerts(secret.Data[corev1.ServiceAccountRootCAKey])
+	assert.NilError(t, err)
+	assert.Equal(t, len(bundle), 2)
+	assert.DeepEqual(t, argoutil.EncodeCertificatePEM(bundle[0]), secret.Data[corev1.TLSCertKey])
+	assert.DeepEqual(t, argoutil.EncodeCertificatePEM(bundle[1]), expiring.Data[corev1.TLSCertKey])
+}
+
+func TestReconcileArgoCD_reconcileClusterCASecret_withRenewBefore(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.TLS.RenewBefore = &metav1.Duration{Duration: time.Hour}
+	})
+	valid := newTestCASecret(t, a, time.Now().Add(24*time.Hour))
+	r := makeTestReconciler(t, a, valid)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+
+	cert := getTestSecretCertificate(t, r, valid.Name)
+	assert.DeepEqual(t, argoutil.EncodeCertificatePEM(cert), valid.Data[corev1.TLSCertKey])
+}
+
+func TestReconcileArgoCD_reconcileClusterTLSSecret_renewal(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	previous := getTestSecretCertificate(t, r, "argocd-tls")
+
+	// Nothing changes while the certificate is valid.
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.Assert(t, previous.Equal(getTestSecretCertificate(t, r, "argocd-tls")))
+
+	// Rotate the CA, keeping the previous CA certificate in the bundle.
+	caSecret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ca", Namespace: testNamespace}, caSecret))
+	renewed, err := newCASecret(a)
+	assert.NilError(t, err)
+	renewed.Data[corev1.ServiceAccountRootCAKey] = append(renewed.Data[corev1.ServiceAccountRootCAKey], caSecret.Data[corev1.TLSCertKey]...)
+	caSecret.Data = renewed.Data
+	assert.NilError(t, r.client.Update(context.TODO(), caSecret))
+
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+
+	caCert := getTestSecretCertificate(t, r, "argocd-ca")
+	cert := getTestSecretCertificate(t, r, "argocd-tls")
+	assert.Assert(t, !previous.Equal(cert))
+	assert.NilError(t, cert.CheckSignatureFrom(caCert))
+}
+
+func TestReconcileArgoCD_reconcileClusterSecrets_privateKeyMigration(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	previous := getTestSecretCertificate(t, r, "argocd-ca")
+	algorithm, size := argoutil.GetPublicKeyAlgorithm(previous.PublicKey)
+	assert.Equal(t, algorithm, common.ArgoCDTLSKeyAlgorithmRSA)
+	assert.Equal(t, size, common.ArgoCDDefaultRSAKeySize)
+
+	// The existing RSA material is replaced when the private key options change.
+	a.Spec.TLS.PrivateKey = &argoprojv1beta1.ArgoCDTLSPrivateKeySpec{
+		Algorithm: common.ArgoCDTLSKeyAlgorithmECDSA,
+		Size:      384,
+	}
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+
+	caCert := getTestSecretCertificate(t, r, "argocd-ca")
+	cert := getTestSecretCertificate(t, r, "argocd-tls")
+	for _, c := range []*x509.Certificate{caCert, cert} {
+		algorithm, size := argoutil.GetPublicKeyAlgorithm(c.PublicKey)
+		assert.Equal(t, algorithm, common.ArgoCDTLSKeyAlgorithmECDSA)
+		assert.Equal(t, size, 384)
+	}
+	assert.NilError(t, cert.CheckSignatureFrom(caCert))
+
+	// The previous RSA CA certificate is still trusted until it expires.
+	caSecret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ca", Namespace: testNamespace}, caSecret))
+	bundle, err := argoutil.ParsePEMEncodedCerts(caSecret.Data[corev1.ServiceAccountRootCAKey])
+	assert.NilError(t, err)
+	assert.Equal(t, len(bundle), 2)
+	assert.Assert(t, bundle[1].Equal(previous))
+
+	// Nothing changes once the keys match the options.
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.Assert(t, caCert.Equal(getTestSecretCertificate(t, r, "argocd-ca")))
+	assert.Assert(t, cert.Equal(getTestSecretCertificate(t, r, "argocd-tls")))
+}
+
+func Test_newCertificateSecret_withOptions(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.TLS.CA.Duration = &metav1.Duration{Duration: 180 * 24 * time.Hour}
+		a.Spec.TLS.Duration = &metav1.Duration{Duration: 90 * 24 * time.Hour}
+		a.Spec.TLS.PrivateKey = &argoprojv1beta1.ArgoCDTLSPrivateKeySpec{
+			Algorithm: common.ArgoCDTLSKeyAlgorithmEd25519,
+		}
+		a.Spec.TLS.Subject = &argoprojv1beta1.ArgoCDTLSSubjectSpec{
+			Organizations: []string{"Example Inc."},
+		}
+	})
+
+	caSecret, err := newCASecret(a)
+	assert.NilError(t, err)
+	caCert, err := argoutil.ParsePEMEncodedCert(caSecret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+
+	secret, err := newCertificateSecret("argocd-tls", getArgoServerDNSNames(a), caSecret, a)
+	assert.NilError(t, err)
+	cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, caCert.Subject.Organization, []string{"Example Inc."})
+	assert.DeepEqual(t, cert.Subject.Organization, []string{"Example Inc."})
+	assert.Equal(t, caCert.NotAfter.Sub(caCert.NotBefore).Round(time.Hour), 180*24*time.Hour)
+	assert.Assert(t, cert.NotAfter.Before(time.Now().Add(90*24*time.Hour+time.Minute)))
+	assert.Assert(t, cert.NotAfter.After(time.Now().Add(90*24*time.Hour-time.Minute)))
+
+	algorithm, _ := argoutil.GetPublicKeyAlgorithm(cert.PublicKey)
+	assert.Equal(t, algorithm, common.ArgoCDTLSKeyAlgorithmEd25519)
+	assert.NilError(t, cert.CheckSignatureFrom(caCert))
+}
+
+func Test_getCertificateRenewBefore(t *testing.T) {
+	a := makeTestArgoCD()
+	assert.Equal(t, getCertificateRenewBefore(a), common.ArgoCDDefaultCertificateRenewBefore)
+
+	// The default window is shortened for certificates valid for less than the window.
+	a.Spec.TLS.Duration = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	assert.Equal(t, getCertificateRenewBefore(a), 10*24*time.Hour)
+
+	// A window that is not shorter than the validity is ignored.
+	a.Spec.TLS.RenewBefore = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	assert.Equal(t, getCertificateRenewBefore(a), 10*24*time.Hour)
+
+	a.Spec.TLS.RenewBefore = &metav1.Duration{Duration: 7 * 24 * time.Hour}
+	assert.Equal(t, getCertificateRenewBefore(a), 7*24*time.Hour)
+}
+
+func TestReconcileArgoCD_reconcileClusterRepoServerTLSSecret(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+
+	// The secret is only issued by the operator when requested.
+	assert.NilError(t, r.reconcileClusterRepoServerTLSSecret(a))
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRepoServerTLSSecretName, Namespace: testNamespace}, secret)
+	assert.Assert(t, apierrors.IsNotFound(err))
+
+	a.Spec.Repo.AutoTLS = common.ArgoCDRepoServerAutoTLSOperator
+	assert.NilError(t, r.reconcileClusterRepoServerTLSSecret(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDRepoServerTLSSecretName, Namespace: testNamespace}, secret))
+	assert.Equal(t, secret.Type, corev1.SecretTypeTLS)
+
+	caSecret := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-ca", Namespace: testNamespace}, caSecret))
+	assert.DeepEqual(t, secret.Data[corev1.ServiceAccountRootCAKey], caSecret.Data[corev1.ServiceAccountRootCAKey])
+
+	// The certificate is valid for the repo server service.
+	caCerts, err := argoutil.ParsePEMEncodedCerts(secret.Data[corev1.ServiceAccountRootCAKey])
+	assert.NilError(t, err)
+	roots := x509.NewCertPool()
+	for _, caCert := range caCerts {
+		roots.AddCert(caCert)
+	}
+	cert := getTestSecretCertificate(t, r, common.ArgoCDRepoServerTLSSecretName)
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "argocd-repo-server.argocd.svc", Roots: roots})
+	assert.NilError(t, err)
+
+	// Nothing changes while the certificate is valid.
+	assert.NilError(t, r.reconcileClusterRepoServerTLSSecret(a))
+	assert.Assert(t, cert.Equal(getTestSecretCertificate(t, r, common.ArgoCDRepoServerTLSSecretName)))
+}
+
+func TestReconcileArgoCD_reconcileClusterTLSSecret_notIssuedByOperator(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	custom := newTestCASecret(t, a, time.Now().Add(time.Hour))
+	custom.Name = "argocd-tls"
+	r := makeTestReconciler(t, a, custom)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+
+	cert := getTestSecretCertificate(t, r, "argocd-tls")
+	assert.DeepEqual(t, argoutil.EncodeCertificatePEM(cert), custom.Data[corev1.TLSCertKey])
+}
+
 func Test_ReconcileArgoCD_ClusterPermissionsSecret(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -18,17 +18,16 @@ import (
 	"context"
 	"fmt"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // getArgoServerServiceType will return the server Service type for the ArgoCD.
-func getArgoServerServiceType(cr *argoprojv1a1.ArgoCD) corev1.ServiceType {
+func getArgoServerServiceType(cr *argoprojv1b1.ArgoCD) corev1.ServiceType {
 	if len(cr.Spec.Server.Service.Type) > 0 {
 		return cr.Spec.Server.Service.Type
 	}
@@ -36,7 +35,7 @@ func getArgoServerServiceType(cr *argoprojv1a1.ArgoCD) corev1.ServiceType {
 }
 
 // newService returns a new Service for the given ArgoCD instance.
-func newService(cr *argoprojv1a1.ArgoCD) *corev1.Service {
+func newService(cr *argoprojv1b1.ArgoCD) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -47,7 +46,7 @@ func newService(cr *argoprojv1a1.ArgoCD) *corev1.Service {
 }
 
 // newServiceWithName returns a new Service instance for the given ArgoCD using the given name.
-func newServiceWithName(name string, component string, cr *argoprojv1a1.ArgoCD) *corev1.Service {
+func newServiceWithName(name string, component string, cr *argoprojv1b1.ArgoCD) *corev1.Service {
 	svc := newService(cr)
 	svc.ObjectMeta.Name = name
 
@@ -60,22 +59,18 @@ func newServiceWithName(name string, component string, cr *argoprojv1a1.ArgoCD)
 }
 
 // newServiceWithSuffix returns a new Service instance for the given ArgoCD using the given suffix.
-func newServiceWithSuffix(suffix string, component string, cr *argoprojv1a1.ArgoCD) *corev1.Service {
+func newServiceWithSuffix(suffix string, component string, cr *argoprojv1b1.ArgoCD) *corev1.Service {
 	return newServiceWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), component, cr)
 }
 
 // reconcileDexService will ensure that the Service for Dex is present.
-func (r *ReconcileArgoCD) reconcileDexService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileDexService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("dex-server", "dex-server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		if isDexDisabled() {
+	if isDexDisabled() {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
 			// Service exists but enabled flag has been set to false, delete the Service
 			return r.client.Delete(context.TODO(), svc)
 		}
-		return nil
-	}
-
-	if isDexDisabled() {
 		return nil // Dex is disabled, do nothing
 	}
 
@@ -97,24 +92,17 @@ func (r *ReconcileArgoCD) reconcileDexService(cr *argoprojv1a1.ArgoCD) error {
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
 // reconcileGrafanaService will ensure that the Service for Grafana is present.
-func (r *ReconcileArgoCD) reconcileGrafanaService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileGrafanaService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("grafana", "grafana", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		if !cr.Spec.Grafana.Enabled {
+	if !cr.Spec.Grafana.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
 			// Service exists but enabled flag has been set to false, delete the Service
 			return r.client.Delete(context.TODO(), svc)
 		}
-		return nil // Service found, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled {
 		return nil // Grafana not enabled, do nothing.
 	}
 
@@ -131,19 +119,12 @@ func (r *ReconcileArgoCD) reconcileGrafanaService(cr *argoprojv1a1.ArgoCD) error
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
 // reconcileMetricsService will ensure that the Service for the Argo CD application controller metrics is present.
-func (r *ReconcileArgoCD) reconcileMetricsService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileMetricsService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("metrics", "metrics", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		// Service found, do nothing
-		return nil
-	}
 
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("application-controller", cr),
@@ -158,19 +139,14 @@ func (r *ReconcileArgoCD) reconcileMetricsService(cr *argoprojv1a1.ArgoCD) error
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
 // reconcileRedisHAAnnounceServices will ensure that the announce Services are present for Redis when running in HA mode.
-func (r *ReconcileArgoCD) reconcileRedisHAAnnounceServices(cr *argoprojv1a1.ArgoCD) error {
-	for i := int32(0); i < common.ArgoCDDefaultRedisHAReplicas; i++ {
+func (r *ReconcileArgoCD) reconcileRedisHAAnnounceServices(cr *argoprojv1b1.ArgoCD) error {
+	replicas := *getRedisHAReplicas(cr)
+	for i := int32(0); i < replicas; i++ {
 		svc := newServiceWithSuffix(fmt.Sprintf("redis-ha-announce-%d", i), "redis", cr)
-		if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-			return nil // Service found, do nothing
-		}
 
 		svc.ObjectMeta.Annotations = map[string]string{
 			common.ArgoCDKeyTolerateUnreadyEndpounts: "true",
@@ -197,23 +173,26 @@ func (r *ReconcileArgoCD) reconcileRedisHAAnnounceServices(cr *argoprojv1a1.Argo
 			},
 		}
 
-		if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
+		if err := r.applyObject(cr, svc); err != nil {
 			return err
 		}
+	}
 
-		if err := r.client.Create(context.TODO(), svc); err != nil {
+	// Remove the announce Services of the replicas that are no longer present after a scale down.
+	for i := replicas; ; i++ {
+		svc := newServiceWithSuffix(fmt.Sprintf("redis-ha-announce-%d", i), "redis", cr)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
+			return nil
+		}
+		if err := r.client.Delete(context.TODO(), svc); err != nil {
 			return err
 		}
 	}
-	return nil
 }
 
 // reconcileRedisHAMasterService will ensure that the "master" Service is present for Redis when running in HA mode.
-func (r *ReconcileArgoCD) reconcileRedisHAMasterService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisHAMasterService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("redis-ha", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		return nil // Service found, do nothing
-	}
 
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("redis-ha", cr),
@@ -233,18 +212,12 @@ func (r *ReconcileArgoCD) reconcileRedisHAMasterService(cr *argoprojv1a1.ArgoCD)
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
 // reconcileRedisHAProxyService will ensure that the HA Proxy Service is present for Redis when running in HA mode.
-func (r *ReconcileArgoCD) reconcileRedisHAProxyService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisHAProxyService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("redis-ha-haproxy", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		return nil // Service found, do nothing
-	}
 
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("redis-ha-haproxy", cr),
@@ -259,14 +232,11 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyService(cr *argoprojv1a1.ArgoCD)
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
 // reconcileRedisHAServices will ensure that all required Services are present for Redis when running in HA mode.
-func (r *ReconcileArgoCD) reconcileRedisHAServices(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisHAServices(cr *argoprojv1b1.ArgoCD) error {
 	if err := r.reconcileRedisHAAnnounceServices(cr); err != nil {
 		return err
 	}
@@ -282,11 +252,8 @@ func (r *ReconcileArgoCD) reconcileRedisHAServices(cr *argoprojv1a1.ArgoCD) erro
 }
 
 // reconcileRedisService will ensure that the Service for Redis is present.
-func (r *ReconcileArgoCD) reconcileRedisService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("redis", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		return nil // Service found, do nothing
-	}
 
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("redis", cr),
@@ -301,43 +268,28 @@ func (r *ReconcileArgoCD) reconcileRedisService(cr *argoprojv1a1.ArgoCD) error {
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
-func ensureAutoTLSAnnotation(cr *argoprojv1a1.ArgoCD, svc *corev1.Service) bool {
+// ensureAutoTLSAnnotation will add the annotation requesting a serving certificate to the given Service when AutoTLS
+// is configured for the repo server.
+func ensureAutoTLSAnnotation(cr *argoprojv1b1.ArgoCD, svc *corev1.Service) {
 	autoTLSAnnotationName := ""
-	if cr.Spec.Repo.AutoTLS == "openshift" {
+	if cr.Spec.Repo.AutoTLS == common.ArgoCDRepoServerAutoTLSOpenShift {
 		autoTLSAnnotationName = "service.beta.openshift.io/serving-cert-secret-name"
 	}
 	if autoTLSAnnotationName != "" {
 		if svc.Annotations == nil {
 			svc.Annotations = make(map[string]string)
 		}
-		val, ok := svc.Annotations[autoTLSAnnotationName]
-		if !ok || val != common.ArgoCDRepoServerTLSSecretName {
-			log.Info(fmt.Sprintf("requesting AutoTLS on service %s", svc.ObjectMeta.Name))
-			svc.Annotations[autoTLSAnnotationName] = common.ArgoCDRepoServerTLSSecretName
-			return true
-		}
+		svc.Annotations[autoTLSAnnotationName] = common.ArgoCDRepoServerTLSSecretName
 	}
-
-	return false
 }
 
 // reconcileRepoService will ensure that the Service for the Argo CD repo server is present.
-func (r *ReconcileArgoCD) reconcileRepoService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRepoService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("repo-server", "repo-server", cr)
 
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		if ensureAutoTLSAnnotation(cr, svc) {
-			return r.client.Update(context.TODO(), svc)
-		}
-		return nil // Service found, do nothing
-	}
-
 	ensureAutoTLSAnnotation(cr, svc)
 
 	svc.Spec.Selector = map[string]string{
@@ -358,18 +310,12 @@ func (r *ReconcileArgoCD) reconcileRepoService(cr *argoprojv1a1.ArgoCD) error {
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
 // reconcileServerMetricsService will ensure that the Service for the Argo CD server metrics is present.
-func (r *ReconcileArgoCD) reconcileServerMetricsService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileServerMetricsService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("server-metrics", "server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		return nil // Service found, do nothing
-	}
 
 	svc.Spec.Selector = map[string]string{
 		common.ArgoCDKeyName: nameWithSuffix("server", cr),
@@ -384,18 +330,12 @@ func (r *ReconcileArgoCD) reconcileServerMetricsService(cr *argoprojv1a1.ArgoCD)
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
+	return r.applyObject(cr, svc)
 }
 
 // reconcileServerService will ensure that the Service is present for the Argo CD server component.
-func (r *ReconcileArgoCD) reconcileServerService(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileServerService(cr *argoprojv1b1.ArgoCD) error {
 	svc := newServiceWithSuffix("server", "server", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, svc.Name, svc) {
-		return nil // Service found, do nothing
-	}
 
 	svc.Spec.Ports = []corev1.ServicePort{
 		{
@@ -417,54 +357,5 @@ func (r *ReconcileArgoCD) reconcileServerService(cr *argoprojv1a1.ArgoCD) error
 
 	svc.Spec.Type = getArgoServerServiceType(cr)
 
-	if err := controllerutil.SetControllerReference(cr, svc, r.scheme); err != nil {
-		return err
-	}
-	return r.client.Create(context.TODO(), svc)
-}
-
-// reconcileServices will ensure that all Services are present for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileServices(cr *argoprojv1a1.ArgoCD) error {
-	err := r.reconcileDexService(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileGrafanaService(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileMetricsService(cr)
-	if err != nil {
-		return err
-	}
-
-	if cr.Spec.HA.Enabled {
-		err = r.reconcileRedisHAServices(cr)
-		if err != nil {
-			return err
-		}
-	} else {
-		err = r.reconcileRedisService(cr)
-		if err != nil {
-			return err
-		}
-	}
-
-	err = r.reconcileRepoService(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileServerMetricsService(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileServerService(cr)
-	if err != nil {
-		return err
-	}
-	return nil
+	return r.applyObject(cr, svc)
 }
@@ -18,7 +18,7 @@ import (
 	"context"
 	"fmt"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	corev1 "k8s.io/api/core/v1"
@@ -29,13 +29,13 @@ import (
 )
 
 // getDexOAuthRedirectURI will return the OAuth redirect URI for the Dex server.
-func (r *ReconcileArgoCD) getDexOAuthRedirectURI(cr *argoprojv1a1.ArgoCD) string {
+func (r *ReconcileArgoCD) getDexOAuthRedirectURI(cr *argoprojv1b1.ArgoCD) string {
 	uri := r.getArgoServerURI(cr)
 	return uri + common.ArgoCDDefaultDexOAuthRedirectPath
 }
 
 // newServiceAccount returns a new ServiceAccount instance.
-func newServiceAccount(cr *argoprojv1a1.ArgoCD) *corev1.ServiceAccount {
+func newServiceAccount(cr *argoprojv1b1.ArgoCD) *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -46,7 +46,7 @@ func newServiceAccount(cr *argoprojv1a1.ArgoCD) *corev1.ServiceAccount {
 }
 
 // newServiceAccountWithName creates a new ServiceAccount with the given name for the given ArgCD.
-func newServiceAccountWithName(name string, cr *argoprojv1a1.ArgoCD) *corev1.ServiceAccount {
+func newServiceAccountWithName(name string, cr *argoprojv1b1.ArgoCD) *corev1.ServiceAccount {
 	sa := newServiceAccount(cr)
 	sa.ObjectMeta.Name = fmt.Sprintf("%s-%s", cr.Name, name)
 
@@ -58,7 +58,7 @@ func newServiceAccountWithName(name string, cr *argoprojv1a1.ArgoCD) *corev1.Ser
 }
 
 // reconcileServiceAccounts will ensure that all ArgoCD Service Accounts are configured.
-func (r *ReconcileArgoCD) reconcileServiceAccounts(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileServiceAccounts(cr *argoprojv1b1.ArgoCD) error {
 
 	if err := r.reconcileServiceAccountPermissions(common.ArgoCDServerComponent, policyRuleForServer(), cr); err != nil {
 		return err
@@ -94,7 +94,7 @@ func (r *ReconcileArgoCD) reconcileServiceAccounts(cr *argoprojv1a1.ArgoCD) erro
 }
 
 // reconcileDexServiceAccount will ensure that the Dex ServiceAccount is configured properly for OpenShift OAuth.
-func (r *ReconcileArgoCD) reconcileDexServiceAccount(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileDexServiceAccount(cr *argoprojv1b1.ArgoCD) error {
 	if !cr.Spec.Dex.OpenShiftOAuth {
 		return nil // OpenShift OAuth not enabled, move along...
 	}
@@ -127,7 +127,7 @@ func (r *ReconcileArgoCD) reconcileDexServiceAccount(cr *argoprojv1a1.ArgoCD) er
 	return r.client.Update(context.TODO(), sa)
 }
 
-func (r *ReconcileArgoCD) reconcileServiceAccountClusterPermissions(name string, rules []v1.PolicyRule, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileServiceAccountClusterPermissions(name string, rules []v1.PolicyRule, cr *argoprojv1b1.ArgoCD) error {
 	var role *v1.ClusterRole
 	var sa *corev1.ServiceAccount
 	var err error
@@ -144,11 +144,11 @@ func (r *ReconcileArgoCD) reconcileServiceAccountClusterPermissions(name string,
 	return r.reconcileClusterRoleBinding(name, role, sa, cr)
 }
 
-func (r *ReconcileArgoCD) reconcileServiceAccountPermissions(name string, rules []v1.PolicyRule, cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileServiceAccountPermissions(name string, rules []v1.PolicyRule, cr *argoprojv1b1.ArgoCD) error {
 	return r.reconcileRoleBinding(name, rules, cr)
 }
 
-func (r *ReconcileArgoCD) reconcileServiceAccount(name string, cr *argoprojv1a1.ArgoCD) (*corev1.ServiceAccount, error) {
+func (r *ReconcileArgoCD) reconcileServiceAccount(name string, cr *argoprojv1b1.ArgoCD) (*corev1.ServiceAccount, error) {
 	sa := newServiceAccountWithName(name, cr)
 
 	exists := true
@@ -0,0 +1,194 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// getArgoApplicationControllerReplicas will return the replica count for the Application Controller StatefulSet.
+func getArgoApplicationControllerReplicas(cr *argoprojv1b1.ArgoCD) *int32 {
+	replicas := common.ArgoCDDefaultControllerReplicas
+	if cr.Spec.Controller.Sharding.Enabled && cr.Spec.Controller.Sharding.Replicas > 0 {
+		replicas = cr.Spec.Controller.Sharding.Replicas
+	}
+	return &replicas
+}
+
+// getArgoApplicationControllerEnv will return the environment variables for the Application Controller container.
+func getArgoApplicationControllerEnv(cr *argoprojv1b1.ArgoCD) []corev1.EnvVar {
+	env := getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, cr)
+	if cr.Spec.Controller.Sharding.Enabled {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ArgoCDControllerReplicasEnvName,
+			Value: fmt.Sprint(*getArgoApplicationControllerReplicas(cr)),
+		})
+	}
+	return proxyEnvVars(env...)
+}
+
+// getClusterShard will return the Application Controller shard that manages the cluster in the given Secret, the
+// same way the Application Controller assigns clusters to shards. False is returned when the Secret sets a shard that
+// is not valid or out of range, the cluster is then not managed by any replica.
+func getClusterShard(secret *corev1.Secret, replicas int32) (int32, bool) {
+	if value, ok := secret.Data[common.ArgoCDKeyClusterShard]; ok {
+		shard, err := strconv.ParseInt(string(value), 10, 32)
+		if err != nil || shard < 0 || int32(shard) >= replicas {
+			return 0, false
+		}
+		return int32(shard), true
+	}
+
+	// The clusters without an ID, such as the implicit in-cluster cluster, are managed by the first shard.
+	if secret.UID == "" {
+		return 0, true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(secret.UID))
+	return int32(h.Sum32() % uint32(replicas)), true
+}
+
+// getClusterName will return the display name for the cluster in the given Secret.
+func getClusterName(secret *corev1.Secret) string {
+	if name := string(secret.Data["name"]); name != "" {
+		return name
+	}
+	if server := string(secret.Data["server"]); server != "" {
+		return server
+	}
+	return secret.Name
+}
+
+// listClusterSecrets will return the cluster Secrets in the namespace of the given ArgoCD, sorted by name.
+func (r *ReconcileArgoCD) listClusterSecrets(cr *argoprojv1b1.ArgoCD) ([]corev1.Secret, error) {
+	secrets := &corev1.SecretList{}
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			common.ArgoCDSecretTypeLabel: "cluster",
+		}),
+		Namespace: cr.Namespace,
+	}
+	if err := r.client.List(context.TODO(), secrets, opts); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(secrets.Items, func(i, j int) bool {
+		return secrets.Items[i].Name < secrets.Items[j].Name
+	})
+	return secrets.Items, nil
+}
+
+// reconcileClusterShards will assign the cluster Secrets managed for the given ArgoCD to the Application Controller
+// shards in turn. The shard assignment is removed from the Secrets when sharding is disabled.
+func (r *ReconcileArgoCD) reconcileClusterShards(cr *argoprojv1b1.ArgoCD) error {
+	secrets, err := r.listClusterSecrets(cr)
+	if err != nil {
+		return err
+	}
+
+	replicas := *getArgoApplicationControllerReplicas(cr)
+	managed := int32(0)
+	for i := range secrets {
+		secret := &secrets[i]
+		if secret.Labels[common.ArgoCDKeyManagedBy] != cr.Name {
+			continue
+		}
+
+		shard := ""
+		if cr.Spec.Controller.Sharding.Enabled {
+			shard = fmt.Sprint(managed % replicas)
+		}
+		managed++
+
+		if string(secret.Data[common.ArgoCDKeyClusterShard]) == shard {
+			continue
+		}
+
+		if shard == "" {
+			delete(secret.Data, common.ArgoCDKeyClusterShard)
+		} else {
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte)
+			}
+			secret.Data[common.ArgoCDKeyClusterShard] = []byte(shard)
+		}
+
+		log.Info(fmt.Sprintf("assigning cluster secret [%s] to application controller shard [%s]", secret.Name, shard))
+		if err := r.client.Update(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileStatusApplicationControllerShards will ensure that the distribution of the clusters across the
+// Application Controller shards is updated in the Status for the given ArgoCD. The in-cluster cluster that Argo CD
+// adds without a Secret is reported on the first shard, the clusters with a shard out of range are reported as
+// unassigned.
+func (r *ReconcileArgoCD) reconcileStatusApplicationControllerShards(cr *argoprojv1b1.ArgoCD) error {
+	var shards []argoprojv1b1.ArgoCDApplicationControllerShardStatus
+	var unassigned []string
+	if cr.Spec.Controller.Sharding.Enabled {
+		secrets, err := r.listClusterSecrets(cr)
+		if err != nil {
+			return err
+		}
+
+		replicas := *getArgoApplicationControllerReplicas(cr)
+		shards = make([]argoprojv1b1.ArgoCDApplicationControllerShardStatus, replicas)
+		for i := range shards {
+			shards[i].Shard = int32(i)
+		}
+
+		inCluster := false
+		for i := range secrets {
+			if string(secrets[i].Data["server"]) == common.ArgoCDDefaultServer {
+				inCluster = true
+			}
+		}
+		if !inCluster {
+			shards[0].Clusters = append(shards[0].Clusters, common.ArgoCDInClusterName)
+		}
+
+		for i := range secrets {
+			shard, ok := getClusterShard(&secrets[i], replicas)
+			if !ok {
+				unassigned = append(unassigned, getClusterName(&secrets[i]))
+				continue
+			}
+			shards[shard].Clusters = append(shards[shard].Clusters, getClusterName(&secrets[i]))
+		}
+	}
+
+	if !reflect.DeepEqual(cr.Status.ApplicationControllerShards, shards) ||
+		!reflect.DeepEqual(cr.Status.ApplicationControllerUnassignedClusters, unassigned) {
+		cr.Status.ApplicationControllerShards = shards
+		cr.Status.ApplicationControllerUnassignedClusters = unassigned
+		return r.client.Status().Update(context.TODO(), cr)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package argocd
+
+import (
+	"context"
+	"hash/fnv"
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func makeTestClusterSecret(name, managedBy string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				common.ArgoCDSecretTypeLabel: "cluster",
+			},
+		},
+		Data: map[string][]byte{"name": []byte(name)},
+	}
+	if managedBy != "" {
+		secret.Labels[common.ArgoCDKeyManagedBy] = managedBy
+	}
+	for k, v := range data {
+		secret.Data[k] = []byte(v)
+	}
+	return secret
+}
+
+func withSharding(replicas int32) argoCDOpt {
+	return func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Controller.Sharding.Enabled = true
+		a.Spec.Controller.Sharding.Replicas = replicas
+	}
+}
+
+func TestReconcileArgoCD_reconcileApplicationControllerStatefulSet_sharding(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withSharding(3))
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	key := types.NamespacedName{Name: "argocd-application-controller", Namespace: a.Namespace}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ss))
+	assert.Equal(t, *ss.Spec.Replicas, int32(3))
+	env := getRedisAuthEnv(common.ArgoCDRedisPasswordEnvName, a)
+	assert.DeepEqual(t, ss.Spec.Template.Spec.Containers[0].Env,
+		proxyEnvVars(append(env, corev1.EnvVar{Name: common.ArgoCDControllerReplicasEnvName, Value: "3"})...))
+
+	// Disabling sharding scales the controller back to a single replica.
+	a.Spec.Controller.Sharding.Enabled = false
+	assert.NilError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss = &appsv1.StatefulSet{}
+	assert.NilError(t, r.client.Get(context.TODO(), key, ss))
+	assert.Equal(t, *ss.Spec.Replicas, int32(1))
+	assert.Equal(t, len(ss.Spec.Template.Spec.Containers[0].Env), len(proxyEnvVars(env...)))
+}
+
+func TestGetArgoApplicationControllerReplicas(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []argoCDOpt
+		want int32
+	}{
+		{"sharding disabled", nil, 1},
+		{"sharding enabled", []argoCDOpt{withSharding(4)}, 4},
+		{"sharding enabled without replicas", []argoCDOpt{withSharding(0)}, 1},
+		{"replicas without sharding", []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
+			a.Spec.Controller.Sharding.Replicas = 4
+		}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, *getArgoApplicationControllerReplicas(makeTestArgoCD(tt.opts...)), tt.want)
+		})
+	}
+}
+
+func TestGetClusterShard(t *testing.T) {
+	withUID := makeTestClusterSecret("remote", "", nil)
+	withUID.UID = "9f0fa3b2-0d6c-4f4c-8a47-0f7d5e0e0a4b"
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(withUID.UID))
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret
+		want     int32
+		assigned bool
+	}{
+		{"explicit shard", makeTestClusterSecret("remote", "", map[string]string{"shard": "2"}), 2, true},
+		{"shard out of range", makeTestClusterSecret("remote", "", map[string]string{"shard": "5"}), 0, false},
+		{"negative shard", makeTestClusterSecret("remote", "", map[string]string{"shard": "-1"}), 0, false},
+		{"invalid shard", makeTestClusterSecret("remote", "", map[string]string{"shard": "one"}), 0, false},
+		{"without uid", makeTestClusterSecret("remote", "", nil), 0, true},
+		{"hashed uid", withUID, int32(h.Sum32() % 3), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shard, assigned := getClusterShard(tt.secret, 3)
+			assert.Equal(t, shard, tt.want)
+			assert.Equal(t, assigned, tt.assigned)
+		})
+	}
+}
+
+func TestReconcileArgoCD_reconcileClusterShards(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withSharding(2))
+	objs := []runtime.Object{
+		a,
+		makeTestClusterSecret("cluster-a", a.Name, nil),
+		makeTestClusterSecret("cluster-b", a.Name, nil),
+		makeTestClusterSecret("cluster-c", a.Name, map[string]string{"shard": "1"}),
+		makeTestClusterSecret("unmanaged", "", map[string]string{"shard": "1"}),
+	}
+	r := makeTestReconciler(t, objs...)
+
+	assert.NilError(t, r.reconcileClusterShards(a))
+
+	want := map[string]string{
+		"cluster-a": "0",
+		"cluster-b": "1",
+		"cluster-c": "0",
+		"unmanaged": "1",
+	}
+	for name, shard := range want {
+		secret := &corev1.Secret{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: a.Namespace}, secret))
+		assert.Equal(t, string(secret.Data[common.ArgoCDKeyClusterShard]), shard, name)
+	}
+
+	// Disabling sharding removes the assignment from the managed Secrets only.
+	a.Spec.Controller.Sharding.Enabled = false
+	assert.NilError(t, r.reconcileClusterShards(a))
+
+	for name := range want {
+		secret := &corev1.Secret{}
+		assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: a.Namespace}, secret))
+		_, ok := secret.Data[common.ArgoCDKeyClusterShard]
+		assert.Equal(t, ok, name == "unmanaged", name)
+	}
+}
+
+func TestReconcileArgoCD_reconcileStatusApplicationControllerShards(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withSharding(2))
+	objs := []runtime.Object{
+		a,
+		makeTestClusterSecret("in-cluster", a.Name, map[string]string{"server": common.ArgoCDDefaultServer}),
+		makeTestClusterSecret("cluster-a", a.Name, map[string]string{"shard": "0"}),
+		makeTestClusterSecret("cluster-b", a.Name, map[string]string{"shard": "1"}),
+		makeTestClusterSecret("cluster-c", "", map[string]string{"shard": "1"}),
+	}
+	r := makeTestReconciler(t, objs...)
+
+	assert.NilError(t, r.reconcileStatusApplicationControllerShards(a))
+	assert.DeepEqual(t, a.Status.ApplicationControllerShards, []argoprojv1beta1.ArgoCDApplicationControllerShardStatus{
+		{Shard: 0, Clusters: []string{"cluster-a", "in-cluster"}},
+		{Shard: 1, Clusters: []string{"cluster-b", "cluster-c"}},
+	})
+	assert.Assert(t, a.Status.ApplicationControllerUnassignedClusters == nil)
+
+	a.Spec.Controller.Sharding.Enabled = false
+	assert.NilError(t, r.reconcileStatusApplicationControllerShards(a))
+	assert.Assert(t, a.Status.ApplicationControllerShards == nil)
+}
+
+func TestReconcileArgoCD_reconcileStatusApplicationControllerShards_unassigned(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(withSharding(2))
+	objs := []runtime.Object{
+		a,
+		makeTestClusterSecret("cluster-a", "", map[string]string{"shard": "1"}),
+		makeTestClusterSecret("cluster-b", "", map[string]string{"shard": "2"}),
+	}
+	r := makeTestReconciler(t, objs...)
+
+	// The cluster with a shard out of range is not managed by any replica.
+	assert.NilError(t, r.reconcileStatusApplicationControllerShards(a))
+	assert.DeepEqual(t, a.Status.ApplicationControllerShards, []argoprojv1beta1.ArgoCDApplicationControllerShardStatus{
+		{Shard: 0, Clusters: []string{"in-cluster"}},
+		{Shard: 1, Clusters: []string{"cluster-a"}},
+	})
+	assert.DeepEqual(t, a.Status.ApplicationControllerUnassignedClusters, []string{"cluster-b"})
+}
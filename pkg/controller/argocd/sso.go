@@ -18,7 +18,7 @@ import (
 	"context"
 	"fmt"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	oappsv1 "github.com/openshift/api/apps/v1"
 	template "github.com/openshift/api/template/v1"
@@ -99,8 +99,8 @@ func verifyTemplateAPI() error {
 	return nil
 }
 
-func (r *ReconcileArgoCD) reconcileSSO(cr *argoprojv1a1.ArgoCD) error {
-	if cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeKeycloak {
+func (r *ReconcileArgoCD) reconcileSSO(cr *argoprojv1b1.ArgoCD) error {
+	if cr.Spec.SSO.Provider == argoprojv1b1.SSOProviderTypeKeycloak {
 		// TemplateAPI is available, Install keycloack using openshift templates.
 		if IsTemplateAPIAvailable() {
 			templateInstanceRef, err := newKeycloakTemplateInstance(cr)
@@ -182,7 +182,7 @@ func (r *ReconcileArgoCD) reconcileSSO(cr *argoprojv1a1.ArgoCD) error {
 	return nil
 }
 
-func deleteSSOConfiguration(cr *argoprojv1a1.ArgoCD) error {
+func deleteSSOConfiguration(cr *argoprojv1b1.ArgoCD) error {
 
 	// If SSO is installed using OpenShift templates.
 	if IsTemplateAPIAvailable() {
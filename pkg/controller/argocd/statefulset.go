@@ -16,28 +16,121 @@ package argocd
 
 import (
 	"context"
+	"crypto/sha1"
 	"fmt"
-	"reflect"
-	"time"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func getRedisHAReplicas(cr *argoprojv1a1.ArgoCD) *int32 {
+// redisHASentinelIDs are the Sentinel IDs of the first Redis HA replicas.
+var redisHASentinelIDs = []string{
+	"25b71bd9d0e4a51945d8422cab53f27027397c12",
+	"896627000a81c7bdad8dbdcffd39728c9c17b309",
+	"3acbca861108bc47379b71b1d87d1c137dce591f",
+}
+
+// getRedisHAReplicas will return the replica count for the Redis HA StatefulSet for the given ArgoCD.
+func getRedisHAReplicas(cr *argoprojv1b1.ArgoCD) *int32 {
 	replicas := common.ArgoCDDefaultRedisHAReplicas
-	// TODO: Allow override of this value through CR?
+	if cr.Spec.HA.Replicas != nil {
+		replicas = *cr.Spec.HA.Replicas
+	}
 	return &replicas
 }
 
+// getRedisHAMajority will return the number of Redis HA replicas that is a majority for the given ArgoCD. A majority
+// of the Sentinels must be available to fail over the Redis master.
+func getRedisHAMajority(cr *argoprojv1b1.ArgoCD) int32 {
+	return *getRedisHAReplicas(cr)/2 + 1
+}
+
+// getRedisHAQuorum will return the number of Sentinels that must agree that the Redis master is unreachable for the
+// given ArgoCD.
+func getRedisHAQuorum(cr *argoprojv1b1.ArgoCD) int32 {
+	if cr.Spec.HA.SentinelQuorum != nil {
+		return *cr.Spec.HA.SentinelQuorum
+	}
+	return getRedisHAMajority(cr)
+}
+
+// getRedisHASentinelIDEnv will return an environment variable with the Sentinel ID for each Redis HA replica. The IDs
+// of the replicas beyond the first ones are derived from the name of the replica.
+func getRedisHASentinelIDEnv(cr *argoprojv1b1.ArgoCD) []corev1.EnvVar {
+	env := make([]corev1.EnvVar, 0)
+	for i := 0; i < int(*getRedisHAReplicas(cr)); i++ {
+		id := fmt.Sprintf("%x", sha1.Sum([]byte(nameWithSuffix(fmt.Sprintf("redis-ha-server-%d", i), cr))))
+		if i < len(redisHASentinelIDs) {
+			id = redisHASentinelIDs[i]
+		}
+		env = append(env, corev1.EnvVar{
+			Name:  fmt.Sprintf("SENTINEL_ID_%d", i),
+			Value: id,
+		})
+	}
+	return env
+}
+
+// getRedisHATopologySpreadConstraints will return the constraints that spread the pods with the given name evenly
+// across zones, or no constraints when not requested for the given ArgoCD.
+func getRedisHATopologySpreadConstraints(name string, cr *argoprojv1b1.ArgoCD) []corev1.TopologySpreadConstraint {
+	if !cr.Spec.HA.SpreadAcrossZones {
+		return nil
+	}
+	return []corev1.TopologySpreadConstraint{{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				common.ArgoCDKeyName: name,
+			},
+		},
+		MaxSkew:           1,
+		TopologyKey:       common.ArgoCDKeyFailureDomainZone,
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}}
+}
+
+// getRedisHAVolumeClaimTemplates will return the template of the data volume claim of the Redis HA replicas, or no
+// templates when the data is kept in an emptyDir volume for the given ArgoCD.
+func getRedisHAVolumeClaimTemplates(cr *argoprojv1b1.ArgoCD) []corev1.PersistentVolumeClaim {
+	if cr.Spec.HA.VolumeClaimTemplate == nil {
+		return nil
+	}
+	return []corev1.PersistentVolumeClaim{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "data",
+		},
+		Spec: *cr.Spec.HA.VolumeClaimTemplate.DeepCopy(),
+	}}
+}
+
+// volumeClaimTemplatesChanged returns true if the desired volume claim templates request other volumes than the
+// existing templates of a StatefulSet.
+func volumeClaimTemplatesChanged(existing, desired []corev1.PersistentVolumeClaim) bool {
+	if len(existing) != len(desired) {
+		return true
+	}
+	for i := range desired {
+		current, want := existing[i].Spec, desired[i].Spec
+		if existing[i].Name != desired[i].Name ||
+			!equality.Semantic.DeepEqual(current.AccessModes, want.AccessModes) ||
+			!equality.Semantic.DeepEqual(current.Resources, want.Resources) {
+			return true
+		}
+		if want.StorageClassName != nil && !equality.Semantic.DeepEqual(current.StorageClassName, want.StorageClassName) {
+			return true
+		}
+	}
+	return false
+}
+
 // newStatefulSet returns a new StatefulSet instance for the given ArgoCD instance.
-func newStatefulSet(cr *argoprojv1a1.ArgoCD) *appsv1.StatefulSet {
+func newStatefulSet(cr *argoprojv1b1.ArgoCD) *appsv1.StatefulSet {
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -48,7 +141,8 @@ func newStatefulSet(cr *argoprojv1a1.ArgoCD) *appsv1.StatefulSet {
 }
 
 // newStatefulSetWithName returns a new StatefulSet instance for the given ArgoCD using the given name.
-func newStatefulSetWithName(name string, component string, cr *argoprojv1a1.ArgoCD) *appsv1.StatefulSet {
+// The pod overrides for the given component are merged into the pod template when the StatefulSet is applied.
+func newStatefulSetWithName(name string, component string, cr *argoprojv1b1.ArgoCD) *appsv1.StatefulSet {
 	ss := newStatefulSet(cr)
 	ss.ObjectMeta.Name = name
 
@@ -78,37 +172,17 @@ func newStatefulSetWithName(name string, component string, cr *argoprojv1a1.Argo
 }
 
 // newStatefulSetWithSuffix returns a new StatefulSet instance for the given ArgoCD using the given suffix.
-func newStatefulSetWithSuffix(suffix string, component string, cr *argoprojv1a1.ArgoCD) *appsv1.StatefulSet {
+func newStatefulSetWithSuffix(suffix string, component string, cr *argoprojv1b1.ArgoCD) *appsv1.StatefulSet {
 	return newStatefulSetWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), component, cr)
 }
 
-func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1b1.ArgoCD) error {
 	ss := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, ss.Name, ss) {
-		if !cr.Spec.HA.Enabled {
+	if !cr.Spec.HA.Enabled {
+		if argoutil.IsObjectFound(r.client, cr.Namespace, ss.Name, ss) {
 			// StatefulSet exists but HA enabled flag has been set to false, delete the StatefulSet
 			return r.client.Delete(context.TODO(), ss)
 		}
-
-		desiredImage := getRedisHAContainerImage(cr)
-		changed := false
-
-		for i, container := range ss.Spec.Template.Spec.Containers {
-			if container.Image != desiredImage {
-				ss.Spec.Template.Spec.Containers[i].Image = getRedisHAContainerImage(cr)
-				changed = true
-			}
-		}
-
-		if changed {
-			ss.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			return r.client.Update(context.TODO(), ss)
-		}
-
-		return nil // StatefulSet found, do nothing
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
@@ -124,13 +198,14 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 
 	ss.Spec.Template.ObjectMeta = metav1.ObjectMeta{
 		Annotations: map[string]string{
-			"checksum/init-config": "552ee3bec8fe5d9d865e371f7b615c6d472253649eb65d53ed4ae874f782647c", // TODO: Should this be hard-coded?
+			common.ArgoCDKeyRedisHAConfigChecksum: getRedisHAConfigChecksum(cr),
 		},
 		Labels: map[string]string{
 			common.ArgoCDKeyName: nameWithSuffix("redis-ha", cr),
 		},
 	}
 
+	ss.Spec.Template.Spec.TopologySpreadConstraints = getRedisHATopologySpreadConstraints(nameWithSuffix("redis-ha", cr), cr)
 	ss.Spec.Template.Spec.Affinity = &corev1.Affinity{
 		PodAffinity: &corev1.PodAffinity{
 			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
@@ -179,12 +254,12 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 				Name:          "redis",
 			}},
 			Resources: getRedisResources(cr),
-			VolumeMounts: []corev1.VolumeMount{
+			VolumeMounts: append([]corev1.VolumeMount{
 				{
 					MountPath: "/data",
 					Name:      "data",
 				},
-			},
+			}, getRedisTLSVolumeMounts(cr)...),
 		},
 		{
 			Args: []string{
@@ -209,12 +284,12 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 				Name:          "sentinel",
 			}},
 			Resources: getRedisResources(cr),
-			VolumeMounts: []corev1.VolumeMount{
+			VolumeMounts: append([]corev1.VolumeMount{
 				{
 					MountPath: "/data",
 					Name:      "data",
 				},
-			},
+			}, getRedisTLSVolumeMounts(cr)...),
 		},
 	}
 
@@ -225,25 +300,12 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 		Command: []string{
 			"sh",
 		},
-		Env: []corev1.EnvVar{
-			{
-				Name:  "SENTINEL_ID_0",
-				Value: "25b71bd9d0e4a51945d8422cab53f27027397c12", // TODO: Should this be hard-coded?
-			},
-			{
-				Name:  "SENTINEL_ID_1",
-				Value: "896627000a81c7bdad8dbdcffd39728c9c17b309", // TODO: Should this be hard-coded?
-			},
-			{
-				Name:  "SENTINEL_ID_2",
-				Value: "3acbca861108bc47379b71b1d87d1c137dce591f", // TODO: Should this be hard-coded?
-			},
-		},
+		Env:             append(getRedisHASentinelIDEnv(cr), getRedisAuthEnv(common.ArgoCDRedisHAAuthEnvName, cr)...),
 		Image:           getRedisHAContainerImage(cr),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Name:            "config-init",
 		Resources:       getRedisResources(cr),
-		VolumeMounts: []corev1.VolumeMount{
+		VolumeMounts: append([]corev1.VolumeMount{
 			{
 				MountPath: "/readonly-config",
 				Name:      "config",
@@ -253,7 +315,7 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 				MountPath: "/data",
 				Name:      "data",
 			},
-		},
+		}, getRedisTLSVolumeMounts(cr)...),
 	}}
 
 	var fsGroup int64 = 1000
@@ -278,13 +340,20 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 					},
 				},
 			},
-		}, {
+		},
+	}
+
+	// The data volume is claimed for each replica when persistence is requested.
+	ss.Spec.VolumeClaimTemplates = getRedisHAVolumeClaimTemplates(cr)
+	if len(ss.Spec.VolumeClaimTemplates) == 0 {
+		ss.Spec.Template.Spec.Volumes = append(ss.Spec.Template.Spec.Volumes, corev1.Volume{
 			Name: "data",
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
-		},
+		})
 	}
+	ss.Spec.Template.Spec.Volumes = append(ss.Spec.Template.Spec.Volumes, getRedisTLSVolumes(cr)...)
 
 	ss.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
 		Type: appsv1.RollingUpdateStatefulSetStrategyType,
@@ -294,16 +363,23 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ss, r.scheme); err != nil {
-		return err
+	// The volume claim templates of a StatefulSet can not be updated, the StatefulSet is recreated instead. The
+	// PersistentVolumeClaims of the existing replicas are kept.
+	existing := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
+	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) &&
+		volumeClaimTemplatesChanged(existing.Spec.VolumeClaimTemplates, ss.Spec.VolumeClaimTemplates) {
+		log.Info(fmt.Sprintf("recreating statefulset [%s], the volume claim templates changed", existing.Name))
+		if err := r.client.Delete(context.TODO(), existing); err != nil {
+			return err
+		}
 	}
-	return r.client.Create(context.TODO(), ss)
+
+	return r.applyObject(cr, ss)
 }
 
-func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoprojv1a1.ArgoCD) error {
-	var replicas int32 = 1 // TODO: allow override using CR ?
+func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoprojv1b1.ArgoCD) error {
 	ss := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
-	ss.Spec.Replicas = &replicas
+	ss.Spec.Replicas = getArgoApplicationControllerReplicas(cr)
 
 	podSpec := &ss.Spec.Template.Spec
 	podSpec.Containers = []corev1.Container{{
@@ -321,7 +397,7 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 			InitialDelaySeconds: 5,
 			PeriodSeconds:       10,
 		},
-		Env: proxyEnvVars(),
+		Env: getArgoApplicationControllerEnv(cr),
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: 8082,
@@ -338,12 +414,12 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 			PeriodSeconds:       10,
 		},
 		Resources: getArgoApplicationControllerResources(cr),
-		VolumeMounts: []corev1.VolumeMount{
+		VolumeMounts: append([]corev1.VolumeMount{
 			{
 				Name:      "argocd-repo-server-tls",
 				MountPath: "/app/config/controller/tls",
 			},
-		},
+		}, getRedisTLSVolumeMounts(cr)...),
 	}}
 	podSpec.ServiceAccountName = nameWithSuffix("argocd-application-controller", cr)
 	podSpec.Volumes = []corev1.Volume{
@@ -357,6 +433,7 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 			},
 		},
 	}
+	podSpec.Volumes = append(podSpec.Volumes, getRedisTLSVolumes(cr)...)
 
 	ss.Spec.Template.Spec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: &corev1.PodAntiAffinity{
@@ -400,70 +477,19 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 			VolumeMounts:    getArgoImportVolumeMounts(export),
 		}}
 
-		podSpec.Volumes = getArgoImportVolumes(export)
-	}
-
-	existing := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
-	if argoutil.IsObjectFound(r.client, cr.Namespace, existing.Name, existing) {
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getArgoContainerImage(cr)
-		changed := false
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		desiredCommand := getArgoApplicationControllerCommand(cr)
-		if isRepoServerTLSVerificationRequested(cr) {
-			desiredCommand = append(desiredCommand, "--repo-server-strict-tls")
-		}
-		if !reflect.DeepEqual(desiredCommand, existing.Spec.Template.Spec.Containers[0].Command) {
-			existing.Spec.Template.Spec.Containers[0].Command = desiredCommand
-			changed = true
-		}
-
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			ss.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = ss.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-		if !reflect.DeepEqual(ss.Spec.Template.Spec.Volumes, existing.Spec.Template.Spec.Volumes) {
-			existing.Spec.Template.Spec.Volumes = ss.Spec.Template.Spec.Volumes
-			changed = true
-		}
-		if !reflect.DeepEqual(ss.Spec.Template.Spec.Containers[0].VolumeMounts,
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts) {
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts = ss.Spec.Template.Spec.Containers[0].VolumeMounts
-			changed = true
-		}
-
-		if changed {
-			return r.client.Update(context.TODO(), existing)
-		}
-		return nil // StatefulSet found with nothing to do, move along...
+		// The volumes mounted by the controller container are kept.
+		podSpec.Volumes = append(podSpec.Volumes, getArgoImportVolumes(export)...)
 	}
 
 	// Delete existing deployment for Application Controller, if any ..
 	deploy := newDeploymentWithSuffix("application-controller", "application-controller", cr)
 	if argoutil.IsObjectFound(r.client, deploy.Namespace, deploy.Name, deploy) {
-		r.client.Delete(context.TODO(), deploy)
-	}
-
-	if err := controllerutil.SetControllerReference(cr, ss, r.scheme); err != nil {
-		return err
+		if err := r.client.Delete(context.TODO(), deploy); err != nil {
+			return err
+		}
 	}
-	return r.client.Create(context.TODO(), ss)
-}
 
-// reconcileStatefulSets will ensure that all StatefulSets are present for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatefulSets(cr *argoprojv1a1.ArgoCD) error {
-	if err := r.reconcileApplicationControllerStatefulSet(cr); err != nil {
-		return err
-	}
-	if err := r.reconcileRedisStatefulSet(cr); err != nil {
-		return err
-	}
-	return nil
+	return r.applyObject(cr, ss)
 }
 
 // triggerStatefulSetRollout will update the label with the given key to trigger a new rollout of the StatefulSet.
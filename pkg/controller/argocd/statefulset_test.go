@@ -3,11 +3,13 @@ package argocd
 import (
 	"context"
 	"fmt"
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
 	"testing"
 
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
@@ -86,6 +88,98 @@ func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_enabled(t *testing.T) {
 	assert.ErrorContains(t, r.client.Get(context.TODO(), types.NamespacedName{Name: s.Name, Namespace: a.Namespace}, s), "not found")
 }
 
+func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_topology(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.HA.Replicas = int32Ptr(5)
+		a.Spec.HA.SpreadAcrossZones = true
+	})
+	r := makeTestReconciler(t, a)
+	s := newStatefulSetWithSuffix("redis-ha-server", "redis", a)
+
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: s.Name, Namespace: a.Namespace}, s))
+	assert.Equal(t, *s.Spec.Replicas, int32(5))
+
+	// Each replica has a distinct Sentinel ID.
+	ids := map[string]bool{}
+	for _, env := range s.Spec.Template.Spec.InitContainers[0].Env {
+		if strings.HasPrefix(env.Name, "SENTINEL_ID_") {
+			ids[env.Value] = true
+		}
+	}
+	assert.Equal(t, len(ids), 5)
+
+	want := []corev1.TopologySpreadConstraint{{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{common.ArgoCDKeyName: "argocd-redis-ha"},
+		},
+		MaxSkew:           1,
+		TopologyKey:       common.ArgoCDKeyFailureDomainZone,
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}}
+	assert.DeepEqual(t, s.Spec.Template.Spec.TopologySpreadConstraints, want)
+}
+
+func TestReconcileArgoCD_reconcileRedisStatefulSet_HA_volumeClaimTemplate(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+	s := newStatefulSetWithSuffix("redis-ha-server", "redis", a)
+
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: s.Name, Namespace: a.Namespace}, s))
+	assert.Equal(t, len(s.Spec.VolumeClaimTemplates), 0)
+	assert.Equal(t, s.Spec.Template.Spec.Volumes[1].Name, "data")
+	assert.Assert(t, s.Spec.Template.Spec.Volumes[1].EmptyDir != nil)
+
+	// The data volume is claimed for each replica when a template is given.
+	a.Spec.HA.VolumeClaimTemplate = &corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resourcev1.MustParse("1Gi"),
+			},
+		},
+	}
+	assert.NilError(t, r.reconcileRedisStatefulSet(a))
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: s.Name, Namespace: a.Namespace}, s))
+	assert.Equal(t, len(s.Spec.VolumeClaimTemplates), 1)
+	assert.Equal(t, s.Spec.VolumeClaimTemplates[0].Name, "data")
+	assert.DeepEqual(t, s.Spec.VolumeClaimTemplates[0].Spec, *a.Spec.HA.VolumeClaimTemplate)
+	for _, volume := range s.Spec.Template.Spec.Volumes {
+		assert.Assert(t, volume.Name != "data")
+	}
+}
+
+func Test_volumeClaimTemplatesChanged(t *testing.T) {
+	claim := func(size string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resourcev1.MustParse(size)},
+				},
+			},
+		}
+	}
+
+	assert.Assert(t, !volumeClaimTemplatesChanged(nil, nil))
+	assert.Assert(t, !volumeClaimTemplatesChanged([]corev1.PersistentVolumeClaim{claim("1Gi")}, []corev1.PersistentVolumeClaim{claim("1024Mi")}))
+	assert.Assert(t, volumeClaimTemplatesChanged(nil, []corev1.PersistentVolumeClaim{claim("1Gi")}))
+	assert.Assert(t, volumeClaimTemplatesChanged([]corev1.PersistentVolumeClaim{claim("1Gi")}, []corev1.PersistentVolumeClaim{claim("2Gi")}))
+
+	// The storage class of the existing template is kept when none is requested.
+	storageClass := "standard"
+	existing := claim("1Gi")
+	existing.Spec.StorageClassName = &storageClass
+	assert.Assert(t, !volumeClaimTemplatesChanged([]corev1.PersistentVolumeClaim{existing}, []corev1.PersistentVolumeClaim{claim("1Gi")}))
+}
+
 func TestReconcileArgoCD_reconcileApplicationController(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	a := makeTestArgoCD()
@@ -166,8 +260,8 @@ func TestReconcileArgoCD_reconcileApplicationController_withUpgrade(t *testing.T
 
 func TestReconcileArgoCD_reconcileApplicationController_withResources(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
-	a := makeTestArgoCDWithResources(func(a *argoprojv1alpha1.ArgoCD) {
-		a.Spec.Import = &argoprojv1alpha1.ArgoCDImportSpec{
+	a := makeTestArgoCDWithResources(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Import = &argoprojv1beta1.ArgoCDImportSpec{
 			Name: "testimport",
 		}
 	})
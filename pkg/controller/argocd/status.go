@@ -16,17 +16,37 @@ package argocd
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	oappsv1 "github.com/openshift/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 )
 
 // reconcileStatus will ensure that all of the Status properties are updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1b1.ArgoCD) error {
 	if err := r.reconcileStatusApplicationController(cr); err != nil {
 		return err
 	}
 
+	if err := r.reconcileStatusApplicationControllerShards(cr); err != nil {
+		return err
+	}
+
+	if err := r.reconcileStatusCertificates(cr); err != nil {
+		return err
+	}
+
 	if err := r.reconcileStatusDex(cr); err != nil {
 		return err
 	}
@@ -50,7 +70,7 @@ func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1a1.ArgoCD) error {
 }
 
 // reconcileStatusApplicationController will ensure that the ApplicationController Status is updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatusApplicationController(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileStatusApplicationController(cr *argoprojv1b1.ArgoCD) error {
 	status := "Unknown"
 
 	ss := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
@@ -71,8 +91,110 @@ func (r *ReconcileArgoCD) reconcileStatusApplicationController(cr *argoprojv1a1.
 	return nil
 }
 
+// getCertificateSecretNames will return the names of the TLS Secrets used by the given ArgoCD.
+func getCertificateSecretNames(cr *argoprojv1b1.ArgoCD) []string {
+	names := []string{
+		nameWithSuffix(common.ArgoCDCASuffix, cr),
+		nameWithSuffix("tls", cr),
+		common.ArgoCDRedisServerTLSSecretName,
+		common.ArgoCDRepoServerTLSSecretName,
+	}
+	sort.Strings(names)
+	return names
+}
+
+// reconcileStatusCertificates will ensure that the expiry of the certificates used by the given ArgoCD is reported in
+// the status and the metrics.
+func (r *ReconcileArgoCD) reconcileStatusCertificates(cr *argoprojv1b1.ArgoCD) error {
+	var certs []argoprojv1b1.ArgoCDCertificateStatus
+	for _, name := range getCertificateSecretNames(cr) {
+		secret := argoutil.NewSecretWithName(cr.ObjectMeta, name)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+			certificateExpiry.DeleteLabelValues(cr.Namespace, cr.Name, name)
+			continue
+		}
+
+		cert, err := argoutil.ParsePEMEncodedCert(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			log.Info(fmt.Sprintf("unable to parse certificate in secret [%s]: %v", name, err))
+			certificateExpiry.DeleteLabelValues(cr.Namespace, cr.Name, name)
+			continue
+		}
+
+		certs = append(certs, argoprojv1b1.ArgoCDCertificateStatus{
+			NotAfter:   metav1.NewTime(cert.NotAfter),
+			SecretName: name,
+		})
+		certificateExpiry.WithLabelValues(cr.Namespace, cr.Name, name).Set(float64(cert.NotAfter.Unix()))
+	}
+
+	// The times in the status are compared semantically, their location differs once decoded.
+	if !equality.Semantic.DeepEqual(cr.Status.Certificates, certs) {
+		cr.Status.Certificates = certs
+		return r.client.Status().Update(context.TODO(), cr)
+	}
+	return nil
+}
+
+// getCertificateRenewalRequeue will return the time until the first of the certificates in the status of the given
+// ArgoCD is due for renewal, or zero when no certificate is known yet.
+func getCertificateRenewalRequeue(cr *argoprojv1b1.ArgoCD) time.Duration {
+	var requeue time.Duration
+	for i, cert := range cr.Status.Certificates {
+		until := time.Until(cert.NotAfter.Add(-getCertificateRenewBefore(cr)))
+		if i == 0 || until < requeue {
+			requeue = until
+		}
+	}
+
+	if len(cr.Status.Certificates) > 0 && requeue < time.Minute {
+		requeue = time.Minute
+	}
+	return requeue
+}
+
+// getAdminPasswordRotationRequeue will return the time until the admin password for the given ArgoCD is due for
+// rotation, or zero when it is not rotated.
+func getAdminPasswordRotationRequeue(cr *argoprojv1b1.ArgoCD) time.Duration {
+	return getRotationRequeue(getAdminPasswordRotationInterval(cr), cr.Status.AdminPasswordRotationTime)
+}
+
+// getServerSecretKeyRotationRequeue will return the time until the server secret key for the given ArgoCD is due for
+// rotation, or zero when it is not rotated.
+func getServerSecretKeyRotationRequeue(cr *argoprojv1b1.ArgoCD) time.Duration {
+	return getRotationRequeue(getServerSecretKeyRotationInterval(cr), cr.Status.ServerSecretKeyRotationTime)
+}
+
+// getRotationRequeue will return the time until a value last rotated at the given time is due for rotation at the
+// given interval, or zero when the interval is zero. A full interval is assumed when the last rotation is not known.
+func getRotationRequeue(interval time.Duration, rotated *metav1.Time) time.Duration {
+	if interval == 0 {
+		return 0
+	}
+
+	requeue := interval
+	if rotated != nil {
+		requeue = time.Until(rotated.Add(interval))
+	}
+	if requeue < time.Minute {
+		requeue = time.Minute
+	}
+	return requeue
+}
+
+// getRequeueAfter will return the shortest of the given durations, leaving out the durations that are zero.
+func getRequeueAfter(durations ...time.Duration) time.Duration {
+	var requeue time.Duration
+	for _, d := range durations {
+		if d > 0 && (requeue == 0 || d < requeue) {
+			requeue = d
+		}
+	}
+	return requeue
+}
+
 // reconcileStatusDex will ensure that the Dex status is updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatusDex(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileStatusDex(cr *argoprojv1b1.ArgoCD) error {
 	status := "Unknown"
 
 	deploy := newDeploymentWithSuffix("dex-server", "dex-server", cr)
@@ -94,7 +216,7 @@ func (r *ReconcileArgoCD) reconcileStatusDex(cr *argoprojv1a1.ArgoCD) error {
 }
 
 // reconcileStatusPhase will ensure that the Status Phase is updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatusPhase(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileStatusPhase(cr *argoprojv1b1.ArgoCD) error {
 	phase := "Unknown"
 
 	if cr.Status.ApplicationController == "Running" && cr.Status.Redis == "Running" && cr.Status.Repo == "Running" && cr.Status.Server == "Running" {
@@ -110,11 +232,18 @@ func (r *ReconcileArgoCD) reconcileStatusPhase(cr *argoprojv1a1.ArgoCD) error {
 	return nil
 }
 
-// reconcileStatusRedis will ensure that the Redis status is updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatusRedis(cr *argoprojv1a1.ArgoCD) error {
+// reconcileStatusRedis will ensure that the Redis status is updated for the given ArgoCD. The status of an external
+// Redis is reported from a connectivity check.
+func (r *ReconcileArgoCD) reconcileStatusRedis(cr *argoprojv1b1.ArgoCD) error {
 	status := "Unknown"
 
-	if !cr.Spec.HA.Enabled {
+	if isRedisExternal(cr) {
+		status = "Running"
+		if err := r.pingRedisExternal(cr); err != nil {
+			log.Info(fmt.Sprintf("unable to connect to external redis [%s]: %v", getRedisExternalAddress(cr), err))
+			status = "Failed"
+		}
+	} else if !cr.Spec.HA.Enabled {
 		deploy := newDeploymentWithSuffix("redis", "redis", cr)
 		if argoutil.IsObjectFound(r.client, cr.Namespace, deploy.Name, deploy) {
 			status = "Pending"
@@ -145,7 +274,7 @@ func (r *ReconcileArgoCD) reconcileStatusRedis(cr *argoprojv1a1.ArgoCD) error {
 }
 
 // reconcileStatusRepo will ensure that the Repo status is updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatusRepo(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileStatusRepo(cr *argoprojv1b1.ArgoCD) error {
 	status := "Unknown"
 
 	deploy := newDeploymentWithSuffix("repo-server", "repo-server", cr)
@@ -167,7 +296,7 @@ func (r *ReconcileArgoCD) reconcileStatusRepo(cr *argoprojv1a1.ArgoCD) error {
 }
 
 // reconcileStatusServer will ensure that the Server status is updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatusServer(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileStatusServer(cr *argoprojv1b1.ArgoCD) error {
 	status := "Unknown"
 
 	deploy := newDeploymentWithSuffix("server", "server", cr)
@@ -188,3 +317,144 @@ func (r *ReconcileArgoCD) reconcileStatusServer(cr *argoprojv1a1.ArgoCD) error {
 	}
 	return nil
 }
+
+// reconcileStatusConditions will ensure that the status conditions and observed generation are updated for the
+// given ArgoCD, based on the outcome of the last reconciliation. The reason and reconcileErr describe the failure
+// when the reconciliation did not succeed, reconcileErr is nil otherwise.
+func (r *ReconcileArgoCD) reconcileStatusConditions(cr *argoprojv1b1.ArgoCD, reason string, reconcileErr error) error {
+	status := cr.Status.DeepCopy()
+	status.ObservedGeneration = cr.Generation
+
+	setReconciledConditions(cr, status, reason, reconcileErr)
+	setComponentConditions(cr, status)
+	r.setSSOReadyCondition(cr, status)
+	r.setExportReadyCondition(cr, status)
+
+	if reflect.DeepEqual(&cr.Status, status) {
+		return nil
+	}
+	cr.Status = *status
+	return r.client.Status().Update(context.TODO(), cr)
+}
+
+// setReconciledConditions will set the Reconciled and Degraded conditions from the outcome of the last reconciliation.
+func setReconciledConditions(cr *argoprojv1b1.ArgoCD, status *argoprojv1b1.ArgoCDStatus, reason string, reconcileErr error) {
+	if reconcileErr != nil {
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionReconciled, corev1.ConditionFalse, reason, reconcileErr.Error()))
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionDegraded, corev1.ConditionTrue, reason, reconcileErr.Error()))
+		return
+	}
+
+	argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionReconciled, corev1.ConditionTrue, argoprojv1b1.ArgoCDReasonReconcileSucceeded, ""))
+
+	// The v1alpha1 string fields that could not be converted are used as is, the ArgoCD is degraded until they are set.
+	if fieldErrs := argoprojv1a1.ValidateStringFields(cr); len(fieldErrs) > 0 {
+		fields := make([]string, 0, len(fieldErrs))
+		for _, fieldErr := range fieldErrs {
+			fields = append(fields, fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Detail))
+		}
+		msg := fmt.Sprintf("unable to parse the v1alpha1 string fields, their original values are used as is: %s", strings.Join(fields, "; "))
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionDegraded, corev1.ConditionTrue, argoprojv1b1.ArgoCDReasonInvalidStringField, msg))
+		return
+	}
+	argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionDegraded, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonReconcileSucceeded, ""))
+}
+
+// setComponentConditions will set the Available and Progressing conditions from the status of the components.
+func setComponentConditions(cr *argoprojv1b1.ArgoCD, status *argoprojv1b1.ArgoCDStatus) {
+	components := map[string]string{
+		"application-controller": status.ApplicationController,
+		"redis":                  status.Redis,
+		"repo-server":            status.Repo,
+		"server":                 status.Server,
+	}
+
+	pending := make([]string, 0)
+	for name, s := range components {
+		if s != "Running" {
+			pending = append(pending, name)
+		}
+	}
+
+	if len(pending) == 0 {
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionAvailable, corev1.ConditionTrue, argoprojv1b1.ArgoCDReasonComponentsRunning, ""))
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionProgressing, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonComponentsRunning, ""))
+		return
+	}
+
+	sort.Strings(pending)
+	msg := fmt.Sprintf("waiting for components to be running: %s", strings.Join(pending, ", "))
+	argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionAvailable, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonComponentsPending, msg))
+	argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionProgressing, corev1.ConditionTrue, argoprojv1b1.ArgoCDReasonComponentsPending, msg))
+}
+
+// setSSOReadyCondition will set the SSOReady condition when a single sign-on provider is configured for the given
+// ArgoCD, the condition is removed otherwise.
+func (r *ReconcileArgoCD) setSSOReadyCondition(cr *argoprojv1b1.ArgoCD, status *argoprojv1b1.ArgoCDStatus) {
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Provider == argoprojv1b1.SSOProviderTypeKeycloak {
+		if !IsTemplateAPIAvailable() {
+			argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionSSOReady, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonSSOUnsupported,
+				"keycloak is only supported on clusters with the OpenShift Template API"))
+			return
+		}
+
+		dc := &oappsv1.DeploymentConfig{}
+		if argoutil.IsObjectFound(r.client, cr.Namespace, defaultKeycloakIdentifier, dc) && dc.Status.AvailableReplicas == expectedReplicas {
+			argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionSSOReady, corev1.ConditionTrue, argoprojv1b1.ArgoCDReasonSSORunning, ""))
+			return
+		}
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionSSOReady, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonSSOPending, "waiting for keycloak to be running"))
+		return
+	}
+
+	if !isDexDisabled() && (cr.Spec.Dex.OpenShiftOAuth || len(getDexConnectors(cr)) > 0) {
+		if status.Dex == "Running" {
+			argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionSSOReady, corev1.ConditionTrue, argoprojv1b1.ArgoCDReasonSSORunning, ""))
+			return
+		}
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionSSOReady, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonSSOPending, "waiting for dex to be running"))
+		return
+	}
+
+	argoutil.RemoveCondition(&status.Conditions, argoprojv1b1.ArgoCDConditionSSOReady)
+}
+
+// setExportReadyCondition will set the ExportReady condition when the given ArgoCD imports an ArgoCDExport, the
+// condition is removed otherwise.
+func (r *ReconcileArgoCD) setExportReadyCondition(cr *argoprojv1b1.ArgoCD, status *argoprojv1b1.ArgoCDStatus) {
+	if cr.Spec.Import == nil {
+		argoutil.RemoveCondition(&status.Conditions, argoprojv1b1.ArgoCDConditionExportReady)
+		return
+	}
+
+	export := r.getArgoCDExport(cr)
+	switch {
+	case export == nil:
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionExportReady, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonExportNotFound,
+			fmt.Sprintf("ArgoCDExport %s not found", cr.Spec.Import.Name)))
+	case export.Status.Phase == common.ArgoCDStatusCompleted:
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionExportReady, corev1.ConditionTrue, argoprojv1b1.ArgoCDReasonExportCompleted, ""))
+	default:
+		argoutil.SetCondition(&status.Conditions, newCondition(cr, argoprojv1b1.ArgoCDConditionExportReady, corev1.ConditionFalse, argoprojv1b1.ArgoCDReasonExportPending,
+			fmt.Sprintf("ArgoCDExport %s is %s", export.Name, exportPhase(export))))
+	}
+}
+
+// exportPhase returns the phase of the given ArgoCDExport, or Unknown when the phase has not been set yet.
+func exportPhase(export *argoprojv1a1.ArgoCDExport) string {
+	if export.Status.Phase == "" {
+		return "Unknown"
+	}
+	return export.Status.Phase
+}
+
+// newCondition returns a new condition for the current generation of the given ArgoCD.
+func newCondition(cr *argoprojv1b1.ArgoCD, conditionType string, status corev1.ConditionStatus, reason, message string) argoprojv1b1.ArgoCDCondition {
+	return argoprojv1b1.ArgoCDCondition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: cr.Generation,
+		Reason:             reason,
+		Message:            message,
+	}
+}
@@ -0,0 +1,92 @@
+package argocd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+func TestReconcileArgoCD_reconcileStatusCertificates(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	assert.NilError(t, r.reconcileClusterCASecret(a))
+	assert.NilError(t, r.reconcileClusterTLSSecret(a))
+	assert.NilError(t, r.reconcileStatusCertificates(a))
+
+	caCert := getTestSecretCertificate(t, r, "argocd-ca")
+	cert := getTestSecretCertificate(t, r, "argocd-tls")
+	want := []argoprojv1beta1.ArgoCDCertificateStatus{
+		{NotAfter: metav1.NewTime(caCert.NotAfter), SecretName: "argocd-ca"},
+		{NotAfter: metav1.NewTime(cert.NotAfter), SecretName: "argocd-tls"},
+	}
+	assert.DeepEqual(t, a.Status.Certificates, want)
+
+	expiry := testutil.ToFloat64(certificateExpiry.WithLabelValues(testNamespace, testArgoCDName, "argocd-ca"))
+	assert.Equal(t, expiry, float64(caCert.NotAfter.Unix()))
+}
+
+func Test_getCertificateRenewalRequeue(t *testing.T) {
+	a := makeTestArgoCD()
+	assert.Equal(t, getCertificateRenewalRequeue(a), time.Duration(0))
+
+	a.Status.Certificates = []argoprojv1beta1.ArgoCDCertificateStatus{
+		{NotAfter: metav1.NewTime(time.Now().Add(40 * 24 * time.Hour)), SecretName: "argocd-ca"},
+	}
+	requeue := getCertificateRenewalRequeue(a)
+	assert.Assert(t, requeue > 9*24*time.Hour && requeue <= 10*24*time.Hour, "unexpected requeue %s", requeue)
+
+	// The certificate expiring first is renewed first, whether or not it is the CA.
+	a.Status.Certificates = append(a.Status.Certificates, argoprojv1beta1.ArgoCDCertificateStatus{
+		NotAfter: metav1.NewTime(time.Now().Add(35 * 24 * time.Hour)), SecretName: "argocd-tls",
+	})
+	requeue = getCertificateRenewalRequeue(a)
+	assert.Assert(t, requeue > 4*24*time.Hour && requeue <= 5*24*time.Hour, "unexpected requeue %s", requeue)
+
+	// A certificate due for renewal is retried shortly.
+	a.Status.Certificates[0].NotAfter = metav1.NewTime(time.Now().Add(time.Hour))
+	assert.Equal(t, getCertificateRenewalRequeue(a), time.Minute)
+}
+
+func Test_getAdminPasswordRotationRequeue(t *testing.T) {
+	a := makeTestArgoCD()
+	assert.Equal(t, getAdminPasswordRotationRequeue(a), time.Duration(0))
+
+	a.Spec.AdminPassword = &argoprojv1beta1.ArgoCDAdminPasswordSpec{
+		RotationInterval: &metav1.Duration{Duration: 24 * time.Hour},
+	}
+	assert.Equal(t, getAdminPasswordRotationRequeue(a), 24*time.Hour)
+
+	rotated := metav1.NewTime(time.Now().Add(-12 * time.Hour))
+	a.Status.AdminPasswordRotationTime = &rotated
+	requeue := getAdminPasswordRotationRequeue(a)
+	assert.Assert(t, requeue > 11*time.Hour && requeue <= 12*time.Hour, "unexpected requeue %s", requeue)
+
+	// A password due for rotation is retried shortly.
+	rotated = metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	assert.Equal(t, getAdminPasswordRotationRequeue(a), time.Minute)
+}
+
+func Test_getServerSecretKeyRotationRequeue(t *testing.T) {
+	a := makeTestArgoCD()
+	assert.Equal(t, getServerSecretKeyRotationRequeue(a), time.Duration(0))
+
+	rotated := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+	a.Spec.Server.SecretKey.RotationInterval = &metav1.Duration{Duration: 72 * time.Hour}
+	a.Status.ServerSecretKeyRotationTime = &rotated
+	requeue := getServerSecretKeyRotationRequeue(a)
+	assert.Assert(t, requeue > 47*time.Hour && requeue <= 48*time.Hour, "unexpected requeue %s", requeue)
+}
+
+func Test_getRequeueAfter(t *testing.T) {
+	assert.Equal(t, getRequeueAfter(), time.Duration(0))
+	assert.Equal(t, getRequeueAfter(0, time.Hour), time.Hour)
+	assert.Equal(t, getRequeueAfter(2*time.Hour, 0, time.Hour), time.Hour)
+}
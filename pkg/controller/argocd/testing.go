@@ -15,22 +15,30 @@
 package argocd
 
 import (
+	"context"
+	"crypto/x509/pkix"
 	"sort"
 	"testing"
 
 	"github.com/argoproj-labs/argocd-operator/pkg/apis"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 )
 
 const (
@@ -45,15 +53,50 @@ func makeTestReconciler(t *testing.T, objs ...runtime.Object) *ReconcileArgoCD {
 
 	cl := fake.NewFakeClientWithScheme(s, objs...)
 	return &ReconcileArgoCD{
-		client: cl,
-		scheme: s,
+		client:   &applyClient{Client: cl},
+		scheme:   s,
+		recorder: record.NewFakeRecorder(100),
 	}
 }
 
-type argoCDOpt func(*argoprojv1alpha1.ArgoCD)
+// applyClient adds support for server-side apply patches to the fake client, which does not implement them. An
+// applied object is created when missing and replaces the existing object otherwise. The field ownership rules of
+// server-side apply are covered by the argoutil tests against a test API server.
+type applyClient struct {
+	client.Client
+}
+
+// Patch will apply the given object for server-side apply patches, other patches are handled by the wrapped client.
+func (c *applyClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	existing := obj.DeepCopyObject()
+	err = c.Client.Get(ctx, types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}, existing)
+	if apierrors.IsNotFound(err) {
+		return c.Client.Create(ctx, obj)
+	} else if err != nil {
+		return err
+	}
+
+	existingAccessor, err := meta.Accessor(existing)
+	if err != nil {
+		return err
+	}
+	accessor.SetResourceVersion(existingAccessor.GetResourceVersion())
+	return c.Client.Update(ctx, obj)
+}
+
+type argoCDOpt func(*argoprojv1beta1.ArgoCD)
 
-func makeTestArgoCD(opts ...argoCDOpt) *argoprojv1alpha1.ArgoCD {
-	a := &argoprojv1alpha1.ArgoCD{
+func makeTestArgoCD(opts ...argoCDOpt) *argoprojv1beta1.ArgoCD {
+	a := &argoprojv1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      testArgoCDName,
 			Namespace: testNamespace,
@@ -65,18 +108,18 @@ func makeTestArgoCD(opts ...argoCDOpt) *argoprojv1alpha1.ArgoCD {
 	return a
 }
 
-func makeTestArgoCDForKeycloak(opts ...argoCDOpt) *argoprojv1alpha1.ArgoCD {
-	a := &argoprojv1alpha1.ArgoCD{
+func makeTestArgoCDForKeycloak(opts ...argoCDOpt) *argoprojv1beta1.ArgoCD {
+	a := &argoprojv1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      testArgoCDName,
 			Namespace: testNamespace,
 		},
-		Spec: argoprojv1alpha1.ArgoCDSpec{
-			SSO: &argoprojv1alpha1.ArgoCDSSOSpec{
+		Spec: argoprojv1beta1.ArgoCDSpec{
+			SSO: &argoprojv1beta1.ArgoCDSSOSpec{
 				Provider: "keycloak",
 			},
-			Server: argoprojv1alpha1.ArgoCDServerSpec{
-				Route: argoprojv1alpha1.ArgoCDRouteSpec{
+			Server: argoprojv1beta1.ArgoCDServerSpec{
+				Route: argoprojv1beta1.ArgoCDRouteSpec{
 					Enabled: true,
 				},
 			},
@@ -88,23 +131,23 @@ func makeTestArgoCDForKeycloak(opts ...argoCDOpt) *argoprojv1alpha1.ArgoCD {
 	return a
 }
 
-func makeTestArgoCDWithResources(opts ...argoCDOpt) *argoprojv1alpha1.ArgoCD {
-	a := &argoprojv1alpha1.ArgoCD{
+func makeTestArgoCDWithResources(opts ...argoCDOpt) *argoprojv1beta1.ArgoCD {
+	a := &argoprojv1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      testArgoCDName,
 			Namespace: testNamespace,
 		},
-		Spec: argoprojv1alpha1.ArgoCDSpec{
-			ApplicationSet: &argoprojv1alpha1.ArgoCDApplicationSet{
+		Spec: argoprojv1beta1.ArgoCDSpec{
+			ApplicationSet: &argoprojv1beta1.ArgoCDApplicationSet{
 				Resources: makeTestApplicationSetResources(),
 			},
-			HA: argoprojv1alpha1.ArgoCDHASpec{
+			HA: argoprojv1beta1.ArgoCDHASpec{
 				Resources: makeTestHAResources(),
 			},
-			Dex: argoprojv1alpha1.ArgoCDDexSpec{
+			Dex: argoprojv1beta1.ArgoCDDexSpec{
 				Resources: makeTestDexResources(),
 			},
-			Controller: argoprojv1alpha1.ArgoCDApplicationControllerSpec{
+			Controller: argoprojv1beta1.ArgoCDApplicationControllerSpec{
 				Resources: makeTestControllerResources(),
 			},
 		},
@@ -178,10 +221,10 @@ func assertNoError(t *testing.T, err error) {
 
 func initialCerts(t *testing.T, host string) argoCDOpt {
 	t.Helper()
-	return func(a *argoprojv1alpha1.ArgoCD) {
-		key, err := argoutil.NewPrivateKey()
+	return func(a *argoprojv1beta1.ArgoCD) {
+		key, err := argoutil.NewPrivateKey(common.ArgoCDTLSKeyAlgorithmRSA, 0)
 		assert.NilError(t, err)
-		cert, err := argoutil.NewSelfSignedCACertificate(key)
+		cert, err := argoutil.NewSelfSignedCACertificate(pkix.Name{}, key, common.ArgoCDDefaultCertificateDuration)
 		assert.NilError(t, err)
 		encoded := argoutil.EncodeCertificatePEM(cert)
 
@@ -0,0 +1,439 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	argopass "github.com/argoproj/argo-cd/util/password"
+	jwt "github.com/dgrijalva/jwt-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+// accountToken is an API token entry of a local user account, as stored by Argo CD in the Argo CD Secret.
+type accountToken struct {
+	ID        string `json:"id"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// getAccountKey will return the Argo CD configuration key for the given field of the local user account with the
+// given name, or the key for the capabilities of the account when the field is empty.
+func getAccountKey(name string, field string) string {
+	if len(field) == 0 {
+		return fmt.Sprintf("%s.%s", common.ArgoCDKeyAccounts, name)
+	}
+	return fmt.Sprintf("%s.%s.%s", common.ArgoCDKeyAccounts, name, field)
+}
+
+// getUserSecretName will return the name of the Secret that holds the password and API token of the local user
+// account with the given name.
+func getUserSecretName(name string, cr *argoprojv1b1.ArgoCD) string {
+	return nameWithSuffix(fmt.Sprintf("user-%s", name), cr)
+}
+
+// newUserSecret returns a new Secret for the local user account with the given name for the given ArgoCD.
+func newUserSecret(name string, cr *argoprojv1b1.ArgoCD) *corev1.Secret {
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, getUserSecretName(name, cr))
+	secret.Labels[common.ArgoCDKeyComponent] = common.ArgoCDUserComponent
+	return secret
+}
+
+// getUserCapabilities will return the capabilities of the given local user account in the Argo CD configuration
+// format.
+func getUserCapabilities(user *argoprojv1b1.ArgoCDUserSpec) string {
+	if len(user.Capabilities) == 0 {
+		return common.ArgoCDUserCapabilityAPIKey
+	}
+	return strings.Join(user.Capabilities, ", ")
+}
+
+// isUserEnabled returns true if the given local user account is enabled.
+func isUserEnabled(user *argoprojv1b1.ArgoCDUserSpec) bool {
+	return user.Enabled == nil || *user.Enabled
+}
+
+// getUserTokenExpiresIn will return the validity of the API token for the given local user account, or zero when the
+// token does not expire.
+func getUserTokenExpiresIn(user *argoprojv1b1.ArgoCDUserSpec) time.Duration {
+	if user.Token.ExpiresIn != nil && user.Token.ExpiresIn.Duration > 0 {
+		return user.Token.ExpiresIn.Duration
+	}
+	return 0
+}
+
+// getUserTokenRenewBefore will return how long before expiry the API token for the given local user account is
+// rotated. The default of a third of the validity is used when the configured window is not shorter than the validity.
+func getUserTokenRenewBefore(user *argoprojv1b1.ArgoCDUserSpec) time.Duration {
+	expiresIn := getUserTokenExpiresIn(user)
+	if user.Token.RenewBefore != nil {
+		if d := user.Token.RenewBefore.Duration; d > 0 && d < expiresIn {
+			return d
+		}
+	}
+	return expiresIn / 3
+}
+
+// newUserToken will return a new API token for the local user account with the given name, signed with the given
+// server key the way the Argo CD server signs the tokens it issues, and the token entry to store for the account.
+func newUserToken(name string, key []byte, expiresIn time.Duration) (string, accountToken, error) {
+	now := time.Now().UTC()
+	entry := accountToken{
+		ID:       string(uuid.NewUUID()),
+		IssuedAt: now.Unix(),
+	}
+	if expiresIn > 0 {
+		entry.ExpiresAt = now.Add(expiresIn).Unix()
+	}
+
+	claims := jwt.StandardClaims{
+		ExpiresAt: entry.ExpiresAt,
+		Id:        entry.ID,
+		IssuedAt:  entry.IssuedAt,
+		Issuer:    common.ArgoCDTokenIssuer,
+		NotBefore: entry.IssuedAt,
+		Subject:   fmt.Sprintf("%s:%s", name, common.ArgoCDUserCapabilityAPIKey),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	return signed, entry, err
+}
+
+// parseUserToken will return the claims of the given API token, or an error when the token was not signed with the
+// given server key or has expired.
+func parseUserToken(signed string, key []byte) (*jwt.StandardClaims, error) {
+	claims := &jwt.StandardClaims{}
+	_, err := jwt.ParseWithClaims(signed, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return key, nil
+	})
+	return claims, err
+}
+
+// getUserTokenRotationReason will return why the given API token of the given local user account must be replaced,
+// or an empty string when the token is valid. The token must be signed with the given server key, be one of the given
+// token entries of the account, and match the expiry options of the account.
+func getUserTokenRotationReason(signed string, tokens []accountToken, key []byte, user *argoprojv1b1.ArgoCDUserSpec) string {
+	if len(signed) == 0 {
+		return "the user secret holds no token"
+	}
+
+	claims, err := parseUserToken(signed, key)
+	if err != nil {
+		return fmt.Sprintf("it is not valid: %v", err)
+	}
+
+	found := false
+	for _, token := range tokens {
+		if token.ID == claims.Id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "it was revoked"
+	}
+
+	expiresIn := getUserTokenExpiresIn(user)
+	if expiresIn == 0 {
+		if claims.ExpiresAt != 0 {
+			return "the token options no longer set an expiry"
+		}
+		return ""
+	}
+
+	if claims.ExpiresAt == 0 {
+		return "it does not expire"
+	}
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if time.Until(expiresAt) < getUserTokenRenewBefore(user) {
+		return fmt.Sprintf("it expires at %s", expiresAt.UTC())
+	}
+	return ""
+}
+
+// getUserTokenRenewalRequeue will return the time until the first of the expiring API tokens of the local user
+// accounts of the given ArgoCD is due for renewal, or zero when no token expires.
+func (r *ReconcileArgoCD) getUserTokenRenewalRequeue(cr *argoprojv1b1.ArgoCD) time.Duration {
+	var requeue time.Duration
+	found := false
+	for i := range cr.Spec.Users {
+		user := &cr.Spec.Users[i]
+		if user.Token == nil || getUserTokenExpiresIn(user) == 0 {
+			continue
+		}
+
+		secret := newUserSecret(user.Name, cr)
+		if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+			continue
+		}
+
+		// The signature is verified when the token is reconciled, only its expiry is needed here.
+		claims := &jwt.StandardClaims{}
+		if _, _, err := new(jwt.Parser).ParseUnverified(string(secret.Data[common.ArgoCDKeyUserToken]), claims); err != nil || claims.ExpiresAt == 0 {
+			continue
+		}
+
+		until := time.Until(time.Unix(claims.ExpiresAt, 0).Add(-getUserTokenRenewBefore(user)))
+		if !found || until < requeue {
+			requeue = until
+			found = true
+		}
+	}
+
+	if found && requeue < time.Minute {
+		requeue = time.Minute
+	}
+	return requeue
+}
+
+// getAccountTokens will return the API token entries of the local user account with the given name from the given
+// Argo CD Secret.
+func getAccountTokens(name string, secret *corev1.Secret) ([]accountToken, error) {
+	tokens := make([]accountToken, 0)
+	if data, ok := secret.Data[getAccountKey(name, common.ArgoCDKeyAccountTokens)]; ok && len(data) > 0 {
+		if err := json.Unmarshal(data, &tokens); err != nil {
+			return nil, fmt.Errorf("failed to parse the tokens of account %s: %w", name, err)
+		}
+	}
+	return tokens, nil
+}
+
+// reconcileAccountConfig will ensure that the given ConfigMap holds the capabilities and the enabled setting of the
+// given local user account, and returns true when the ConfigMap was changed.
+func reconcileAccountConfig(cm *corev1.ConfigMap, user *argoprojv1b1.ArgoCDUserSpec) bool {
+	changed := false
+
+	key := getAccountKey(user.Name, "")
+	if capabilities := getUserCapabilities(user); cm.Data[key] != capabilities {
+		cm.Data[key] = capabilities
+		changed = true
+	}
+
+	key = getAccountKey(user.Name, common.ArgoCDKeyAccountEnabled)
+	if isUserEnabled(user) {
+		if _, ok := cm.Data[key]; ok {
+			delete(cm.Data, key)
+			changed = true
+		}
+	} else if cm.Data[key] != "false" {
+		cm.Data[key] = "false"
+		changed = true
+	}
+	return changed
+}
+
+// reconcileUserSecret will ensure that the Secret of the given local user account is present, generating the password
+// and API token when requested, and that the given Argo CD Secret holds the password hash and token entries for the
+// account. A password set in the user Secret is kept, so that it can be set by the user. The password of an account
+// without a password in the user Secret is left as is, so that it can be changed with the Argo CD CLI. Returns true
+// when the Argo CD Secret was changed.
+func (r *ReconcileArgoCD) reconcileUserSecret(cr *argoprojv1b1.ArgoCD, user *argoprojv1b1.ArgoCDUserSpec, argoSecret *corev1.Secret) (bool, error) {
+	secret := newUserSecret(user.Name, cr)
+	found := argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret)
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	userChanged := false
+	changed := false
+
+	if len(secret.Data[common.ArgoCDKeyUserPassword]) == 0 && user.GeneratePassword {
+		password, err := generateArgoAdminPassword()
+		if err != nil {
+			return false, err
+		}
+		secret.Data[common.ArgoCDKeyUserPassword] = password
+		userChanged = true
+	}
+
+	if password := string(secret.Data[common.ArgoCDKeyUserPassword]); len(password) > 0 {
+		key := getAccountKey(user.Name, common.ArgoCDKeyAccountPassword)
+		if valid, _ := argopass.VerifyPassword(password, string(argoSecret.Data[key])); !valid {
+			hashedPassword, err := argopass.HashPassword(password)
+			if err != nil {
+				return false, err
+			}
+			log.Info(fmt.Sprintf("updating the password of account [%s]", user.Name))
+			argoSecret.Data[key] = []byte(hashedPassword)
+			argoSecret.Data[getAccountKey(user.Name, common.ArgoCDKeyAccountPasswordMTime)] = nowBytes()
+			changed = true
+		}
+	}
+
+	if user.Token != nil {
+		tokens, err := getAccountTokens(user.Name, argoSecret)
+		if err != nil {
+			return false, err
+		}
+
+		key := argoSecret.Data[common.ArgoCDKeyServerSecretKey]
+		reason := getUserTokenRotationReason(string(secret.Data[common.ArgoCDKeyUserToken]), tokens, key, user)
+		if len(reason) > 0 {
+			log.Info(fmt.Sprintf("issuing a new token for account [%s], %s", user.Name, reason))
+			signed, entry, err := newUserToken(user.Name, key, getUserTokenExpiresIn(user))
+			if err != nil {
+				return false, err
+			}
+
+			// The previous tokens stay valid until they expire or are deleted with the Argo CD CLI.
+			current := []accountToken{entry}
+			for _, token := range tokens {
+				if token.ExpiresAt == 0 || time.Now().Before(time.Unix(token.ExpiresAt, 0)) {
+					current = append(current, token)
+				}
+			}
+
+			data, err := json.Marshal(current)
+			if err != nil {
+				return false, err
+			}
+			argoSecret.Data[getAccountKey(user.Name, common.ArgoCDKeyAccountTokens)] = data
+			secret.Data[common.ArgoCDKeyUserToken] = []byte(signed)
+			userChanged = true
+			changed = true
+		}
+	}
+
+	if !found {
+		if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+			return false, err
+		}
+		if err := r.client.Create(context.TODO(), secret); err != nil {
+			return false, err
+		}
+	} else if userChanged {
+		if err := r.client.Update(context.TODO(), secret); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// reconcileUsers will ensure that the local user accounts of the given ArgoCD are configured in the Argo CD ConfigMap
+// and Secret, and removes the accounts that are no longer needed. Only the accounts with a user Secret are removed,
+// so that the accounts configured by hand are kept.
+func (r *ReconcileArgoCD) reconcileUsers(cr *argoprojv1b1.ArgoCD) error {
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, cm.Name, cm) {
+		log.Info(fmt.Sprintf("config map [%s] not found, waiting to reconcile users", cm.Name))
+		return nil
+	}
+
+	secret := argoutil.NewSecretWithName(cr.ObjectMeta, common.ArgoCDSecretName)
+	if !argoutil.IsObjectFound(r.client, cr.Namespace, secret.Name, secret) {
+		log.Info(fmt.Sprintf("argo secret [%s] not found, waiting to reconcile users", secret.Name))
+		return nil
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	cmChanged := false
+	secretChanged := false
+	desired := make(map[string]bool)
+
+	for i := range cr.Spec.Users {
+		user := &cr.Spec.Users[i]
+		desired[getUserSecretName(user.Name, cr)] = true
+
+		if reconcileAccountConfig(cm, user) {
+			cmChanged = true
+		}
+
+		changed, err := r.reconcileUserSecret(cr, user, secret)
+		if err != nil {
+			return err
+		}
+		if changed {
+			secretChanged = true
+		}
+	}
+
+	existing := &corev1.SecretList{}
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			common.ArgoCDKeyComponent: common.ArgoCDUserComponent,
+			common.ArgoCDKeyManagedBy: cr.Name,
+		}),
+		Namespace: cr.Namespace,
+	}
+	if err := r.client.List(context.TODO(), existing, opts); err != nil {
+		return err
+	}
+
+	stale := make([]*corev1.Secret, 0)
+	for i := range existing.Items {
+		userSecret := &existing.Items[i]
+		if desired[userSecret.Name] || !metav1.IsControlledBy(userSecret, cr) {
+			continue
+		}
+		stale = append(stale, userSecret)
+
+		name := strings.TrimPrefix(userSecret.Name, getUserSecretName("", cr))
+		log.Info(fmt.Sprintf("deleting account [%s], it is no longer needed", name))
+		for _, field := range []string{"", common.ArgoCDKeyAccountEnabled} {
+			if _, ok := cm.Data[getAccountKey(name, field)]; ok {
+				delete(cm.Data, getAccountKey(name, field))
+				cmChanged = true
+			}
+		}
+		for _, field := range []string{common.ArgoCDKeyAccountPassword, common.ArgoCDKeyAccountPasswordMTime, common.ArgoCDKeyAccountTokens} {
+			if _, ok := secret.Data[getAccountKey(name, field)]; ok {
+				delete(secret.Data, getAccountKey(name, field))
+				secretChanged = true
+			}
+		}
+	}
+
+	if cmChanged {
+		if err := r.client.Update(context.TODO(), cm); err != nil {
+			return err
+		}
+	}
+
+	if secretChanged {
+		if err := r.client.Update(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+
+	// The user Secrets are deleted last, so that the removal of the accounts is retried when an update fails.
+	for _, userSecret := range stale {
+		if err := r.client.Delete(context.TODO(), userSecret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	argopass "github.com/argoproj/argo-cd/util/password"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
+)
+
+var testServerSecretKey = []byte("test-server-secret-key")
+
+func makeTestArgoConfig(a *argoprojv1beta1.ArgoCD) (*corev1.ConfigMap, *corev1.Secret) {
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, a)
+	cm.Data = map[string]string{
+		"accounts.manual": "login",
+	}
+	secret := argoutil.NewSecretWithName(a.ObjectMeta, common.ArgoCDSecretName)
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyServerSecretKey: testServerSecretKey,
+	}
+	return cm, secret
+}
+
+func TestReconcileArgoCD_reconcileUsers(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Users = []argoprojv1beta1.ArgoCDUserSpec{
+			{
+				Name:             "alice",
+				Capabilities:     []string{"login", "apiKey"},
+				GeneratePassword: true,
+			},
+			{
+				Name:    "ci",
+				Enabled: boolPtr(false),
+				Token:   &argoprojv1beta1.ArgoCDUserTokenSpec{ExpiresIn: &metav1.Duration{Duration: 24 * time.Hour}},
+			},
+		}
+	})
+	cm, secret := makeTestArgoConfig(a)
+	r := makeTestReconciler(t, a, cm, secret)
+
+	assert.NilError(t, r.reconcileUsers(a))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDConfigMapName, Namespace: testNamespace}, cm))
+	assert.Equal(t, cm.Data["accounts.alice"], "login, apiKey")
+	assert.Equal(t, cm.Data["accounts.ci"], "apiKey")
+	assert.Equal(t, cm.Data["accounts.ci.enabled"], "false")
+	assert.Equal(t, cm.Data["accounts.manual"], "login")
+
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+
+	alice := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-user-alice", Namespace: testNamespace}, alice))
+	valid, _ := argopass.VerifyPassword(string(alice.Data[common.ArgoCDKeyUserPassword]), string(secret.Data["accounts.alice.password"]))
+	assert.Assert(t, valid)
+	assert.Assert(t, len(secret.Data["accounts.alice.passwordMtime"]) > 0)
+	assert.Assert(t, alice.Data[common.ArgoCDKeyUserToken] == nil)
+
+	ci := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-user-ci", Namespace: testNamespace}, ci))
+	claims, err := parseUserToken(string(ci.Data[common.ArgoCDKeyUserToken]), testServerSecretKey)
+	assert.NilError(t, err)
+	assert.Equal(t, claims.Subject, "ci:apiKey")
+
+	tokens := []accountToken{}
+	assert.NilError(t, json.Unmarshal(secret.Data["accounts.ci.tokens"], &tokens))
+	assert.Equal(t, len(tokens), 1)
+	assert.Equal(t, tokens[0].ID, claims.Id)
+	assert.Equal(t, tokens[0].ExpiresAt, claims.ExpiresAt)
+
+	// The accounts are removed with their users, the accounts configured by hand are kept.
+	a.Spec.Users = a.Spec.Users[:1]
+	assert.NilError(t, r.reconcileUsers(a))
+
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-user-ci", Namespace: testNamespace}, &corev1.Secret{})
+	assert.Assert(t, apierrors.IsNotFound(err))
+
+	cm = &corev1.ConfigMap{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDConfigMapName, Namespace: testNamespace}, cm))
+	_, ok := cm.Data["accounts.ci"]
+	assert.Assert(t, !ok)
+	assert.Equal(t, cm.Data["accounts.manual"], "login")
+
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	_, ok = secret.Data["accounts.ci.tokens"]
+	assert.Assert(t, !ok)
+}
+
+func TestReconcileArgoCD_reconcileUsers_password(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Users = []argoprojv1beta1.ArgoCDUserSpec{{Name: "alice", Capabilities: []string{"login"}}}
+	})
+	cm, secret := makeTestArgoConfig(a)
+	r := makeTestReconciler(t, a, cm, secret)
+
+	// No password is set without a password in the user Secret.
+	assert.NilError(t, r.reconcileUsers(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	_, ok := secret.Data["accounts.alice.password"]
+	assert.Assert(t, !ok)
+
+	// The password set in the user Secret is hashed into the Argo CD Secret.
+	alice := &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: "argocd-user-alice", Namespace: testNamespace}, alice))
+	alice.Data = map[string][]byte{common.ArgoCDKeyUserPassword: []byte("s3cr3t")}
+	assert.NilError(t, r.client.Update(context.TODO(), alice))
+
+	assert.NilError(t, r.reconcileUsers(a))
+	secret = &corev1.Secret{}
+	assert.NilError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDSecretName, Namespace: testNamespace}, secret))
+	valid, _ := argopass.VerifyPassword("s3cr3t", string(secret.Data["accounts.alice.password"]))
+	assert.Assert(t, valid)
+}
+
+func TestReconcileArgoCD_getUserTokenRenewalRequeue(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1beta1.ArgoCD) {
+		a.Spec.Users = []argoprojv1beta1.ArgoCDUserSpec{
+			{Name: "alice", Token: &argoprojv1beta1.ArgoCDUserTokenSpec{}},
+			{Name: "ci", Token: &argoprojv1beta1.ArgoCDUserTokenSpec{ExpiresIn: &metav1.Duration{Duration: time.Hour}}},
+		}
+	})
+	cm, secret := makeTestArgoConfig(a)
+	r := makeTestReconciler(t, a, cm, secret)
+
+	// Nothing is due before the tokens are issued.
+	assert.Equal(t, r.getUserTokenRenewalRequeue(a), time.Duration(0))
+
+	// The token expiring in an hour is renewed a third of its validity before, well ahead of the default sync period
+	// and of the other renewals.
+	assert.NilError(t, r.reconcileUsers(a))
+	a.Status.Certificates = []argoprojv1beta1.ArgoCDCertificateStatus{
+		{NotAfter: metav1.NewTime(time.Now().Add(40 * 24 * time.Hour)), SecretName: "argocd-ca"},
+	}
+	requeue := getRequeueAfter(getCertificateRenewalRequeue(a), r.getUserTokenRenewalRequeue(a))
+	assert.Assert(t, requeue > 39*time.Minute && requeue <= 40*time.Minute, "unexpected requeue %s", requeue)
+}
+
+func TestGetUserTokenRotationReason(t *testing.T) {
+	user := &argoprojv1beta1.ArgoCDUserSpec{
+		Name:  "ci",
+		Token: &argoprojv1beta1.ArgoCDUserTokenSpec{ExpiresIn: &metav1.Duration{Duration: 3 * time.Hour}},
+	}
+
+	signed, entry, err := newUserToken(user.Name, testServerSecretKey, 3*time.Hour)
+	assert.NilError(t, err)
+	tokens := []accountToken{entry}
+
+	assert.Equal(t, getUserTokenRotationReason(signed, tokens, testServerSecretKey, user), "")
+	assert.Equal(t, getUserTokenRotationReason("", tokens, testServerSecretKey, user), "the user secret holds no token")
+	assert.Equal(t, getUserTokenRotationReason(signed, []accountToken{}, testServerSecretKey, user), "it was revoked")
+	assert.Assert(t, getUserTokenRotationReason(signed, tokens, []byte("rotated"), user) != "")
+
+	// The token is rotated within the renewal window, a third of the validity by default.
+	user.Token.RenewBefore = &metav1.Duration{Duration: 4 * time.Hour}
+	assert.Equal(t, getUserTokenRenewBefore(user), time.Hour)
+	user.Token.RenewBefore = &metav1.Duration{Duration: 170 * time.Minute}
+	assert.Assert(t, getUserTokenRotationReason(signed, tokens, testServerSecretKey, user) != "")
+
+	user.Token.ExpiresIn = nil
+	assert.Equal(t, getUserTokenRotationReason(signed, tokens, testServerSecretKey, user), "the token options no longer set an expiry")
+}
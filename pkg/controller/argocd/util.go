@@ -17,6 +17,7 @@ package argocd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -25,40 +26,37 @@ import (
 	"strings"
 	"text/template"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/sethvargo/go-password/password"
-	"gopkg.in/yaml.v2"
 
 	routev1 "github.com/openshift/api/route/v1"
+	userv1 "github.com/openshift/api/user/v1"
 
 	oappsv1 "github.com/openshift/api/apps/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-// DexConnector represents an authentication connector for Dex.
-type DexConnector struct {
-	Config map[string]interface{} `yaml:"config,omitempty"`
-	ID     string                 `yaml:"id"`
-	Name   string                 `yaml:"name"`
-	Type   string                 `yaml:"type"`
-}
-
 // generateArgoAdminPassword will generate and return the admin password for Argo CD.
 func generateArgoAdminPassword() ([]byte, error) {
 	pass, err := password.Generate(
@@ -82,7 +80,7 @@ func generateArgoServerSessionKey() ([]byte, error) {
 }
 
 // getArgoApplicationControllerResources will return the ResourceRequirements for the Argo CD application controller container.
-func getArgoApplicationControllerResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getArgoApplicationControllerResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	// Allow override of resource requirements from CR
@@ -94,22 +92,27 @@ func getArgoApplicationControllerResources(cr *argoprojv1a1.ArgoCD) corev1.Resou
 }
 
 // getArgoApplicationControllerCommand will return the command for the ArgoCD Application Controller component.
-func getArgoApplicationControllerCommand(cr *argoprojv1a1.ArgoCD) []string {
+func getArgoApplicationControllerCommand(cr *argoprojv1b1.ArgoCD) []string {
 	cmd := []string{
 		"argocd-application-controller",
 		"--operation-processors", fmt.Sprint(getArgoServerOperationProcessors(cr)),
-		"--redis", getRedisServerAddress(cr),
-		"--repo-server", getRepoServerAddress(cr),
-		"--status-processors", fmt.Sprint(getArgoServerStatusProcessors(cr)),
 	}
+	cmd = append(cmd, getRedisServerArgs(cr)...)
+	cmd = append(cmd,
+		"--repo-server", getRepoServerAddress(cr),
+		"--status-processors", fmt.Sprint(getArgoServerStatusProcessors(cr)))
+	cmd = append(cmd, getRedisClientArgs(cr)...)
 	if cr.Spec.Controller.AppSync != nil {
 		cmd = append(cmd, "--app-resync", strconv.FormatInt(int64(cr.Spec.Controller.AppSync.Seconds()), 10))
 	}
+	if isRepoServerTLSVerificationRequested(cr) {
+		cmd = append(cmd, "--repo-server-strict-tls")
+	}
 	return cmd
 }
 
 // getArgoContainerImage will return the container image for ArgoCD.
-func getArgoContainerImage(cr *argoprojv1a1.ArgoCD) string {
+func getArgoContainerImage(cr *argoprojv1b1.ArgoCD) string {
 	defaultTag, defaultImg := false, false
 	img := cr.Spec.Image
 	if img == "" {
@@ -130,7 +133,7 @@ func getArgoContainerImage(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getArgoRepoResources will return the ResourceRequirements for the Argo CD Repo server container.
-func getArgoRepoResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getArgoRepoResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	// Allow override of resource requirements from CR
@@ -142,16 +145,18 @@ func getArgoRepoResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 }
 
 // getArgoServerInsecure returns the insecure value for the ArgoCD Server component.
-func getArgoServerInsecure(cr *argoprojv1a1.ArgoCD) bool {
+func getArgoServerInsecure(cr *argoprojv1b1.ArgoCD) bool {
 	return cr.Spec.Server.Insecure
 }
 
-func isRepoServerTLSVerificationRequested(cr *argoprojv1a1.ArgoCD) bool {
-	return cr.Spec.Repo.VerifyTLS
+// isRepoServerTLSVerificationRequested returns true if the clients of the repo server should verify its certificate
+// strictly, which is always the case when the certificate is issued by the operator CA.
+func isRepoServerTLSVerificationRequested(cr *argoprojv1b1.ArgoCD) bool {
+	return cr.Spec.Repo.VerifyTLS || cr.Spec.Repo.AutoTLS == common.ArgoCDRepoServerAutoTLSOperator
 }
 
 // getArgoServerGRPCHost will return the GRPC host for the given ArgoCD.
-func getArgoServerGRPCHost(cr *argoprojv1a1.ArgoCD) string {
+func getArgoServerGRPCHost(cr *argoprojv1b1.ArgoCD) string {
 	host := nameWithSuffix("grpc", cr)
 	if len(cr.Spec.Server.GRPC.Host) > 0 {
 		host = cr.Spec.Server.GRPC.Host
@@ -160,7 +165,7 @@ func getArgoServerGRPCHost(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getArgoServerHost will return the host for the given ArgoCD.
-func getArgoServerHost(cr *argoprojv1a1.ArgoCD) string {
+func getArgoServerHost(cr *argoprojv1b1.ArgoCD) string {
 	host := cr.Name
 	if len(cr.Spec.Server.Host) > 0 {
 		host = cr.Spec.Server.Host
@@ -169,7 +174,7 @@ func getArgoServerHost(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getArgoServerResources will return the ResourceRequirements for the Argo CD server container.
-func getArgoServerResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getArgoServerResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	if cr.Spec.Server.Autoscale.Enabled {
@@ -195,7 +200,7 @@ func getArgoServerResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements
 
 // getArgoServerURI will return the URI for the ArgoCD server.
 // The hostname for argocd-server is from the route, ingress, an external hostname or service name in that order.
-func (r *ReconcileArgoCD) getArgoServerURI(cr *argoprojv1a1.ArgoCD) string {
+func (r *ReconcileArgoCD) getArgoServerURI(cr *argoprojv1b1.ArgoCD) string {
 	host := nameWithSuffix("server", cr) // Default to service name
 
 	// Use the external hostname provided by the user
@@ -223,7 +228,7 @@ func (r *ReconcileArgoCD) getArgoServerURI(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getArgoServerOperationProcessors will return the numeric Operation Processors value for the ArgoCD Server.
-func getArgoServerOperationProcessors(cr *argoprojv1a1.ArgoCD) int32 {
+func getArgoServerOperationProcessors(cr *argoprojv1b1.ArgoCD) int32 {
 	op := common.ArgoCDDefaultServerOperationProcessors
 	if cr.Spec.Controller.Processors.Operation > op {
 		op = cr.Spec.Controller.Processors.Operation
@@ -232,7 +237,7 @@ func getArgoServerOperationProcessors(cr *argoprojv1a1.ArgoCD) int32 {
 }
 
 // getArgoServerStatusProcessors will return the numeric Status Processors value for the ArgoCD Server.
-func getArgoServerStatusProcessors(cr *argoprojv1a1.ArgoCD) int32 {
+func getArgoServerStatusProcessors(cr *argoprojv1b1.ArgoCD) int32 {
 	sp := common.ArgoCDDefaultServerStatusProcessors
 	if cr.Spec.Controller.Processors.Status > sp {
 		sp = cr.Spec.Controller.Processors.Status
@@ -251,7 +256,7 @@ func getArgoServerStatusProcessors(cr *argoprojv1a1.ArgoCD) int32 {
 // that if the spec is not configured.
 // 3. the default is configured in common.ArgoCDDefaultDexVersion and
 // common.ArgoCDDefaultDexImage.
-func getDexContainerImage(cr *argoprojv1a1.ArgoCD) string {
+func getDexContainerImage(cr *argoprojv1b1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 	img := cr.Spec.Dex.Image
 	if img == "" {
@@ -271,12 +276,12 @@ func getDexContainerImage(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getDexOAuthClientID will return the OAuth client ID for the given ArgoCD.
-func getDexOAuthClientID(cr *argoprojv1a1.ArgoCD) string {
+func getDexOAuthClientID(cr *argoprojv1b1.ArgoCD) string {
 	return fmt.Sprintf("system:serviceaccount:%s:%s", cr.Namespace, fmt.Sprintf("%s-%s", cr.Name, common.ArgoCDDefaultDexServiceAccountName))
 }
 
 // getDexOAuthClientSecret will return the OAuth client secret for the given ArgoCD.
-func (r *ReconcileArgoCD) getDexOAuthClientSecret(cr *argoprojv1a1.ArgoCD) (*string, error) {
+func (r *ReconcileArgoCD) getDexOAuthClientSecret(cr *argoprojv1b1.ArgoCD) (*string, error) {
 	sa := newServiceAccountWithName(common.ArgoCDDefaultDexServiceAccountName, cr)
 	if err := argoutil.FetchObject(r.client, cr.Namespace, sa.Name, sa); err != nil {
 		return nil, err
@@ -306,7 +311,7 @@ func (r *ReconcileArgoCD) getDexOAuthClientSecret(cr *argoprojv1a1.ArgoCD) (*str
 }
 
 // getDexResources will return the ResourceRequirements for the Dex container.
-func getDexResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getDexResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	// Allow override of resource requirements from CR
@@ -318,7 +323,7 @@ func getDexResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 }
 
 // getGrafanaContainerImage will return the container image for the Grafana server.
-func getGrafanaContainerImage(cr *argoprojv1a1.ArgoCD) string {
+func getGrafanaContainerImage(cr *argoprojv1b1.ArgoCD) string {
 	defaultTag, defaultImg := false, false
 	img := cr.Spec.Grafana.Image
 	if img == "" {
@@ -338,7 +343,7 @@ func getGrafanaContainerImage(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getGrafanaResources will return the ResourceRequirements for the Grafana container.
-func getGrafanaResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getGrafanaResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	// Allow override of resource requirements from CR
@@ -349,34 +354,35 @@ func getGrafanaResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 	return resources
 }
 
-// getOpenShiftDexConfig will return the configuration for the Dex server running on OpenShift.
-func (r *ReconcileArgoCD) getOpenShiftDexConfig(cr *argoprojv1a1.ArgoCD) (string, error) {
+// getOpenShiftDexConnector will return the Dex connector for the OAuth server of the OpenShift cluster.
+func (r *ReconcileArgoCD) getOpenShiftDexConnector(cr *argoprojv1b1.ArgoCD) (*argoprojv1b1.ArgoCDDexConnector, error) {
 	clientSecret, err := r.getDexOAuthClientSecret(cr)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	connector := DexConnector{
-		Type: "openshift",
-		ID:   "openshift",
-		Name: "OpenShift",
-		Config: map[string]interface{}{
-			"issuer":       "https://kubernetes.default.svc", // TODO: Should this be hard-coded?
-			"clientID":     getDexOAuthClientID(cr),
-			"clientSecret": *clientSecret,
-			"redirectURI":  r.getDexOAuthRedirectURI(cr),
-			"insecureCA":   true, // TODO: Configure for openshift CA
-		},
+	issuer, err := r.getOpenShiftDexIssuer(cr)
+	if err != nil {
+		return nil, err
 	}
 
-	connectors := make([]DexConnector, 0)
-	connectors = append(connectors, connector)
-
-	dex := make(map[string]interface{})
-	dex["connectors"] = connectors
+	config, err := json.Marshal(map[string]interface{}{
+		"issuer":       issuer,
+		"clientID":     getDexOAuthClientID(cr),
+		"clientSecret": *clientSecret,
+		"redirectURI":  r.getDexOAuthRedirectURI(cr),
+		"rootCA":       getOpenShiftDexRootCA(cr),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	bytes, err := yaml.Marshal(dex)
-	return string(bytes), err
+	return &argoprojv1b1.ArgoCDDexConnector{
+		Config: &runtime.RawExtension{Raw: config},
+		ID:     common.ArgoCDDexOpenShiftConnectorID,
+		Name:   "OpenShift",
+		Type:   "openshift",
+	}, nil
 }
 
 // getRedisConfigPath will return the path for the Redis configuration templates.
@@ -390,9 +396,9 @@ func getRedisConfigPath() string {
 
 // getRedisInitScript will load the redis configuration from a template on disk for the given ArgoCD.
 // If an error occurs, an empty string value will be returned.
-func getRedisConf(cr *argoprojv1a1.ArgoCD) string {
+func getRedisConf(cr *argoprojv1b1.ArgoCD) string {
 	path := fmt.Sprintf("%s/redis.conf.tpl", getRedisConfigPath())
-	conf, err := loadTemplateFile(path, map[string]string{})
+	conf, err := loadTemplateFile(path, getRedisHATemplateVars(cr))
 	if err != nil {
 		log.Error(err, "unable to load redis configuration")
 		return ""
@@ -401,7 +407,7 @@ func getRedisConf(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getRedisContainerImage will return the container image for the Redis server.
-func getRedisContainerImage(cr *argoprojv1a1.ArgoCD) string {
+func getRedisContainerImage(cr *argoprojv1b1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 	img := cr.Spec.Redis.Image
 	if img == "" {
@@ -420,7 +426,7 @@ func getRedisContainerImage(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getRedisHAContainerImage will return the container image for the Redis server in HA mode.
-func getRedisHAContainerImage(cr *argoprojv1a1.ArgoCD) string {
+func getRedisHAContainerImage(cr *argoprojv1b1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 	img := cr.Spec.Redis.Image
 	if img == "" {
@@ -439,12 +445,12 @@ func getRedisHAContainerImage(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getRedisHAProxyAddress will return the Redis HA Proxy service address for the given ArgoCD.
-func getRedisHAProxyAddress(cr *argoprojv1a1.ArgoCD) string {
+func getRedisHAProxyAddress(cr *argoprojv1b1.ArgoCD) string {
 	return fqdnServiceRef("redis-ha-haproxy", common.ArgoCDDefaultRedisPort, cr)
 }
 
 // getRedisHAProxyContainerImage will return the container image for the Redis HA Proxy.
-func getRedisHAProxyContainerImage(cr *argoprojv1a1.ArgoCD) string {
+func getRedisHAProxyContainerImage(cr *argoprojv1b1.ArgoCD) string {
 	defaultImg, defaultTag := false, false
 	img := cr.Spec.HA.RedisProxyImage
 	if len(img) <= 0 {
@@ -467,11 +473,9 @@ func getRedisHAProxyContainerImage(cr *argoprojv1a1.ArgoCD) string {
 
 // getRedisInitScript will load the redis init script from a template on disk for the given ArgoCD.
 // If an error occurs, an empty string value will be returned.
-func getRedisInitScript(cr *argoprojv1a1.ArgoCD) string {
+func getRedisInitScript(cr *argoprojv1b1.ArgoCD) string {
 	path := fmt.Sprintf("%s/init.sh.tpl", getRedisConfigPath())
-	vars := map[string]string{
-		"ServiceName": nameWithSuffix("redis-ha", cr),
-	}
+	vars := getRedisHATemplateVars(cr)
 
 	script, err := loadTemplateFile(path, vars)
 	if err != nil {
@@ -483,11 +487,9 @@ func getRedisInitScript(cr *argoprojv1a1.ArgoCD) string {
 
 // getRedisHAProxySConfig will load the Redis HA Proxy configuration from a template on disk for the given ArgoCD.
 // If an error occurs, an empty string value will be returned.
-func getRedisHAProxyConfig(cr *argoprojv1a1.ArgoCD) string {
+func getRedisHAProxyConfig(cr *argoprojv1b1.ArgoCD) string {
 	path := fmt.Sprintf("%s/haproxy.cfg.tpl", getRedisConfigPath())
-	vars := map[string]string{
-		"ServiceName": nameWithSuffix("redis-ha", cr),
-	}
+	vars := getRedisHATemplateVars(cr)
 
 	script, err := loadTemplateFile(path, vars)
 	if err != nil {
@@ -499,11 +501,9 @@ func getRedisHAProxyConfig(cr *argoprojv1a1.ArgoCD) string {
 
 // getRedisHAProxyScript will load the Redis HA Proxy init script from a template on disk for the given ArgoCD.
 // If an error occurs, an empty string value will be returned.
-func getRedisHAProxyScript(cr *argoprojv1a1.ArgoCD) string {
+func getRedisHAProxyScript(cr *argoprojv1b1.ArgoCD) string {
 	path := fmt.Sprintf("%s/haproxy_init.sh.tpl", getRedisConfigPath())
-	vars := map[string]string{
-		"ServiceName": nameWithSuffix("redis-ha", cr),
-	}
+	vars := getRedisHATemplateVars(cr)
 
 	script, err := loadTemplateFile(path, vars)
 	if err != nil {
@@ -514,7 +514,7 @@ func getRedisHAProxyScript(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getRedisResources will return the ResourceRequirements for the Redis container.
-func getRedisResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getRedisResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	// Allow override of resource requirements from CR
@@ -526,7 +526,7 @@ func getRedisResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 }
 
 // getRedisHAProxyResources will return the ResourceRequirements for the Redis HA Proxy.
-func getRedisHAProxyResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
+func getRedisHAProxyResources(cr *argoprojv1b1.ArgoCD) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{}
 
 	// Allow override of resource requirements from CR
@@ -539,9 +539,9 @@ func getRedisHAProxyResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequiremen
 
 // getRedisSentinelConf will load the redis sentinel configuration from a template on disk for the given ArgoCD.
 // If an error occurs, an empty string value will be returned.
-func getRedisSentinelConf(cr *argoprojv1a1.ArgoCD) string {
+func getRedisSentinelConf(cr *argoprojv1b1.ArgoCD) string {
 	path := fmt.Sprintf("%s/sentinel.conf.tpl", getRedisConfigPath())
-	conf, err := loadTemplateFile(path, map[string]string{})
+	conf, err := loadTemplateFile(path, getRedisHATemplateVars(cr))
 	if err != nil {
 		log.Error(err, "unable to load redis sentinel configuration")
 		return ""
@@ -550,7 +550,10 @@ func getRedisSentinelConf(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // getRedisServerAddress will return the Redis service address for the given ArgoCD.
-func getRedisServerAddress(cr *argoprojv1a1.ArgoCD) string {
+func getRedisServerAddress(cr *argoprojv1b1.ArgoCD) string {
+	if isRedisExternal(cr) {
+		return getRedisExternalAddress(cr)
+	}
 	if cr.Spec.HA.Enabled {
 		return getRedisHAProxyAddress(cr)
 	}
@@ -558,7 +561,7 @@ func getRedisServerAddress(cr *argoprojv1a1.ArgoCD) string {
 }
 
 // loadTemplateFile will parse a template with the given path and execute it with the given params.
-func loadTemplateFile(path string, params map[string]string) (string, error) {
+func loadTemplateFile(path string, params map[string]interface{}) (string, error) {
 	tmpl, err := template.ParseFiles(path)
 	if err != nil {
 		log.Error(err, "unable to parse template")
@@ -577,18 +580,18 @@ func loadTemplateFile(path string, params map[string]string) (string, error) {
 // nameWithSuffix will return a name based on the given ArgoCD. The given suffix is appended to the generated name.
 // Example: Given an ArgoCD with the name "example-argocd", providing the suffix "foo" would result in the value of
 // "example-argocd-foo" being returned.
-func nameWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) string {
+func nameWithSuffix(suffix string, cr *argoprojv1b1.ArgoCD) string {
 	return fmt.Sprintf("%s-%s", cr.Name, suffix)
 }
 
 // fqdnServiceRef will return the FQDN referencing a specific service name, as set up by the operator, with the
 // given port.
-func fqdnServiceRef(service string, port int, cr *argoprojv1a1.ArgoCD) string {
+func fqdnServiceRef(service string, port int, cr *argoprojv1b1.ArgoCD) string {
 	return fmt.Sprintf("%s.%s.svc.cluster.local:%d", nameWithSuffix(service, cr), cr.Namespace, port)
 }
 
-// InspectCluster will verify the availability of extra features available to the cluster, such as Prometheus and
-// OpenShift Routes.
+// InspectCluster will verify the availability of extra features available to the cluster, such as Prometheus,
+// OpenShift Routes, cert-manager, the OpenShift cluster configuration and the OpenShift user API.
 func InspectCluster() error {
 	if err := verifyPrometheusAPI(); err != nil {
 		return err
@@ -601,11 +604,23 @@ func InspectCluster() error {
 	if err := verifyTemplateAPI(); err != nil {
 		return err
 	}
+
+	if err := verifyCertManagerAPI(); err != nil {
+		return err
+	}
+
+	if err := verifyOpenShiftConfigAPI(); err != nil {
+		return err
+	}
+
+	if err := verifyUserAPI(); err != nil {
+		return err
+	}
 	return nil
 }
 
 // reconcileCertificateAuthority will reconcile all Certificate Authority resources.
-func (r *ReconcileArgoCD) reconcileCertificateAuthority(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) reconcileCertificateAuthority(cr *argoprojv1b1.ArgoCD) error {
 	log.Info("reconciling CA secret")
 	if err := r.reconcileClusterCASecret(cr); err != nil {
 		return err
@@ -619,115 +634,32 @@ func (r *ReconcileArgoCD) reconcileCertificateAuthority(cr *argoprojv1a1.ArgoCD)
 }
 
 // reconcileResources will reconcile common ArgoCD resources.
-func (r *ReconcileArgoCD) reconcileResources(cr *argoprojv1a1.ArgoCD) error {
-	log.Info("reconciling status")
-	if err := r.reconcileStatus(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling roles")
-	if _, err := r.reconcileRoles(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling rolebindings")
-	if err := r.reconcileRoleBindings(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling service accounts")
-	if err := r.reconcileServiceAccounts(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling certificate authority")
-	if err := r.reconcileCertificateAuthority(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling secrets")
-	if err := r.reconcileSecrets(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling config maps")
-	if err := r.reconcileConfigMaps(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling services")
-	if err := r.reconcileServices(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling deployments")
-	if err := r.reconcileDeployments(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling statefulsets")
-	if err := r.reconcileStatefulSets(cr); err != nil {
-		return err
-	}
-
-	log.Info("reconciling autoscalers")
-	if err := r.reconcileAutoscalers(cr); err != nil {
-		return err
-	}
+func (r *ReconcileArgoCD) reconcileResources(cr *argoprojv1b1.ArgoCD) error {
+	return r.reconcileComponents(cr, argoCDComponents)
+}
 
-	log.Info("reconciling ingresses")
-	if err := r.reconcileIngresses(cr); err != nil {
+// applyObject will set the given ArgoCD as the controller of the object and apply the desired state of the object.
+// The pod overrides for the component are merged into the pod template of Deployments and StatefulSets.
+func (r *ReconcileArgoCD) applyObject(cr *argoprojv1b1.ArgoCD, obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
 		return err
 	}
 
-	if IsRouteAPIAvailable() {
-		log.Info("reconciling routes")
-		if err := r.reconcileRoutes(cr); err != nil {
-			return err
-		}
-	}
-
-	if IsPrometheusAPIAvailable() {
-		log.Info("reconciling prometheus")
-		if err := r.reconcilePrometheus(cr); err != nil {
-			return err
-		}
-
-		if err := r.reconcileMetricsServiceMonitor(cr); err != nil {
-			return err
-		}
-
-		if err := r.reconcileRepoServerServiceMonitor(cr); err != nil {
-			return err
-		}
-
-		if err := r.reconcileServerMetricsServiceMonitor(cr); err != nil {
-			return err
-		}
-	}
-
-	if cr.Spec.ApplicationSet != nil {
-		log.Info("reconciling ApplicationSet controller")
-		if err := r.reconcileApplicationSetController(cr); err != nil {
-			return err
-		}
-	}
-
-	if err := r.reconcileRepoServerTLSSecret(cr); err != nil {
+	if err := controllerutil.SetControllerReference(cr, accessor, r.scheme); err != nil {
 		return err
 	}
 
-	if cr.Spec.SSO != nil {
-		log.Info("reconciling SSO")
-		if err := r.reconcileSSO(cr); err != nil {
-			return err
-		}
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		applyPodOverrides(&o.Spec.Template, getPodOverrides(cr, o.Labels[common.ArgoCDKeyComponent]))
+	case *appsv1.StatefulSet:
+		applyPodOverrides(&o.Spec.Template, getPodOverrides(cr, o.Labels[common.ArgoCDKeyComponent]))
 	}
-
-	return nil
+	return argoutil.ApplyObject(r.client, r.scheme, obj)
 }
 
-func (r *ReconcileArgoCD) deleteClusterResources(cr *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) deleteClusterResources(cr *argoprojv1b1.ArgoCD) error {
 	selector, err := argocdInstanceSelector(cr.Name)
 	if err != nil {
 		return err
@@ -767,7 +699,7 @@ func argocdInstanceSelector(name string) (labels.Selector, error) {
 	return selector.Add(*requirement), nil
 }
 
-func (r *ReconcileArgoCD) removeDeletionFinalizer(argocd *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) removeDeletionFinalizer(argocd *argoprojv1b1.ArgoCD) error {
 	argocd.Finalizers = removeString(argocd.GetFinalizers(), common.ArgoCDDeletionFinalizer)
 	if err := r.client.Update(context.TODO(), argocd); err != nil {
 		return fmt.Errorf("failed to remove deletion finalizer from %s: %w", argocd.Name, err)
@@ -775,7 +707,7 @@ func (r *ReconcileArgoCD) removeDeletionFinalizer(argocd *argoprojv1a1.ArgoCD) e
 	return nil
 }
 
-func (r *ReconcileArgoCD) addDeletionFinalizer(argocd *argoprojv1a1.ArgoCD) error {
+func (r *ReconcileArgoCD) addDeletionFinalizer(argocd *argoprojv1b1.ArgoCD) error {
 	argocd.Finalizers = append(argocd.Finalizers, common.ArgoCDDeletionFinalizer)
 	if err := r.client.Update(context.TODO(), argocd); err != nil {
 		return fmt.Errorf("failed to add deletion finalizer for %s: %w", argocd.Name, err)
@@ -795,7 +727,7 @@ func removeString(slice []string, s string) []string {
 }
 
 // labelsForCluster returns the labels for all cluster resources.
-func labelsForCluster(cr *argoprojv1a1.ArgoCD) map[string]string {
+func labelsForCluster(cr *argoprojv1b1.ArgoCD) map[string]string {
 	labels := argoutil.DefaultLabels(cr.Name)
 	for key, val := range cr.ObjectMeta.Labels {
 		labels[key] = val
@@ -804,7 +736,7 @@ func labelsForCluster(cr *argoprojv1a1.ArgoCD) map[string]string {
 }
 
 // annotationsForCluster returns the annotations for all cluster resources.
-func annotationsForCluster(cr *argoprojv1a1.ArgoCD) map[string]string {
+func annotationsForCluster(cr *argoprojv1b1.ArgoCD) map[string]string {
 	annotations := argoutil.DefaultAnnotations(cr)
 	for key, val := range cr.ObjectMeta.Annotations {
 		annotations[key] = val
@@ -813,7 +745,7 @@ func annotationsForCluster(cr *argoprojv1a1.ArgoCD) map[string]string {
 }
 
 // watchResources will register Watches for each of the supported Resources.
-func watchResources(c controller.Controller, clusterResourceMapper handler.ToRequestsFunc, tlsSecretMapper handler.ToRequestsFunc) error {
+func watchResources(c controller.Controller, clusterResourceMapper handler.ToRequestsFunc, tlsSecretMapper handler.ToRequestsFunc, secretReferenceMapper handler.ToRequestsFunc, rbacPolicyConfigMapMapper handler.ToRequestsFunc, groupMappingMapper handler.ToRequestsFunc) error {
 
 	deploymentConfigPred := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -849,11 +781,11 @@ func watchResources(c controller.Controller, clusterResourceMapper handler.ToReq
 
 	deleteSSOPred := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			newCR, ok := e.ObjectNew.(*argoprojv1a1.ArgoCD)
+			newCR, ok := e.ObjectNew.(*argoprojv1b1.ArgoCD)
 			if !ok {
 				return false
 			}
-			oldCR, ok := e.ObjectOld.(*argoprojv1a1.ArgoCD)
+			oldCR, ok := e.ObjectOld.(*argoprojv1b1.ArgoCD)
 			if !ok {
 				return false
 			}
@@ -869,7 +801,7 @@ func watchResources(c controller.Controller, clusterResourceMapper handler.ToReq
 	}
 
 	// Watch for changes to primary resource ArgoCD
-	if err := c.Watch(&source.Kind{Type: &argoprojv1a1.ArgoCD{}}, &handler.EnqueueRequestForObject{}, deleteSSOPred); err != nil {
+	if err := c.Watch(&source.Kind{Type: &argoprojv1b1.ArgoCD{}}, &handler.EnqueueRequestForObject{}, deleteSSOPred); err != nil {
 		return err
 	}
 
@@ -914,6 +846,14 @@ func watchResources(c controller.Controller, clusterResourceMapper handler.ToReq
 		ToRequests: tlsSecretMapper,
 	}
 
+	secretReferenceHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: secretReferenceMapper,
+	}
+
+	rbacPolicyConfigMapHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: rbacPolicyConfigMapMapper,
+	}
+
 	if err := c.Watch(&source.Kind{Type: &v1.ClusterRoleBinding{}}, clusterResourceHandler); err != nil {
 		return err
 	}
@@ -927,11 +867,31 @@ func watchResources(c controller.Controller, clusterResourceMapper handler.ToReq
 		return err
 	}
 
+	// Watch for the secrets referenced by an ArgoCD
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, secretReferenceHandler); err != nil {
+		return err
+	}
+
+	// Watch for the configmaps holding RBAC policy fragments selected by an ArgoCD
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, rbacPolicyConfigMapHandler); err != nil {
+		return err
+	}
+
 	// Watch for changes to Secret sub-resources owned by ArgoCD instances.
 	if err := watchOwnedResource(c, &appsv1.StatefulSet{}); err != nil {
 		return err
 	}
 
+	// Watch for changes to PodDisruptionBudget sub-resources owned by ArgoCD instances.
+	if err := watchOwnedResource(c, &policyv1beta1.PodDisruptionBudget{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to NetworkPolicy sub-resources owned by ArgoCD instances.
+	if err := watchOwnedResource(c, &networkingv1.NetworkPolicy{}); err != nil {
+		return err
+	}
+
 	// Inspect cluster to verify availability of extra features
 	// This sets the flags that are used in subsequent checks
 	if err := InspectCluster(); err != nil {
@@ -957,11 +917,29 @@ func watchResources(c controller.Controller, clusterResourceMapper handler.ToReq
 		}
 	}
 
+	if IsCertManagerAPIAvailable() {
+		// Watch cert-manager Certificate sub-resources owned by ArgoCD instances, their status changes on renewal.
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(certificateGVK)
+		if err := watchOwnedResource(c, cert); err != nil {
+			return err
+		}
+	}
+
+	if IsUserAPIAvailable() {
+		// Watch for the OpenShift Groups mapped to RBAC roles by an ArgoCD
+		if err := c.Watch(&source.Kind{Type: &userv1.Group{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: groupMappingMapper,
+		}); err != nil {
+			return err
+		}
+	}
+
 	if IsTemplateAPIAvailable() {
 		// Watch for the changes to Deployment Config
 		if err := c.Watch(&source.Kind{Type: &oappsv1.DeploymentConfig{}}, &handler.EnqueueRequestForOwner{
 			IsController: true,
-			OwnerType:    &argoprojv1a1.ArgoCD{},
+			OwnerType:    &argoprojv1b1.ArgoCD{},
 		},
 			deploymentConfigPred); err != nil {
 			return err
@@ -974,12 +952,12 @@ func watchResources(c controller.Controller, clusterResourceMapper handler.ToReq
 func watchOwnedResource(c controller.Controller, obj runtime.Object) error {
 	return c.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &argoprojv1a1.ArgoCD{},
+		OwnerType:    &argoprojv1b1.ArgoCD{},
 	})
 }
 
 // withClusterLabels will add the given labels to the labels for the cluster and return the result.
-func withClusterLabels(cr *argoprojv1a1.ArgoCD, addLabels map[string]string) map[string]string {
+func withClusterLabels(cr *argoprojv1b1.ArgoCD, addLabels map[string]string) map[string]string {
 	labels := labelsForCluster(cr)
 	for key, val := range addLabels {
 		labels[key] = val
@@ -6,7 +6,7 @@ import (
 	"testing"
 	"time"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argoutil"
 	"gotest.tools/assert"
@@ -26,7 +26,7 @@ var imageTests = []struct {
 	pre       func(t *testing.T)
 	opts      []argoCDOpt
 	want      string
-	imageFunc func(a *argoprojv1alpha1.ArgoCD) string
+	imageFunc func(a *argoprojv1beta1.ArgoCD) string
 }{
 	{
 		name:      "dex default configuration",
@@ -37,7 +37,7 @@ var imageTests = []struct {
 		name:      "dex spec configuration",
 		imageFunc: getDexContainerImage,
 		want:      dexTestImage,
-		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+		opts: []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
 			a.Spec.Dex.Image = "testing/dex"
 			a.Spec.Dex.Version = "latest"
 		}},
@@ -62,7 +62,7 @@ var imageTests = []struct {
 	{
 		name:      "argo spec configuration",
 		imageFunc: getArgoContainerImage,
-		want:      argoTestImage, opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+		want:      argoTestImage, opts: []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
 			a.Spec.Image = "testing/argocd"
 			a.Spec.Version = "latest"
 		}},
@@ -88,7 +88,7 @@ var imageTests = []struct {
 		name:      "grafana spec configuration",
 		imageFunc: getGrafanaContainerImage,
 		want:      grafanaTestImage,
-		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+		opts: []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
 			a.Spec.Grafana.Image = "testing/grafana"
 			a.Spec.Grafana.Version = "latest"
 		}},
@@ -114,7 +114,7 @@ var imageTests = []struct {
 		name:      "redis spec configuration",
 		imageFunc: getRedisContainerImage,
 		want:      redisTestImage,
-		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+		opts: []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
 			a.Spec.Redis.Image = "testing/redis"
 			a.Spec.Redis.Version = "latest"
 		}},
@@ -142,7 +142,7 @@ var imageTests = []struct {
 		name:      "redis ha spec configuration",
 		imageFunc: getRedisHAContainerImage,
 		want:      redisHATestImage,
-		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+		opts: []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
 			a.Spec.Redis.Image = "testing/redis"
 			a.Spec.Redis.Version = "latest-ha"
 		}},
@@ -170,7 +170,7 @@ var imageTests = []struct {
 		name:      "redis ha proxy spec configuration",
 		imageFunc: getRedisHAProxyContainerImage,
 		want:      redisHAProxyTestImage,
-		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+		opts: []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
 			a.Spec.HA.RedisProxyImage = "testing/redis-ha-haproxy"
 			a.Spec.HA.RedisProxyVersion = "latest-ha"
 		}},
@@ -218,7 +218,7 @@ var argoServerURITests = []struct {
 	{
 		name:         "test with external host name",
 		routeEnabled: false,
-		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+		opts: []argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
 			a.Spec.Server.Host = "test-host-name"
 		}},
 		want: "https://test-host-name",
@@ -366,6 +366,24 @@ func TestGetArgoApplicationControllerCommand(t *testing.T) {
 				"600",
 			},
 		},
+		{
+			"operator AutoTLS",
+			[]argoCDOpt{func(a *argoprojv1beta1.ArgoCD) {
+				a.Spec.Repo.AutoTLS = common.ArgoCDRepoServerAutoTLSOperator
+			}},
+			[]string{
+				"argocd-application-controller",
+				"--operation-processors",
+				"10",
+				"--redis",
+				"argocd-redis.argocd.svc.cluster.local:6379",
+				"--repo-server",
+				"argocd-repo-server.argocd.svc.cluster.local:8081",
+				"--status-processors",
+				"20",
+				"--repo-server-strict-tls",
+			},
+		},
 	}
 
 	for _, tt := range cmdTests {
@@ -16,8 +16,10 @@ package argocdexport
 
 import (
 	"context"
+	"fmt"
 
 	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/validation"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -90,6 +92,11 @@ func (r *ReconcileArgoCDExport) Reconcile(request reconcile.Request) (reconcile.
 		return reconcile.Result{}, err
 	}
 
+	if err := validation.ValidateArgoCDExport(export); err != nil {
+		// Resources created before the validating webhook was installed are not guaranteed to be valid.
+		return reconcile.Result{}, fmt.Errorf("invalid ArgoCDExport: %w", err)
+	}
+
 	if err := r.reconcileArgoCDExportResources(export); err != nil {
 		// Error reconciling ArgoCDExport sub-resources - requeue the request.
 		return reconcile.Result{}, err
@@ -0,0 +1,135 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// ApplyObject will apply the given object using server-side apply with the operator field manager. The object must
+// describe the complete desired state: fields applied previously that are no longer set are removed, fields that
+// differ are reverted and fields owned by other managers are left untouched. The fields of an existing object that
+// the operator set with updates, before it used server-side apply, are taken over by the apply field manager first.
+func ApplyObject(c client.Client, s *runtime.Scheme, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, s)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := takeOverUpdatedFields(c, obj); err != nil {
+		return err
+	}
+
+	accessor.SetResourceVersion("")
+	accessor.SetManagedFields(nil)
+
+	setDefaultProtocols(obj)
+
+	return c.Patch(context.TODO(), obj, client.Apply, client.FieldOwner(common.ArgoCDFieldManager), client.ForceOwnership)
+}
+
+// takeOverUpdatedFields will hand the fields that the operator set with updates on the existing object for the given
+// object over to the apply field manager, so that they are removed once the desired state no longer sets them. Only
+// objects that were never applied by the operator are changed, so the takeover happens once: fields that the operator
+// still sets with updates afterwards, e.g. rollout labels, are not pruned by the next apply.
+func takeOverUpdatedFields(c client.Client, obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	existing := obj.DeepCopyObject()
+	key := client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}
+	if err := c.Get(context.TODO(), key, existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	existingAccessor, err := meta.Accessor(existing)
+	if err != nil {
+		return err
+	}
+
+	managedFields := existingAccessor.GetManagedFields()
+	updated := -1
+	for i, entry := range managedFields {
+		if entry.Manager != common.ArgoCDFieldManager {
+			continue
+		}
+		if entry.Operation == metav1.ManagedFieldsOperationApply {
+			return nil // Already applied, the fields are owned by the apply field manager.
+		}
+		if entry.Operation == metav1.ManagedFieldsOperationUpdate && entry.APIVersion == obj.GetObjectKind().GroupVersionKind().GroupVersion().String() {
+			updated = i
+		}
+	}
+	if updated < 0 {
+		return nil
+	}
+
+	patch := client.MergeFrom(existing.DeepCopyObject())
+	managedFields[updated].Operation = metav1.ManagedFieldsOperationApply
+	existingAccessor.SetManagedFields(managedFields)
+	return c.Patch(context.TODO(), existing, patch)
+}
+
+// setDefaultProtocols will set the protocol of any port without one to TCP. The protocol is part of the key for
+// container and service ports, server-side apply rejects list entries that omit it.
+func setDefaultProtocols(obj runtime.Object) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		setPodSpecDefaultProtocols(&o.Spec.Template.Spec)
+	case *appsv1.StatefulSet:
+		setPodSpecDefaultProtocols(&o.Spec.Template.Spec)
+	case *corev1.Service:
+		for i := range o.Spec.Ports {
+			if o.Spec.Ports[i].Protocol == "" {
+				o.Spec.Ports[i].Protocol = corev1.ProtocolTCP
+			}
+		}
+	}
+}
+
+// setPodSpecDefaultProtocols will set the protocol of any container port without one to TCP.
+func setPodSpecDefaultProtocols(spec *corev1.PodSpec) {
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			for j := range containers[i].Ports {
+				if containers[i].Ports[j].Protocol == "" {
+					containers[i].Ports[j].Protocol = corev1.ProtocolTCP
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// patchRecorder records the last patch sent through the client.
+type patchRecorder struct {
+	client.Client
+	data  []byte
+	patch client.Patch
+	opts  *client.PatchOptions
+}
+
+func (p *patchRecorder) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	p.data = data
+	p.patch = patch
+	p.opts = &client.PatchOptions{}
+	p.opts.ApplyOptions(opts)
+	return nil
+}
+
+func TestApplyObject(t *testing.T) {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "argocd-server",
+			Namespace:       "argocd",
+			ResourceVersion: "10",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "argocd-server",
+						Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+					}},
+				},
+			},
+		},
+	}
+
+	c := &patchRecorder{Client: fake.NewFakeClientWithScheme(scheme.Scheme)}
+	if err := ApplyObject(c, scheme.Scheme, deploy); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.patch.Type() != types.ApplyPatchType {
+		t.Fatalf("got patch type %q, want %q", c.patch.Type(), types.ApplyPatchType)
+	}
+	if c.opts.FieldManager != common.ArgoCDFieldManager {
+		t.Fatalf("got field manager %q, want %q", c.opts.FieldManager, common.ArgoCDFieldManager)
+	}
+	if c.opts.Force == nil || !*c.opts.Force {
+		t.Fatal("ownership of conflicting fields is not forced")
+	}
+
+	applied := &appsv1.Deployment{}
+	if err := json.Unmarshal(c.data, applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied.APIVersion != "apps/v1" || applied.Kind != "Deployment" {
+		t.Fatalf("got apiVersion %q and kind %q, want apps/v1 Deployment", applied.APIVersion, applied.Kind)
+	}
+	if applied.ResourceVersion != "" {
+		t.Fatalf("got resourceVersion %q, want none", applied.ResourceVersion)
+	}
+	if p := applied.Spec.Template.Spec.Containers[0].Ports[0].Protocol; p != corev1.ProtocolTCP {
+		t.Fatalf("got protocol %q, want %q", p, corev1.ProtocolTCP)
+	}
+}
+
+// startTestAPIServer will start a test API server and return a client for it, the test is skipped when the API server
+// binaries are not installed, see KUBEBUILDER_ASSETS. The fake client does not implement server-side apply.
+func startTestAPIServer(t *testing.T) client.Client {
+	t.Helper()
+	env := &envtest.Environment{}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Skipf("unable to start the test API server, set KUBEBUILDER_ASSETS to run this test: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = env.Stop()
+	})
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "argocd"}}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func makeTestApplyDeployment(annotations map[string]string) *appsv1.Deployment {
+	labels := map[string]string{"app.kubernetes.io/name": "argocd-server"}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "argocd-server",
+			Namespace:   "argocd",
+			Annotations: annotations,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "argocd-server",
+						Image: "quay.io/argoproj/argocd:v2.0.0",
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestApplyObject_managedFields(t *testing.T) {
+	c := startTestAPIServer(t)
+	key := types.NamespacedName{Name: "argocd-server", Namespace: "argocd"}
+	get := func() *appsv1.Deployment {
+		t.Helper()
+		deploy := &appsv1.Deployment{}
+		if err := c.Get(context.TODO(), key, deploy); err != nil {
+			t.Fatal(err)
+		}
+		return deploy
+	}
+
+	// A Deployment created with an update by a previous version of the operator.
+	legacy := makeTestApplyDeployment(map[string]string{"legacy": "true"})
+	replicas := int32(2)
+	legacy.Spec.Replicas = &replicas
+	if err := c.Create(context.TODO(), legacy, client.FieldOwner(common.ArgoCDFieldManager)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fields set by the update are taken over and pruned by the first apply.
+	if err := ApplyObject(c, scheme.Scheme, makeTestApplyDeployment(nil)); err != nil {
+		t.Fatal(err)
+	}
+	deploy := get()
+	if _, ok := deploy.Annotations["legacy"]; ok {
+		t.Fatal("the annotation set by the update was not pruned")
+	}
+	if *deploy.Spec.Replicas != 1 {
+		t.Fatalf("got %d replicas, want the default of 1", *deploy.Spec.Replicas)
+	}
+
+	// The replicas set by another manager, e.g. a HorizontalPodAutoscaler, are left alone.
+	replicas = 3
+	deploy.Spec.Replicas = &replicas
+	if err := c.Update(context.TODO(), deploy, client.FieldOwner("horizontal-pod-autoscaler")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyObject(c, scheme.Scheme, makeTestApplyDeployment(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if deploy = get(); *deploy.Spec.Replicas != 3 {
+		t.Fatalf("got %d replicas, want the 3 replicas set by the other manager", *deploy.Spec.Replicas)
+	}
+
+	// The fields owned by the operator are reverted when changed by hand.
+	deploy.Spec.Template.Spec.Containers[0].Image = "example.com/argocd:latest"
+	if err := c.Update(context.TODO(), deploy, client.FieldOwner("kubectl-edit")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyObject(c, scheme.Scheme, makeTestApplyDeployment(map[string]string{"applied": "true"})); err != nil {
+		t.Fatal(err)
+	}
+	deploy = get()
+	if image := deploy.Spec.Template.Spec.Containers[0].Image; image != "quay.io/argoproj/argocd:v2.0.0" {
+		t.Fatalf("got image %q, want the applied image", image)
+	}
+	if deploy.Annotations["applied"] != "true" {
+		t.Fatal("the applied annotation is not set")
+	}
+
+	// The fields that are no longer applied are removed.
+	if err := ApplyObject(c, scheme.Scheme, makeTestApplyDeployment(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := get().Annotations["applied"]; ok {
+		t.Fatal("the annotation that is no longer applied was not pruned")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+// FindCondition returns the condition with the given type, or nil if no such condition exists.
+func FindCondition(conditions []argoprojv1b1.ArgoCDCondition, conditionType string) *argoprojv1b1.ArgoCDCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds the given condition to the conditions, or updates the existing condition of the same type.
+// The LastTransitionTime is only changed when the status of the condition changes.
+func SetCondition(conditions *[]argoprojv1b1.ArgoCDCondition, condition argoprojv1b1.ArgoCDCondition) {
+	existing := FindCondition(*conditions, condition.Type)
+	if existing == nil {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		*conditions = append(*conditions, condition)
+		return
+	}
+
+	if existing.Status != condition.Status {
+		existing.Status = condition.Status
+		if condition.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = metav1.Now()
+		} else {
+			existing.LastTransitionTime = condition.LastTransitionTime
+		}
+	}
+
+	existing.Reason = condition.Reason
+	existing.Message = condition.Message
+	existing.ObservedGeneration = condition.ObservedGeneration
+}
+
+// RemoveCondition removes the condition with the given type from the conditions.
+func RemoveCondition(conditions *[]argoprojv1b1.ArgoCDCondition, conditionType string) {
+	if conditions == nil || len(*conditions) == 0 {
+		return
+	}
+
+	updated := make([]argoprojv1b1.ArgoCDCondition, 0, len(*conditions))
+	for _, c := range *conditions {
+		if c.Type != conditionType {
+			updated = append(updated, c)
+		}
+	}
+	*conditions = updated
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+func TestSetCondition(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	conditions := []argoprojv1b1.ArgoCDCondition{{
+		Type:               argoprojv1b1.ArgoCDConditionReconciled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: past,
+		Reason:             argoprojv1b1.ArgoCDReasonReconcileSucceeded,
+	}}
+
+	// Same status, the transition time must not change.
+	SetCondition(&conditions, argoprojv1b1.ArgoCDCondition{
+		Type:               argoprojv1b1.ArgoCDConditionReconciled,
+		Status:             corev1.ConditionTrue,
+		ObservedGeneration: 2,
+		Reason:             argoprojv1b1.ArgoCDReasonReconcileSucceeded,
+	})
+	got := FindCondition(conditions, argoprojv1b1.ArgoCDConditionReconciled)
+	if got == nil || !got.LastTransitionTime.Equal(&past) || got.ObservedGeneration != 2 {
+		t.Fatalf("unexpected condition after update without transition: %v", got)
+	}
+
+	// Changed status, the transition time must be updated.
+	SetCondition(&conditions, argoprojv1b1.ArgoCDCondition{
+		Type:    argoprojv1b1.ArgoCDConditionReconciled,
+		Status:  corev1.ConditionFalse,
+		Reason:  argoprojv1b1.ArgoCDReasonReconcileFailed,
+		Message: "failed",
+	})
+	got = FindCondition(conditions, argoprojv1b1.ArgoCDConditionReconciled)
+	if got == nil || !past.Before(&got.LastTransitionTime) || got.Reason != argoprojv1b1.ArgoCDReasonReconcileFailed || got.Message != "failed" {
+		t.Fatalf("unexpected condition after transition: %v", got)
+	}
+
+	// New condition type.
+	SetCondition(&conditions, argoprojv1b1.ArgoCDCondition{
+		Type:   argoprojv1b1.ArgoCDConditionAvailable,
+		Status: corev1.ConditionTrue,
+		Reason: argoprojv1b1.ArgoCDReasonComponentsRunning,
+	})
+	if len(conditions) != 2 || conditions[1].LastTransitionTime.IsZero() {
+		t.Fatalf("expected a new condition, got %v", conditions)
+	}
+}
+
+func TestRemoveCondition(t *testing.T) {
+	conditions := []argoprojv1b1.ArgoCDCondition{
+		{Type: argoprojv1b1.ArgoCDConditionAvailable},
+		{Type: argoprojv1b1.ArgoCDConditionSSOReady},
+	}
+
+	RemoveCondition(&conditions, argoprojv1b1.ArgoCDConditionSSOReady)
+	if len(conditions) != 1 || FindCondition(conditions, argoprojv1b1.ArgoCDConditionSSOReady) != nil {
+		t.Fatalf("expected SSOReady to be removed, got %v", conditions)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/common"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -75,7 +76,7 @@ func DefaultLabels(name string) map[string]string {
 }
 
 // DefaultAnnotations returns the default set of annotations for child resources of ArgoCD
-func DefaultAnnotations(cr *argoprojv1a1.ArgoCD) map[string]string {
+func DefaultAnnotations(cr *argoprojv1b1.ArgoCD) map[string]string {
 	return map[string]string{
 		common.AnnotationName:      cr.Name,
 		common.AnnotationNamespace: cr.Namespace,
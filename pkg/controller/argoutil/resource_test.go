@@ -18,13 +18,13 @@ import (
 	"reflect"
 	"testing"
 
-	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestDefaultAnnotations(t *testing.T) {
 	type args struct {
-		cr *argoprojv1a1.ArgoCD
+		cr *argoprojv1b1.ArgoCD
 	}
 	tests := []struct {
 		name string
@@ -34,7 +34,7 @@ func TestDefaultAnnotations(t *testing.T) {
 		{
 			name: "simple annotations",
 			args: args{
-				&argoprojv1a1.ArgoCD{
+				&argoprojv1b1.ArgoCD{
 					ObjectMeta: v1.ObjectMeta{
 						Name:      "foo",
 						Namespace: "bar",
@@ -15,12 +15,17 @@
 package argoutil
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"time"
@@ -29,17 +34,85 @@ import (
 	tlsutil "github.com/operator-framework/operator-sdk/pkg/tls"
 )
 
-// NewPrivateKey returns randomly generated RSA private key.
-func NewPrivateKey() (*rsa.PrivateKey, error) {
-	return rsa.GenerateKey(rand.Reader, common.ArgoCDDefaultRSAKeySize)
+// NewPrivateKey returns a randomly generated private key using the given algorithm and size in bits. The default size
+// for the algorithm is used when the size is zero, the size is ignored for Ed25519 keys.
+func NewPrivateKey(algorithm string, size int) (crypto.Signer, error) {
+	switch algorithm {
+	case common.ArgoCDTLSKeyAlgorithmRSA:
+		if size == 0 {
+			size = common.ArgoCDDefaultRSAKeySize
+		}
+		return rsa.GenerateKey(rand.Reader, size)
+	case common.ArgoCDTLSKeyAlgorithmECDSA:
+		if size == 0 {
+			size = common.ArgoCDDefaultECDSAKeySize
+		}
+		curve, err := getEllipticCurve(size)
+		if err != nil {
+			return nil, err
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case common.ArgoCDTLSKeyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key algorithm %s", algorithm)
 }
 
-// EncodePrivateKeyPEM encodes the given private key pem and returns bytes (base64).
-func EncodePrivateKeyPEM(key *rsa.PrivateKey) []byte {
-	return pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(key),
-	})
+// getEllipticCurve returns the NIST curve with the given size in bits.
+func getEllipticCurve(size int) (elliptic.Curve, error) {
+	switch size {
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	case 521:
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("unsupported ECDSA key size %d", size)
+}
+
+// GetPublicKeyAlgorithm returns the algorithm and size in bits of the given public key, the size is zero for Ed25519
+// keys.
+func GetPublicKeyAlgorithm(pub crypto.PublicKey) (string, int) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return common.ArgoCDTLSKeyAlgorithmRSA, key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return common.ArgoCDTLSKeyAlgorithmECDSA, key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return common.ArgoCDTLSKeyAlgorithmEd25519, 0
+	}
+	return "", 0
+}
+
+// EncodePrivateKeyPEM encodes the given private key pem and returns bytes (base64). RSA keys are encoded in PKCS #1
+// form and ECDSA keys in SEC 1 form, other keys are encoded in PKCS #8 form.
+func EncodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	block := &pem.Block{}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		block.Type = "RSA PRIVATE KEY"
+		block.Bytes = x509.MarshalPKCS1PrivateKey(k)
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		block.Type = "EC PRIVATE KEY"
+		block.Bytes = der
+	default:
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		block.Type = "PRIVATE KEY"
+		block.Bytes = der
+	}
+	return pem.EncodeToMemory(block), nil
 }
 
 // EncodeCertificatePEM encodes the given certificate pem and returns bytes (base64).
@@ -59,28 +132,77 @@ func ParsePEMEncodedCert(pemdata []byte) (*x509.Certificate, error) {
 	return x509.ParseCertificate(decoded.Bytes)
 }
 
-// ParsePEMEncodedPrivateKey parses a private key from given pemdata
-func ParsePEMEncodedPrivateKey(pemdata []byte) (*rsa.PrivateKey, error) {
+// ParsePEMEncodedCerts parses all certificates from the given pemdata, such as a CA bundle.
+func ParsePEMEncodedCerts(pemdata []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var decoded *pem.Block
+		decoded, pemdata = pem.Decode(pemdata)
+		if decoded == nil {
+			return certs, nil
+		}
+		cert, err := x509.ParseCertificate(decoded.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// CertificateNeedsRenewal returns true if the given certificate expires within the given duration.
+func CertificateNeedsRenewal(cert *x509.Certificate, renewBefore time.Duration) bool {
+	return !time.Now().Add(renewBefore).Before(cert.NotAfter)
+}
+
+// ParsePEMEncodedPrivateKey parses a PKCS #1, SEC 1 or PKCS #8 encoded private key from given pemdata
+func ParsePEMEncodedPrivateKey(pemdata []byte) (crypto.Signer, error) {
 	decoded, _ := pem.Decode(pemdata)
 	if decoded == nil {
 		return nil, errors.New("no PEM data found")
 	}
-	return x509.ParsePKCS1PrivateKey(decoded.Bytes)
+
+	switch decoded.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(decoded.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(decoded.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(decoded.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+	return nil, fmt.Errorf("unsupported PEM block type %s", decoded.Type)
+}
+
+// getKeyUsage returns the key usage of a certificate for the given private key. Key encipherment is only used with
+// RSA keys.
+func getKeyUsage(key crypto.Signer) x509.KeyUsage {
+	usage := x509.KeyUsageDigitalSignature
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		usage |= x509.KeyUsageKeyEncipherment
+	}
+	return usage
 }
 
-// NewSelfSignedCACertificate returns a self-signed CA certificate based on given configuration and private key.
-// The certificate has one-year lease.
-func NewSelfSignedCACertificate(key *rsa.PrivateKey) (*x509.Certificate, error) {
+// NewSelfSignedCACertificate returns a self-signed CA certificate with the given subject, private key and validity.
+func NewSelfSignedCACertificate(subject pkix.Name, key crypto.Signer, validity time.Duration) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
 	}
 	now := time.Now()
 	tmpl := x509.Certificate{
+		Subject:               subject,
 		SerialNumber:          serial,
 		NotBefore:             now.UTC(),
-		NotAfter:              now.Add(common.ArgoCDDuration365Days).UTC(),
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		NotAfter:              now.Add(validity).UTC(),
+		KeyUsage:              getKeyUsage(key) | x509.KeyUsageCertSign,
 		BasicConstraintsValid: true,
 		IsCA:                  true,
 	}
@@ -93,8 +215,8 @@ func NewSelfSignedCACertificate(key *rsa.PrivateKey) (*x509.Certificate, error)
 
 // NewSignedCertificate signs a certificate using the given private key, CA and returns a signed certificate.
 // The certificate could be used for both client and server auth.
-// The certificate has one-year lease.
-func NewSignedCertificate(cfg *tlsutil.CertConfig, dnsNames []string, key *rsa.PrivateKey, caCert *x509.Certificate, caKey *rsa.PrivateKey) (*x509.Certificate, error) {
+// The certificate is valid for the given duration.
+func NewSignedCertificate(cfg *tlsutil.CertConfig, dnsNames []string, key crypto.Signer, caCert *x509.Certificate, caKey crypto.Signer, validity time.Duration) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -116,8 +238,8 @@ func NewSignedCertificate(cfg *tlsutil.CertConfig, dnsNames []string, key *rsa.P
 		DNSNames:     dnsNames,
 		SerialNumber: serial,
 		NotBefore:    caCert.NotBefore,
-		NotAfter:     time.Now().Add(common.ArgoCDDuration365Days).UTC(),
-		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		NotAfter:     time.Now().Add(validity).UTC(),
+		KeyUsage:     getKeyUsage(key),
 		ExtKeyUsage:  eku,
 	}
 	certDERBytes, err := x509.CreateCertificate(rand.Reader, &certTmpl, caCert, key.Public(), caKey)
@@ -0,0 +1,124 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func newTestCACertificate(t *testing.T) []byte {
+	t.Helper()
+	key, err := NewPrivateKey(common.ArgoCDTLSKeyAlgorithmRSA, 0)
+	assert.NilError(t, err)
+	cert, err := NewSelfSignedCACertificate(pkix.Name{}, key, common.ArgoCDDefaultCertificateDuration)
+	assert.NilError(t, err)
+	return EncodeCertificatePEM(cert)
+}
+
+func assertSamePublicKey(t *testing.T, actual, expected crypto.Signer) {
+	t.Helper()
+	actualDER, err := x509.MarshalPKIXPublicKey(actual.Public())
+	assert.NilError(t, err)
+	expectedDER, err := x509.MarshalPKIXPublicKey(expected.Public())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, actualDER, expectedDER)
+}
+
+func TestParsePEMEncodedCerts(t *testing.T) {
+	first := newTestCACertificate(t)
+	second := newTestCACertificate(t)
+
+	certs, err := ParsePEMEncodedCerts(append(append([]byte{}, first...), second...))
+	assert.NilError(t, err)
+	assert.Equal(t, len(certs), 2)
+	assert.DeepEqual(t, EncodeCertificatePEM(certs[0]), first)
+	assert.DeepEqual(t, EncodeCertificatePEM(certs[1]), second)
+
+	certs, err = ParsePEMEncodedCerts(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(certs), 0)
+}
+
+func TestCertificateNeedsRenewal(t *testing.T) {
+	certs, err := ParsePEMEncodedCerts(newTestCACertificate(t))
+	assert.NilError(t, err)
+	cert := certs[0]
+
+	assert.Assert(t, !CertificateNeedsRenewal(cert, time.Hour))
+	assert.Assert(t, CertificateNeedsRenewal(cert, common.ArgoCDDuration365Days))
+}
+
+func TestNewPrivateKey(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		size      int
+		wantSize  int
+		wantUsage x509.KeyUsage
+	}{
+		{common.ArgoCDTLSKeyAlgorithmRSA, 0, common.ArgoCDDefaultRSAKeySize, x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment},
+		{common.ArgoCDTLSKeyAlgorithmRSA, 3072, 3072, x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment},
+		{common.ArgoCDTLSKeyAlgorithmECDSA, 0, common.ArgoCDDefaultECDSAKeySize, x509.KeyUsageDigitalSignature},
+		{common.ArgoCDTLSKeyAlgorithmECDSA, 521, 521, x509.KeyUsageDigitalSignature},
+		{common.ArgoCDTLSKeyAlgorithmEd25519, 0, 0, x509.KeyUsageDigitalSignature},
+	}
+
+	for _, test := range tests {
+		t.Run(test.algorithm, func(t *testing.T) {
+			key, err := NewPrivateKey(test.algorithm, test.size)
+			assert.NilError(t, err)
+
+			algorithm, size := GetPublicKeyAlgorithm(key.Public())
+			assert.Equal(t, algorithm, test.algorithm)
+			assert.Equal(t, size, test.wantSize)
+			assert.Equal(t, getKeyUsage(key), test.wantUsage)
+
+			// The PEM encoded key is parsed back to the same key.
+			encoded, err := EncodePrivateKeyPEM(key)
+			assert.NilError(t, err)
+			decoded, err := ParsePEMEncodedPrivateKey(encoded)
+			assert.NilError(t, err)
+			assertSamePublicKey(t, decoded, key)
+		})
+	}
+
+	_, err := NewPrivateKey(common.ArgoCDTLSKeyAlgorithmECDSA, 128)
+	assert.ErrorContains(t, err, "unsupported ECDSA key size 128")
+
+	_, err = NewPrivateKey("DSA", 0)
+	assert.ErrorContains(t, err, "unsupported private key algorithm DSA")
+}
+
+func TestParsePEMEncodedPrivateKey_pkcs8(t *testing.T) {
+	key, err := NewPrivateKey(common.ArgoCDTLSKeyAlgorithmRSA, 0)
+	assert.NilError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NilError(t, err)
+
+	decoded, err := ParsePEMEncodedPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.NilError(t, err)
+	assertSamePublicKey(t, decoded, key)
+
+	_, err = ParsePEMEncodedPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "DSA PRIVATE KEY", Bytes: der}))
+	assert.ErrorContains(t, err, "unsupported PEM block type DSA PRIVATE KEY")
+}
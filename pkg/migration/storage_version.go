@@ -0,0 +1,166 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+var log = logf.Log.WithName("migration")
+
+// migrationBackoff is the backoff between the attempts of the storage version migration. The resources stored at a
+// previous version are listed through the conversion webhook, which may not be serving yet when the Manager starts.
+var migrationBackoff = wait.Backoff{
+	Duration: 5 * time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    10,
+	Cap:      10 * time.Minute,
+}
+
+// StorageVersionMigrator rewrites all ArgoCD resources at the current storage version and removes the
+// previous versions from the stored versions of the ArgoCD CustomResourceDefinition, so that those versions
+// can be removed from the CRD in a later release.
+type StorageVersionMigrator struct {
+	// client is used to update the resources.
+	client client.Client
+
+	// reader reads directly from the API server, the migration must not rely on the namespaced cache.
+	reader client.Reader
+
+	// recorder reports the resources that could not be migrated.
+	recorder record.EventRecorder
+}
+
+// AddToManager adds the storage version migration to the given Manager, it runs when the Manager is started and is
+// retried until it succeeds or the Manager is stopped.
+func AddToManager(mgr manager.Manager) error {
+	if err := apiextensionsv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+
+	m := &StorageVersionMigrator{
+		client:   mgr.GetClient(),
+		reader:   mgr.GetAPIReader(),
+		recorder: mgr.GetEventRecorderFor("argocd-operator"),
+	}
+	return mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		// A failed migration must not stop the operator.
+		m.migrateUntilDone(stop, migrationBackoff)
+		return nil
+	}))
+}
+
+// migrateUntilDone will run the migration until it succeeds or the given channel is closed, waiting for the given
+// backoff between the attempts.
+func (m *StorageVersionMigrator) migrateUntilDone(stop <-chan struct{}, backoff wait.Backoff) {
+	for {
+		err := m.Migrate(context.TODO())
+		if err == nil {
+			return
+		}
+
+		delay := backoff.Step()
+		log.Error(err, fmt.Sprintf("unable to migrate ArgoCD storage version, retrying in %s", delay))
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Migrate will rewrite every ArgoCD resource at the storage version and update the stored versions of the CRD. A
+// resource that is rejected by the API server, e.g. because it is not valid, is reported with an Event and skipped,
+// the stored versions are then left as they are.
+func (m *StorageVersionMigrator) Migrate(ctx context.Context) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := m.reader.Get(ctx, types.NamespacedName{Name: common.ArgoCDCRDName}, crd); err != nil {
+		return err
+	}
+
+	storageVersion := getStorageVersion(crd)
+	if storageVersion == "" {
+		return fmt.Errorf("no storage version found for %s", common.ArgoCDCRDName)
+	}
+
+	if isMigrated(crd, storageVersion) {
+		return nil // Nothing stored at a previous version, nothing to do.
+	}
+
+	list := &argoproj.ArgoCDList{}
+	if err := m.reader.List(ctx, list); err != nil {
+		return err
+	}
+
+	skipped := 0
+	for i := range list.Items {
+		cr := &list.Items[i]
+		log.Info(fmt.Sprintf("migrating ArgoCD %s/%s to storage version %s", cr.Namespace, cr.Name, storageVersion))
+
+		// An update without changes is enough, the API server always writes the resource at the storage version.
+		if err := m.client.Update(ctx, cr); err != nil {
+			if errors.IsNotFound(err) || errors.IsConflict(err) {
+				continue // Deleted or changed since listed, either way no longer stored at a previous version.
+			}
+			if errors.IsForbidden(err) || errors.IsInvalid(err) || errors.IsBadRequest(err) {
+				// Denied by the validating webhook, the other resources are still migrated.
+				m.recorder.Event(cr, corev1.EventTypeWarning, argoproj.ArgoCDReasonStorageVersionMigrationFailed,
+					fmt.Sprintf("unable to migrate to storage version %s: %v", storageVersion, err))
+				skipped++
+				continue
+			}
+			return err
+		}
+	}
+
+	if skipped > 0 {
+		return fmt.Errorf("%d ArgoCD resources could not be migrated to storage version %s", skipped, storageVersion)
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	return m.client.Status().Update(ctx, crd)
+}
+
+// getStorageVersion will return the name of the storage version for the given CRD.
+func getStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// isMigrated returns true if the given version is the only stored version for the given CRD.
+func isMigrated(crd *apiextensionsv1.CustomResourceDefinition, version string) bool {
+	stored := crd.Status.StoredVersions
+	return len(stored) == 1 && stored[0] == version
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis"
+	argoproj "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func makeTestCRD(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: common.ArgoCDCRDName,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			StoredVersions: storedVersions,
+		},
+	}
+}
+
+func makeTestMigrator(t *testing.T, objs ...runtime.Object) *StorageVersionMigrator {
+	s := scheme.Scheme
+	assert.NilError(t, apis.AddToScheme(s))
+	assert.NilError(t, apiextensionsv1.AddToScheme(s))
+
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	return &StorageVersionMigrator{client: cl, reader: cl, recorder: record.NewFakeRecorder(10)}
+}
+
+// updateErrorClient is a client that fails the updates of the ArgoCD resources with the errors returned by the given
+// function, a nil error lets the update through.
+type updateErrorClient struct {
+	client.Client
+	err func(obj runtime.Object) error
+}
+
+func (c *updateErrorClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	if _, ok := obj.(*argoproj.ArgoCD); ok {
+		if err := c.err(obj); err != nil {
+			return err
+		}
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func makeTestArgoCD(name string) *argoproj.ArgoCD {
+	return &argoproj.ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "argocd",
+		},
+	}
+}
+
+func TestStorageVersionMigrator_Migrate(t *testing.T) {
+	a := &argoproj.ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd",
+			Namespace: "argocd",
+		},
+	}
+	m := makeTestMigrator(t, makeTestCRD("v1alpha1", "v1beta1"), a)
+
+	assert.NilError(t, m.Migrate(context.TODO()))
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	assert.NilError(t, m.reader.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDCRDName}, crd))
+	assert.DeepEqual(t, crd.Status.StoredVersions, []string{"v1beta1"})
+}
+
+func TestStorageVersionMigrator_Migrate_alreadyMigrated(t *testing.T) {
+	m := makeTestMigrator(t, makeTestCRD("v1beta1"))
+
+	assert.NilError(t, m.Migrate(context.TODO()))
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	assert.NilError(t, m.reader.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDCRDName}, crd))
+	assert.DeepEqual(t, crd.Status.StoredVersions, []string{"v1beta1"})
+}
+
+func TestStorageVersionMigrator_Migrate_denied(t *testing.T) {
+	m := makeTestMigrator(t, makeTestCRD("v1alpha1", "v1beta1"), makeTestArgoCD("argocd"), makeTestArgoCD("invalid"))
+	denied := 0
+	m.client = &updateErrorClient{Client: m.client, err: func(obj runtime.Object) error {
+		if cr := obj.(*argoproj.ArgoCD); cr.Name == "invalid" {
+			denied++
+			return apierrors.NewForbidden(argoproj.SchemeGroupVersion.WithResource("argocds").GroupResource(), cr.Name,
+				errors.New("admission webhook denied the request"))
+		}
+		return nil
+	}}
+
+	// The resource that is denied is reported and skipped, the stored versions are kept.
+	assert.ErrorContains(t, m.Migrate(context.TODO()), "1 ArgoCD resources could not be migrated to storage version v1beta1")
+	assert.Equal(t, denied, 1)
+	event := <-m.recorder.(*record.FakeRecorder).Events
+	assert.Assert(t, strings.HasPrefix(event, "Warning StorageVersionMigrationFailed unable to migrate to storage version v1beta1"), event)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	assert.NilError(t, m.reader.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDCRDName}, crd))
+	assert.DeepEqual(t, crd.Status.StoredVersions, []string{"v1alpha1", "v1beta1"})
+}
+
+func TestStorageVersionMigrator_migrateUntilDone(t *testing.T) {
+	m := makeTestMigrator(t, makeTestCRD("v1alpha1", "v1beta1"), makeTestArgoCD("argocd"))
+	attempts := 0
+	m.client = &updateErrorClient{Client: m.client, err: func(obj runtime.Object) error {
+		// The conversion webhook is not serving during the first attempts.
+		if attempts++; attempts < 3 {
+			return apierrors.NewInternalError(errors.New("conversion webhook is not available"))
+		}
+		return nil
+	}}
+
+	m.migrateUntilDone(make(chan struct{}), wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 5})
+	assert.Equal(t, attempts, 3)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	assert.NilError(t, m.reader.Get(context.TODO(), types.NamespacedName{Name: common.ArgoCDCRDName}, crd))
+	assert.DeepEqual(t, crd.Status.StoredVersions, []string{"v1beta1"})
+}
+
+func TestStorageVersionMigrator_migrateUntilDone_stopped(t *testing.T) {
+	m := makeTestMigrator(t)
+	stop := make(chan struct{})
+	close(stop)
+
+	// The missing CRD fails every attempt, the retries end with the Manager.
+	m.migrateUntilDone(stop, wait.Backoff{Duration: time.Hour})
+}
+
+func TestStorageVersionMigrator_Migrate_missingCRD(t *testing.T) {
+	m := makeTestMigrator(t)
+	assert.ErrorContains(t, m.Migrate(context.TODO()), "not found")
+}
@@ -7,7 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"os"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -37,14 +37,14 @@ func makeTestPolicyRules() []rbacv1.PolicyRule {
 	}
 }
 
-func makeTestArgoCDForClusterConfig() *argoprojv1alpha1.ArgoCD {
+func makeTestArgoCDForClusterConfig() *argoprojv1beta1.ArgoCD {
 	a := makeTestArgoCD()
 	a.Namespace = testNamespace
 	return a
 }
 
-func makeTestArgoCD() *argoprojv1alpha1.ArgoCD {
-	a := &argoprojv1alpha1.ArgoCD{
+func makeTestArgoCD() *argoprojv1beta1.ArgoCD {
+	a := &argoprojv1beta1.ArgoCD{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      testArgoCDName,
 			Namespace: testDummyNameSpace,
@@ -109,11 +109,11 @@ func makeTestRoleBinding() *rbacv1.RoleBinding {
 	}
 }
 
-func newStatefulSetWithSuffix(suffix string, component string, cr *argoprojv1alpha1.ArgoCD) *appsv1.StatefulSet {
+func newStatefulSetWithSuffix(suffix string, component string, cr *argoprojv1beta1.ArgoCD) *appsv1.StatefulSet {
 	return newStatefulSetWithName(fmt.Sprintf("%s-%s", cr.Name, suffix), component, cr)
 }
 
-func newStatefulSetWithName(name string, component string, cr *argoprojv1alpha1.ArgoCD) *appsv1.StatefulSet {
+func newStatefulSetWithName(name string, component string, cr *argoprojv1beta1.ArgoCD) *appsv1.StatefulSet {
 	ss := newStatefulSet(cr)
 	ss.ObjectMeta.Name = name
 
@@ -125,7 +125,7 @@ func newStatefulSetWithName(name string, component string, cr *argoprojv1alpha1.
 	return ss
 }
 
-func newStatefulSet(cr *argoprojv1alpha1.ArgoCD) *appsv1.StatefulSet {
+func newStatefulSet(cr *argoprojv1beta1.ArgoCD) *appsv1.StatefulSet {
 	ss := appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -238,7 +238,7 @@ func newStatefulSet(cr *argoprojv1alpha1.ArgoCD) *appsv1.StatefulSet {
 	return &ss
 }
 
-func labelsForCluster(cr *argoprojv1alpha1.ArgoCD) map[string]string {
+func labelsForCluster(cr *argoprojv1beta1.ArgoCD) map[string]string {
 	labels := argoutil.DefaultLabels(cr.Name)
 	for key, val := range cr.ObjectMeta.Labels {
 		labels[key] = val
@@ -6,7 +6,7 @@ import (
 	"os"
 	"strings"
 
-	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1beta1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
 	"github.com/argoproj-labs/argocd-operator/pkg/controller/argocd"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -23,7 +23,7 @@ func init() {
 	argocd.Register(reconcilerHook)
 }
 
-func reconcilerHook(cr *argoprojv1alpha1.ArgoCD, v interface{}, hint string) error {
+func reconcilerHook(cr *argoprojv1beta1.ArgoCD, v interface{}, hint string) error {
 	logv := log.WithValues("ArgoCD Namespace", cr.Namespace, "ArgoCD Name", cr.Name)
 	switch o := v.(type) {
 	case *rbacv1.ClusterRole:
@@ -0,0 +1,752 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	argorbac "github.com/argoproj/argo-cd/util/rbac"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+const (
+	// redisTLSMinimumArgoCDVersion is the first Argo CD version that connects to Redis with TLS.
+	redisTLSMinimumArgoCDVersion = "2.3.0"
+
+	// redisTLSMinimumRedisVersion is the first Redis version that serves TLS.
+	redisTLSMinimumRedisVersion = "6.0.0"
+)
+
+// ValidateArgoCD will validate the given ArgoCD and return an aggregate of all invalid fields, or nil when the
+// ArgoCD is valid. The routeAPIAvailable argument indicates whether the cluster supports OpenShift Routes.
+func ValidateArgoCD(cr *argoprojv1b1.ArgoCD, routeAPIAvailable bool) error {
+	allErrs := argoprojv1a1.ValidateStringFields(cr)
+	allErrs = append(allErrs, validateArgoCDSpec(cr, routeAPIAvailable)...)
+	return allErrs.ToAggregate()
+}
+
+// ValidateArgoCDSpec will validate the given ArgoCD like ValidateArgoCD, except for the v1alpha1 string fields that
+// could not be converted. The original strings of these fields are used as is by the controller.
+func ValidateArgoCDSpec(cr *argoprojv1b1.ArgoCD, routeAPIAvailable bool) error {
+	return validateArgoCDSpec(cr, routeAPIAvailable).ToAggregate()
+}
+
+// validateArgoCDSpec will return the invalid fields of the spec of the given ArgoCD.
+func validateArgoCDSpec(cr *argoprojv1b1.ArgoCD, routeAPIAvailable bool) field.ErrorList {
+	spec := field.NewPath("spec")
+
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateAdminPassword(cr.Spec.AdminPassword, spec.Child("adminPassword"))...)
+	allErrs = append(allErrs, validateRBACPolicy(cr.Spec.RBAC.Policy, spec.Child("rbac", "policy"))...)
+	allErrs = append(allErrs, validateRBACRoles(&cr.Spec.RBAC, spec.Child("rbac"))...)
+	allErrs = append(allErrs, validateTLS(&cr.Spec.TLS, spec.Child("tls"))...)
+	allErrs = append(allErrs, validateRepoAutoTLS(cr.Spec.Repo.AutoTLS, spec.Child("repo", "autotls"))...)
+	allErrs = append(allErrs, validateHA(&cr.Spec.HA, spec.Child("ha"))...)
+	allErrs = append(allErrs, validateRedisTLS(cr, spec)...)
+	allErrs = append(allErrs, validateDexConnectors(&cr.Spec.Dex, spec.Child("dex"))...)
+	allErrs = append(allErrs, validateDexOpenShiftOAuth(&cr.Spec.Dex, spec.Child("dex"))...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(cr.Spec.Dex.PodDisruptionBudget, spec.Child("dex", "podDisruptionBudget"))...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(cr.Spec.Repo.PodDisruptionBudget, spec.Child("repo", "podDisruptionBudget"))...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(cr.Spec.Server.PodDisruptionBudget, spec.Child("server", "podDisruptionBudget"))...)
+	if cr.Spec.ApplicationSet != nil {
+		allErrs = append(allErrs, validatePodDisruptionBudget(cr.Spec.ApplicationSet.PodDisruptionBudget, spec.Child("applicationSet", "podDisruptionBudget"))...)
+	}
+	allErrs = append(allErrs, validateRotationInterval(cr.Spec.Server.SecretKey.RotationInterval, spec.Child("server", "secretKey", "rotationInterval"))...)
+	allErrs = append(allErrs, validateNetworkPolicy(&cr.Spec.NetworkPolicy, spec.Child("networkPolicy"))...)
+	allErrs = append(allErrs, validateUsers(cr.Spec.Users, spec.Child("users"))...)
+	allErrs = append(allErrs, validateRepositories(cr.Spec.Repositories, false, spec.Child("repositories"))...)
+	allErrs = append(allErrs, validateRepositories(cr.Spec.RepositoryCredentialTemplates, true, spec.Child("repositoryCredentialTemplates"))...)
+	allErrs = append(allErrs, validateConfigMapRepositories(cr.Spec.InitialRepositories, spec.Child("initialRepositories"))...)
+	allErrs = append(allErrs, validateConfigMapRepositories(cr.Spec.RepositoryCredentials, spec.Child("repositoryCredentials"))...)
+
+	if !routeAPIAvailable {
+		allErrs = append(allErrs, validateRouteOrIngress(cr.Spec.Grafana.Route.Enabled, cr.Spec.Grafana.Ingress.Enabled, spec.Child("grafana"))...)
+		allErrs = append(allErrs, validateRouteOrIngress(cr.Spec.Prometheus.Route.Enabled, cr.Spec.Prometheus.Ingress.Enabled, spec.Child("prometheus"))...)
+		allErrs = append(allErrs, validateRouteOrIngress(cr.Spec.Server.Route.Enabled, cr.Spec.Server.Ingress.Enabled, spec.Child("server"))...)
+	}
+
+	return allErrs
+}
+
+// validateAdminPassword will verify that the admin password is read from a complete Secret key reference, and that it
+// is rotated at most once an hour when it is generated by the operator.
+func validateAdminPassword(password *argoprojv1b1.ArgoCDAdminPasswordSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if password == nil {
+		return allErrs
+	}
+
+	if ref := password.SecretRef; ref != nil {
+		allErrs = append(allErrs, validateSecretKeySelector(ref, path.Child("secretRef"))...)
+		if password.RotationInterval != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("rotationInterval"), "may not be set together with secretRef"))
+		}
+	}
+
+	allErrs = append(allErrs, validateRotationInterval(password.RotationInterval, path.Child("rotationInterval"))...)
+	return allErrs
+}
+
+// validateRBACPolicy will verify that each line of the given RBAC policy CSV is a well-formed policy rule or
+// role binding, and that the policy as a whole is accepted by the casbin enforcer of Argo CD.
+func validateRBACPolicy(policy *string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy == nil {
+		return allErrs
+	}
+
+	for i, line := range strings.Split(*policy, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		reader := csv.NewReader(strings.NewReader(line))
+		reader.TrimLeadingSpace = true
+
+		record, err := reader.Read()
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(path, line, fmt.Sprintf("line %d: %v", i+1, err)))
+			continue
+		}
+
+		if msg := validateRBACPolicyRecord(record); msg != "" {
+			allErrs = append(allErrs, field.Invalid(path, line, fmt.Sprintf("line %d: %s", i+1, msg)))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		if err := argorbac.ValidatePolicy(*policy); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, *policy, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// ValidateRBACPolicy will verify that each line of the given RBAC policy CSV is a well-formed policy rule or role
+// binding accepted by casbin, and return an aggregate of the problems, or nil when the policy is valid.
+func ValidateRBACPolicy(policy string) error {
+	return validateRBACPolicy(&policy, field.NewPath(common.ArgoCDKeyRBACPolicyCSV)).ToAggregate()
+}
+
+// validateRBACPolicyRecord will return a message describing the problem with the given policy record, or an empty
+// string when the record is valid.
+func validateRBACPolicyRecord(record []string) string {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	switch record[0] {
+	case "p":
+		if len(record) != 6 {
+			return fmt.Sprintf("policy rules must be in the form 'p, subject, resource, action, object, effect', found %d fields", len(record))
+		}
+		if record[5] != "allow" && record[5] != "deny" {
+			return fmt.Sprintf("policy effect must be 'allow' or 'deny', found '%s'", record[5])
+		}
+	case "g":
+		if len(record) != 3 {
+			return fmt.Sprintf("role bindings must be in the form 'g, subject, inherited-subject', found %d fields", len(record))
+		}
+	default:
+		return fmt.Sprintf("policy lines must start with 'p' or 'g', found '%s'", record[0])
+	}
+
+	for _, value := range record[1:] {
+		if value == "" {
+			return "policy fields must not be empty"
+		}
+	}
+	return ""
+}
+
+// rbacResources are the Argo CD resource types of the RBAC permissions.
+var rbacResources = []string{
+	"accounts",
+	"applications",
+	"certificates",
+	"clusters",
+	"gpgkeys",
+	"projects",
+	"repositories",
+}
+
+// validateRBACRoles will verify that the typed roles have unique names and complete permissions, that the group
+// bindings refer to a role, and that the policy ConfigMap selector is valid. The values are rendered to policy CSV
+// lines, they may not contain commas or line breaks.
+func validateRBACRoles(rbac *argoprojv1b1.ArgoCDRBACSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	roles := map[string]bool{
+		common.ArgoCDRBACRoleAdmin:    true,
+		common.ArgoCDRBACRoleReadonly: true,
+	}
+	for i, role := range rbac.Roles {
+		rolePath := path.Child("roles").Index(i)
+		switch err := validateRBACPolicyValue(role.Name, rolePath.Child("name")); {
+		case err != nil:
+			allErrs = append(allErrs, err)
+		case role.Name == common.ArgoCDRBACRoleAdmin || role.Name == common.ArgoCDRBACRoleReadonly:
+			allErrs = append(allErrs, field.Invalid(rolePath.Child("name"), role.Name, "is reserved for a built-in role"))
+		case roles[role.Name]:
+			allErrs = append(allErrs, field.Duplicate(rolePath.Child("name"), role.Name))
+		}
+		roles[role.Name] = true
+
+		for j, permission := range role.Permissions {
+			permissionPath := rolePath.Child("permissions").Index(j)
+			if err := validateRBACPolicyValue(permission.Resource, permissionPath.Child("resource")); err != nil {
+				allErrs = append(allErrs, err)
+			} else if !isRBACResource(permission.Resource) {
+				allErrs = append(allErrs, field.NotSupported(permissionPath.Child("resource"), permission.Resource, rbacResources))
+			}
+			if err := validateRBACPolicyValue(permission.Action, permissionPath.Child("action")); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			if err := validateRBACPolicyValue(permission.Object, permissionPath.Child("object")); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			switch permission.Effect {
+			case "", common.ArgoCDRBACEffectAllow, common.ArgoCDRBACEffectDeny:
+			default:
+				supported := []string{common.ArgoCDRBACEffectAllow, common.ArgoCDRBACEffectDeny}
+				allErrs = append(allErrs, field.NotSupported(permissionPath.Child("effect"), permission.Effect, supported))
+			}
+		}
+	}
+
+	for i, binding := range rbac.GroupBindings {
+		bindingPath := path.Child("groupBindings").Index(i)
+		if err := validateRBACPolicyValue(binding.Group, bindingPath.Child("group")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+		if len(binding.Role) == 0 {
+			allErrs = append(allErrs, field.Required(bindingPath.Child("role"), ""))
+		} else if !roles[binding.Role] {
+			allErrs = append(allErrs, field.NotFound(bindingPath.Child("role"), binding.Role))
+		}
+	}
+
+	for i, mapping := range rbac.GroupMappings {
+		mappingPath := path.Child("groupMappings").Index(i)
+		if len(mapping.Groups) == 0 && mapping.Selector == nil {
+			allErrs = append(allErrs, field.Required(mappingPath, "groups or selector must be set"))
+		}
+		for j, group := range mapping.Groups {
+			if err := validateRBACPolicyValue(group, mappingPath.Child("groups").Index(j)); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+		if len(mapping.Role) == 0 {
+			allErrs = append(allErrs, field.Required(mappingPath.Child("role"), ""))
+		} else if !roles[mapping.Role] {
+			allErrs = append(allErrs, field.NotFound(mappingPath.Child("role"), mapping.Role))
+		}
+		if mapping.Selector != nil {
+			allErrs = append(allErrs, metav1validation.ValidateLabelSelector(mapping.Selector, mappingPath.Child("selector"))...)
+		}
+	}
+
+	if rbac.PolicyConfigMapSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(rbac.PolicyConfigMapSelector, path.Child("policyConfigMapSelector"))...)
+	}
+	return allErrs
+}
+
+// isRBACResource returns true if the given value is an Argo CD resource type of the RBAC permissions.
+func isRBACResource(value string) bool {
+	for _, resource := range rbacResources {
+		if value == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRBACPolicyValue will verify that the given value can be rendered to a field of a policy CSV line.
+func validateRBACPolicyValue(value string, path *field.Path) *field.Error {
+	if len(strings.TrimSpace(value)) == 0 {
+		return field.Required(path, "")
+	}
+	if strings.ContainsAny(value, ",\n") || strings.TrimSpace(value) != value {
+		return field.Invalid(path, value, "must not contain commas or line breaks, or start or end with a space")
+	}
+	return nil
+}
+
+// validateRouteOrIngress will verify that a Route is not requested for a component on a cluster without the Route
+// API, when an Ingress is also requested.
+func validateRouteOrIngress(routeEnabled, ingressEnabled bool, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if routeEnabled && ingressEnabled {
+		allErrs = append(allErrs, field.Forbidden(path.Child("route", "enabled"),
+			"Route and Ingress may not both be enabled on a cluster without the OpenShift Route API, disable the Route"))
+	}
+	return allErrs
+}
+
+// validateTLS will verify that the private key size is supported by the private key algorithm, and that the
+// certificates generated by the operator are valid for at least an hour.
+func validateTLS(tls *argoprojv1b1.ArgoCDTLSSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateCertificateDuration(tls.CA.Duration, path.Child("ca", "duration"))...)
+	allErrs = append(allErrs, validateCertificateDuration(tls.Duration, path.Child("duration"))...)
+
+	if tls.PrivateKey == nil || tls.PrivateKey.Size == 0 {
+		return allErrs
+	}
+
+	sizes := map[string][]int{
+		common.ArgoCDTLSKeyAlgorithmRSA:   {2048, 3072, 4096},
+		common.ArgoCDTLSKeyAlgorithmECDSA: {256, 384, 521},
+	}
+
+	algorithm := tls.PrivateKey.Algorithm
+	if len(algorithm) == 0 {
+		algorithm = common.ArgoCDDefaultTLSKeyAlgorithm
+	}
+
+	if _, ok := sizes[algorithm]; !ok {
+		return allErrs // The size is ignored for the algorithm
+	}
+
+	supported := []string{}
+	for _, size := range sizes[algorithm] {
+		if tls.PrivateKey.Size == size {
+			return allErrs
+		}
+		supported = append(supported, strconv.Itoa(size))
+	}
+	allErrs = append(allErrs, field.NotSupported(path.Child("privateKey", "size"), tls.PrivateKey.Size, supported))
+	return allErrs
+}
+
+// validateRepoAutoTLS will verify that the given AutoTLS method for the repo server is supported.
+func validateRepoAutoTLS(autoTLS string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch autoTLS {
+	case "", common.ArgoCDRepoServerAutoTLSOpenShift, common.ArgoCDRepoServerAutoTLSOperator:
+		return allErrs
+	}
+	supported := []string{common.ArgoCDRepoServerAutoTLSOpenShift, common.ArgoCDRepoServerAutoTLSOperator}
+	allErrs = append(allErrs, field.NotSupported(path, autoTLS, supported))
+	return allErrs
+}
+
+// validateHA will verify that the given Redis HA topology can elect a new Redis master when a replica is lost, and
+// that the Sentinel quorum can be reached by the replicas.
+func validateHA(ha *argoprojv1b1.ArgoCDHASpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	replicas := common.ArgoCDDefaultRedisHAReplicas
+	if ha.Replicas != nil {
+		replicas = *ha.Replicas
+		if replicas < common.ArgoCDDefaultRedisHAReplicas {
+			allErrs = append(allErrs, field.Invalid(path.Child("replicas"), replicas,
+				fmt.Sprintf("must be at least %d", common.ArgoCDDefaultRedisHAReplicas)))
+		}
+	}
+
+	if ha.SentinelQuorum != nil && (*ha.SentinelQuorum < 1 || *ha.SentinelQuorum > replicas) {
+		allErrs = append(allErrs, field.Invalid(path.Child("sentinelQuorum"), *ha.SentinelQuorum,
+			fmt.Sprintf("must be between 1 and the number of replicas (%d)", replicas)))
+	}
+
+	if ha.RedisProxyReplicas != nil && *ha.RedisProxyReplicas < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("redisProxyReplicas"), *ha.RedisProxyReplicas, "must be at least 1"))
+	}
+
+	if ha.ComponentReplicas != nil && *ha.ComponentReplicas < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("componentReplicas"), *ha.ComponentReplicas, "must be at least 1"))
+	}
+	return allErrs
+}
+
+// validateRedisTLS will verify that the Redis and Argo CD versions are set to versions that support TLS when it is
+// enabled for Redis, the default versions do not. A version that is not a version number, e.g. a digest, is accepted.
+func validateRedisTLS(cr *argoprojv1b1.ArgoCD, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !cr.Spec.Redis.EnableTLS {
+		return allErrs
+	}
+
+	// An external Redis is not deployed by the operator, only the Argo CD components need to support TLS.
+	if cr.Spec.Redis.External == nil {
+		allErrs = append(allErrs, validateMinimumVersion(cr.Spec.Redis.Version, redisTLSMinimumRedisVersion, path.Child("redis", "version"))...)
+	}
+	allErrs = append(allErrs, validateMinimumVersion(cr.Spec.Version, redisTLSMinimumArgoCDVersion, path.Child("version"))...)
+	return allErrs
+}
+
+// validateMinimumVersion will verify that the given image tag is set, and that it is at least the given minimum
+// version when it is a version number.
+func validateMinimumVersion(tag string, minimum string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(tag) == 0 {
+		allErrs = append(allErrs, field.Required(path, fmt.Sprintf("must be set to %s or later when Redis TLS is enabled", minimum)))
+		return allErrs
+	}
+
+	if v, err := version.ParseGeneric(tag); err == nil && !v.AtLeast(version.MustParseGeneric(minimum)) {
+		allErrs = append(allErrs, field.Invalid(path, tag, fmt.Sprintf("must be %s or later when Redis TLS is enabled", minimum)))
+	}
+	return allErrs
+}
+
+// validateDexOpenShiftOAuth will verify that the CA bundle for the OpenShift Dex connector is supported, and that the
+// issuer is an HTTPS URL, Dex verifies the TLS certificate of the issuer.
+func validateDexOpenShiftOAuth(dex *argoprojv1b1.ArgoCDDexSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch dex.OpenShiftOAuthCA {
+	case "", common.ArgoCDDexOpenShiftCAServiceAccount, common.ArgoCDDexOpenShiftCAServiceCA:
+	default:
+		supported := []string{common.ArgoCDDexOpenShiftCAServiceAccount, common.ArgoCDDexOpenShiftCAServiceCA}
+		allErrs = append(allErrs, field.NotSupported(path.Child("openShiftOAuthCA"), dex.OpenShiftOAuthCA, supported))
+	}
+
+	if len(dex.OpenShiftOAuthIssuer) > 0 {
+		if u, err := url.Parse(dex.OpenShiftOAuthIssuer); err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("openShiftOAuthIssuer"), dex.OpenShiftOAuthIssuer, "must be an https URL"))
+		}
+	}
+	return allErrs
+}
+
+// validateDexConnectors will verify that the typed Dex connectors have IDs that are unique across all connectors, do
+// not take the ID of the OpenShift connector, and can be used in the keys of the argocd-secret Secret, and exactly one
+// connector type with its required options.
+func validateDexConnectors(dex *argoprojv1b1.ArgoCDDexSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	ids := make(map[string]bool)
+	if dex.Config != nil {
+		for _, connector := range dex.Config.Connectors {
+			ids[connector.ID] = true
+		}
+	}
+	openShiftConnector := dex.OpenShiftOAuth && len(ids) == 0
+
+	for i, connector := range dex.Connectors {
+		connectorPath := path.Child("connectors").Index(i)
+		switch {
+		case len(connector.ID) == 0:
+			allErrs = append(allErrs, field.Required(connectorPath.Child("id"), ""))
+		case openShiftConnector && connector.ID == common.ArgoCDDexOpenShiftConnectorID:
+			allErrs = append(allErrs, field.Invalid(connectorPath.Child("id"), connector.ID, "is reserved for the OpenShift connector when openShiftOAuth is enabled"))
+		case ids[connector.ID]:
+			allErrs = append(allErrs, field.Duplicate(connectorPath.Child("id"), connector.ID))
+		default:
+			for _, msg := range k8svalidation.IsDNS1123Label(connector.ID) {
+				allErrs = append(allErrs, field.Invalid(connectorPath.Child("id"), connector.ID, msg))
+			}
+		}
+		ids[connector.ID] = true
+
+		if len(connector.Name) == 0 {
+			allErrs = append(allErrs, field.Required(connectorPath.Child("name"), ""))
+		}
+		allErrs = append(allErrs, validateDexConnectorType(&dex.Connectors[i], connectorPath)...)
+	}
+	return allErrs
+}
+
+// validateDexConnectorType will verify that exactly one connector type is set on the given typed Dex connector, with
+// its required options.
+func validateDexConnectorType(connector *argoprojv1b1.ArgoCDDexConnectorSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	types := []string{}
+
+	if c := connector.GitHub; c != nil {
+		types = append(types, "github")
+		allErrs = append(allErrs, validateDexClient(c.ClientID, &c.ClientSecretRef, path.Child("github"))...)
+	}
+	if c := connector.GitLab; c != nil {
+		types = append(types, "gitlab")
+		allErrs = append(allErrs, validateDexClient(c.ClientID, &c.ClientSecretRef, path.Child("gitlab"))...)
+	}
+	if c := connector.LDAP; c != nil {
+		types = append(types, "ldap")
+		ldapPath := path.Child("ldap")
+		if len(c.Host) == 0 {
+			allErrs = append(allErrs, field.Required(ldapPath.Child("host"), ""))
+		}
+		if c.BindPasswordRef != nil {
+			allErrs = append(allErrs, validateSecretKeySelector(c.BindPasswordRef, ldapPath.Child("bindPasswordRef"))...)
+		}
+		if len(c.UserSearch.BaseDN) == 0 {
+			allErrs = append(allErrs, field.Required(ldapPath.Child("userSearch", "baseDN"), ""))
+		}
+		if len(c.UserSearch.Username) == 0 {
+			allErrs = append(allErrs, field.Required(ldapPath.Child("userSearch", "username"), ""))
+		}
+	}
+	if c := connector.Microsoft; c != nil {
+		types = append(types, "microsoft")
+		allErrs = append(allErrs, validateDexClient(c.ClientID, &c.ClientSecretRef, path.Child("microsoft"))...)
+	}
+	if c := connector.OIDC; c != nil {
+		types = append(types, "oidc")
+		allErrs = append(allErrs, validateDexClient(c.ClientID, &c.ClientSecretRef, path.Child("oidc"))...)
+		if len(c.Issuer) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("oidc", "issuer"), ""))
+		}
+	}
+	if c := connector.SAML; c != nil {
+		types = append(types, "saml")
+		if len(c.SSOURL) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("saml", "ssoURL"), ""))
+		}
+	}
+
+	switch {
+	case len(types) == 0:
+		allErrs = append(allErrs, field.Required(path, "one of github, gitlab, ldap, microsoft, oidc or saml must be set"))
+	case len(types) > 1:
+		allErrs = append(allErrs, field.Forbidden(path.Child(types[1]), fmt.Sprintf("may not be set together with %s", types[0])))
+	}
+	return allErrs
+}
+
+// validateDexClient will verify that the given OAuth client ID and secret reference of a Dex connector are set.
+func validateDexClient(clientID string, secretRef *corev1.SecretKeySelector, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(clientID) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("clientID"), ""))
+	}
+	return append(allErrs, validateSecretKeySelector(secretRef, path.Child("clientSecretRef"))...)
+}
+
+// validateSecretKeySelector will verify that the name and key of the given Secret key reference are set.
+func validateSecretKeySelector(ref *corev1.SecretKeySelector, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(ref.Name) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+	}
+	if len(ref.Key) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("key"), ""))
+	}
+	return allErrs
+}
+
+// validatePodDisruptionBudget will verify that the given PodDisruptionBudget options set at most one budget.
+func validatePodDisruptionBudget(budget *argoprojv1b1.ArgoCDPodDisruptionBudgetSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if budget != nil && budget.MaxUnavailable != nil && budget.MinAvailable != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("minAvailable"), "may not be set together with maxUnavailable"))
+	}
+	return allErrs
+}
+
+// networkPolicyComponents are the components that accept extra NetworkPolicy peers.
+var networkPolicyComponents = []string{
+	"application-controller",
+	"dex-server",
+	"redis",
+	"redis-ha",
+	"repo-server",
+	"server",
+}
+
+// validateNetworkPolicy will verify that the extra peers of the given NetworkPolicy options are keyed by a supported
+// component.
+func validateNetworkPolicy(np *argoprojv1b1.ArgoCDNetworkPolicySpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	keys := make([]string, 0, len(np.ExtraPeers))
+	for key := range np.ExtraPeers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		supported := false
+		for _, component := range networkPolicyComponents {
+			if key == component {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			allErrs = append(allErrs, field.NotSupported(path.Child("extraPeers"), key, networkPolicyComponents))
+		}
+	}
+	return allErrs
+}
+
+// validateUsers will verify that the given local user accounts have unique names that can be used for their user
+// Secrets, and only supported capabilities. The admin account is managed with the admin options.
+func validateUsers(users []argoprojv1b1.ArgoCDUserSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	supported := []string{common.ArgoCDUserCapabilityAPIKey, common.ArgoCDUserCapabilityLogin}
+	names := make(map[string]bool)
+
+	for i, user := range users {
+		userPath := path.Index(i)
+		switch {
+		case user.Name == "admin":
+			allErrs = append(allErrs, field.Invalid(userPath.Child("name"), user.Name, "is reserved for the admin account"))
+		case names[user.Name]:
+			allErrs = append(allErrs, field.Duplicate(userPath.Child("name"), user.Name))
+		default:
+			for _, msg := range k8svalidation.IsDNS1123Label(user.Name) {
+				allErrs = append(allErrs, field.Invalid(userPath.Child("name"), user.Name, msg))
+			}
+		}
+		names[user.Name] = true
+
+		apiKey := len(user.Capabilities) == 0
+		for j, capability := range user.Capabilities {
+			switch capability {
+			case common.ArgoCDUserCapabilityAPIKey:
+				apiKey = true
+			case common.ArgoCDUserCapabilityLogin:
+			default:
+				allErrs = append(allErrs, field.NotSupported(userPath.Child("capabilities").Index(j), capability, supported))
+			}
+		}
+
+		if user.Token != nil && !apiKey {
+			allErrs = append(allErrs, field.Forbidden(userPath.Child("token"), "requires the apiKey capability"))
+		}
+	}
+	return allErrs
+}
+
+// validateRepositories will verify that the given repositories, or credential templates, have unique URLs, a supported
+// type and complete credentials. Helm repositories must be named, the name and project are only supported for
+// repositories.
+func validateRepositories(repos []argoprojv1b1.ArgoCDRepositorySpec, templates bool, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	urls := make(map[string]bool)
+
+	for i := range repos {
+		repo := &repos[i]
+		repoPath := path.Index(i)
+		if len(repo.URL) == 0 {
+			allErrs = append(allErrs, field.Required(repoPath.Child("url"), ""))
+		} else if urls[repo.URL] {
+			allErrs = append(allErrs, field.Duplicate(repoPath.Child("url"), repo.URL))
+		}
+		urls[repo.URL] = true
+
+		switch repo.Type {
+		case "", common.ArgoCDRepositoryTypeGit, common.ArgoCDRepositoryTypeHelm:
+		default:
+			supported := []string{common.ArgoCDRepositoryTypeGit, common.ArgoCDRepositoryTypeHelm}
+			allErrs = append(allErrs, field.NotSupported(repoPath.Child("type"), repo.Type, supported))
+		}
+
+		if templates {
+			if len(repo.Name) > 0 {
+				allErrs = append(allErrs, field.Forbidden(repoPath.Child("name"), "is only supported for repositories"))
+			}
+			if len(repo.Project) > 0 {
+				allErrs = append(allErrs, field.Forbidden(repoPath.Child("project"), "is only supported for repositories"))
+			}
+		} else if repo.Type == common.ArgoCDRepositoryTypeHelm && len(repo.Name) == 0 {
+			allErrs = append(allErrs, field.Required(repoPath.Child("name"), "is required for helm repositories"))
+		}
+
+		if repo.UsernameSecret != nil && repo.PasswordSecret == nil {
+			allErrs = append(allErrs, field.Required(repoPath.Child("passwordSecret"), "must be set with usernameSecret"))
+		}
+		if repo.PasswordSecret != nil && repo.UsernameSecret == nil {
+			allErrs = append(allErrs, field.Required(repoPath.Child("usernameSecret"), "must be set with passwordSecret"))
+		}
+		if repo.TLSClientCertDataSecret != nil && repo.TLSClientCertKeySecret == nil {
+			allErrs = append(allErrs, field.Required(repoPath.Child("tlsClientCertKeySecret"), "must be set with tlsClientCertDataSecret"))
+		}
+		if repo.TLSClientCertKeySecret != nil && repo.TLSClientCertDataSecret == nil {
+			allErrs = append(allErrs, field.Required(repoPath.Child("tlsClientCertDataSecret"), "must be set with tlsClientCertKeySecret"))
+		}
+
+		refs := []struct {
+			name string
+			ref  *corev1.SecretKeySelector
+		}{
+			{"usernameSecret", repo.UsernameSecret},
+			{"passwordSecret", repo.PasswordSecret},
+			{"sshPrivateKeySecret", repo.SSHPrivateKeySecret},
+			{"tlsClientCertDataSecret", repo.TLSClientCertDataSecret},
+			{"tlsClientCertKeySecret", repo.TLSClientCertKeySecret},
+		}
+		for _, secret := range refs {
+			if secret.ref != nil {
+				allErrs = append(allErrs, validateSecretKeySelector(secret.ref, repoPath.Child(secret.name))...)
+			}
+		}
+
+		if app := repo.GitHubApp; app != nil {
+			appPath := repoPath.Child("githubApp")
+			if app.ID <= 0 {
+				allErrs = append(allErrs, field.Invalid(appPath.Child("id"), app.ID, "must be greater than 0"))
+			}
+			if app.InstallationID <= 0 {
+				allErrs = append(allErrs, field.Invalid(appPath.Child("installationId"), app.InstallationID, "must be greater than 0"))
+			}
+			allErrs = append(allErrs, validateSecretKeySelector(&app.PrivateKeySecret, appPath.Child("privateKeySecret"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateConfigMapRepositories will verify that the given repositories, or credential templates, written to the
+// argocd-cm ConfigMap do not set the fields that are only supported for the repositories rendered to Secrets.
+func validateConfigMapRepositories(repos []argoprojv1b1.ArgoCDRepositorySpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i := range repos {
+		repoPath := path.Index(i)
+		if repos[i].GitHubApp != nil {
+			allErrs = append(allErrs, field.Forbidden(repoPath.Child("githubApp"), "is only supported for repositories and repositoryCredentialTemplates"))
+		}
+		if len(repos[i].Project) > 0 {
+			allErrs = append(allErrs, field.Forbidden(repoPath.Child("project"), "is only supported for repositories"))
+		}
+	}
+	return allErrs
+}
+
+// validateCertificateDuration will verify that the given certificate validity is at least an hour.
+func validateCertificateDuration(duration *metav1.Duration, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if duration != nil && duration.Duration < time.Hour {
+		allErrs = append(allErrs, field.Invalid(path, duration.Duration.String(), "must be at least 1h"))
+	}
+	return allErrs
+}
+
+// validateRotationInterval will verify that the given rotation interval is at least an hour.
+func validateRotationInterval(interval *metav1.Duration, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if interval != nil && interval.Duration < time.Hour {
+		allErrs = append(allErrs, field.Invalid(path, interval.Duration.String(), "must be at least 1h"))
+	}
+	return allErrs
+}
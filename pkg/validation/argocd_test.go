@@ -0,0 +1,494 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+func makeTestArgoCD(opts ...func(*argoprojv1b1.ArgoCD)) *argoprojv1b1.ArgoCD {
+	a := &argoprojv1b1.ArgoCD{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd",
+			Namespace: "argocd",
+		},
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+func TestValidateArgoCD_valid(t *testing.T) {
+	policy := `# Admins
+p, role:org-admin, applications, *, */*, allow
+p, role:org-admin, clusters, get, *, allow
+g, "my-org:team-alpha", role:org-admin
+`
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.RBAC.Policy = &policy
+		a.Spec.Server.Route.Enabled = true
+		a.Spec.Server.Ingress.Enabled = true
+	})
+
+	assert.NilError(t, ValidateArgoCD(cr, true))
+	assert.NilError(t, ValidateArgoCD(makeTestArgoCD(), false))
+}
+
+func TestValidateArgoCD_adminPassword(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.AdminPassword = &argoprojv1b1.ArgoCDAdminPasswordSpec{
+			RotationInterval: &metav1.Duration{Duration: 30 * 24 * time.Hour},
+		}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.AdminPassword.RotationInterval.Duration = time.Minute
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.adminPassword.rotationInterval: Invalid value: \"1m0s\": must be at least 1h")
+
+	cr.Spec.AdminPassword.SecretRef = &corev1.SecretKeySelector{}
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.adminPassword.secretRef.name: Required value")
+	assert.ErrorContains(t, err, "spec.adminPassword.secretRef.key: Required value")
+	assert.ErrorContains(t, err, "spec.adminPassword.rotationInterval: Forbidden: may not be set together with secretRef")
+}
+
+func TestValidateArgoCD_serverSecretKey(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Server.SecretKey.RotationInterval = &metav1.Duration{Duration: 90 * 24 * time.Hour}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.Server.SecretKey.RotationInterval.Duration = 30 * time.Minute
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.server.secretKey.rotationInterval: Invalid value: \"30m0s\": must be at least 1h")
+}
+
+func TestValidateArgoCD_rbacPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{
+			name:   "unknown line type",
+			policy: "x, role:admin, applications, *, */*, allow",
+			want:   "line 1: policy lines must start with 'p' or 'g', found 'x'",
+		},
+		{
+			name:   "policy rule without effect",
+			policy: "g, admins, role:admin\np, role:admin, applications, *, */*",
+			want:   "line 2: policy rules must be in the form 'p, subject, resource, action, object, effect', found 5 fields",
+		},
+		{
+			name:   "invalid effect",
+			policy: "p, role:admin, applications, *, */*, permit",
+			want:   "line 1: policy effect must be 'allow' or 'deny', found 'permit'",
+		},
+		{
+			name:   "role binding with too many fields",
+			policy: "g, admins, role:admin, role:readonly",
+			want:   "line 1: role bindings must be in the form 'g, subject, inherited-subject', found 4 fields",
+		},
+		{
+			name:   "empty field",
+			policy: "g, , role:admin",
+			want:   "line 1: policy fields must not be empty",
+		},
+		{
+			name:   "invalid quoting",
+			policy: `g, "admins, role:admin`,
+			want:   `extraneous or missing " in quoted-field`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+				a.Spec.RBAC.Policy = &test.policy
+			})
+
+			err := ValidateArgoCD(cr, false)
+			assert.ErrorContains(t, err, "spec.rbac.policy")
+			assert.ErrorContains(t, err, test.want)
+		})
+	}
+}
+
+func TestValidateArgoCD_rbacRoles(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.RBAC.Roles = []argoprojv1b1.ArgoCDRBACRoleSpec{{
+			Name:        "deployer",
+			Permissions: []argoprojv1b1.ArgoCDRBACPermissionSpec{{Resource: "applications", Action: "sync", Object: "*/*"}},
+		}}
+		a.Spec.RBAC.GroupBindings = []argoprojv1b1.ArgoCDRBACGroupBindingSpec{
+			{Group: "team-a", Role: "deployer"},
+			{Group: "auditors", Role: "readonly"},
+		}
+		a.Spec.RBAC.GroupMappings = []argoprojv1b1.ArgoCDRBACGroupMappingSpec{
+			{Groups: []string{"cluster-admins"}, Role: "admin"},
+			{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}, Role: "deployer"},
+		}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.RBAC.Roles = []argoprojv1b1.ArgoCDRBACRoleSpec{
+		{
+			Name: "deployer",
+			Permissions: []argoprojv1b1.ArgoCDRBACPermissionSpec{
+				{Resource: "apps", Action: "sync", Object: "team-a/*, team-b/*", Effect: "maybe"},
+			},
+		},
+		{Name: "deployer"},
+		{Name: "admin"},
+	}
+	cr.Spec.RBAC.GroupBindings = []argoprojv1b1.ArgoCDRBACGroupBindingSpec{{Group: "team-b", Role: "operator"}}
+	cr.Spec.RBAC.GroupMappings = []argoprojv1b1.ArgoCDRBACGroupMappingSpec{
+		{Role: "admin"},
+		{Groups: []string{"team-c "}, Role: "operator"},
+	}
+	cr.Spec.RBAC.PolicyConfigMapSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Maybe"}},
+	}
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.rbac.roles[0].permissions[0].resource: Unsupported value: \"apps\"")
+	assert.ErrorContains(t, err, "spec.rbac.roles[0].permissions[0].object: Invalid value: \"team-a/*, team-b/*\": must not contain commas")
+	assert.ErrorContains(t, err, "spec.rbac.roles[0].permissions[0].effect: Unsupported value: \"maybe\"")
+	assert.ErrorContains(t, err, "spec.rbac.roles[1].name: Duplicate value: \"deployer\"")
+	assert.ErrorContains(t, err, "spec.rbac.roles[2].name: Invalid value: \"admin\": is reserved for a built-in role")
+	assert.ErrorContains(t, err, "spec.rbac.groupBindings[0].role: Not found: \"operator\"")
+	assert.ErrorContains(t, err, "spec.rbac.groupMappings[0]: Required value: groups or selector must be set")
+	assert.ErrorContains(t, err, "spec.rbac.groupMappings[1].groups[0]: Invalid value: \"team-c \"")
+	assert.ErrorContains(t, err, "spec.rbac.groupMappings[1].role: Not found: \"operator\"")
+	assert.ErrorContains(t, err, "spec.rbac.policyConfigMapSelector.matchExpressions[0].operator: Invalid value: \"Maybe\"")
+}
+
+func TestValidateRBACPolicy(t *testing.T) {
+	assert.NilError(t, ValidateRBACPolicy("# team-a\ng, team-a, role:readonly\n"))
+	assert.ErrorContains(t, ValidateRBACPolicy("p, role:team-a, applications, *"), "policy.csv: Invalid value")
+}
+
+func TestValidateArgoCD_stringFields(t *testing.T) {
+	alpha := &argoprojv1a1.ArgoCD{
+		Spec: argoprojv1a1.ArgoCDSpec{
+			ResourceExclusions: "testing: testing",
+		},
+	}
+	cr := &argoprojv1b1.ArgoCD{}
+	assert.NilError(t, alpha.ConvertTo(cr))
+
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.resourceExclusions: Invalid value: \"testing: testing\": unable to parse YAML")
+	assert.NilError(t, ValidateArgoCDSpec(cr, false))
+}
+
+func TestValidateArgoCD_routeAndIngress(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Server.Route.Enabled = true
+		a.Spec.Server.Ingress.Enabled = true
+		a.Spec.Grafana.Route.Enabled = true
+	})
+
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.server.route.enabled: Forbidden")
+	assert.Assert(t, !strings.Contains(err.Error(), "spec.grafana"))
+}
+
+func TestValidateArgoCD_tls(t *testing.T) {
+	valid := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.TLS.Duration = &metav1.Duration{Duration: 90 * 24 * time.Hour}
+		a.Spec.TLS.PrivateKey = &argoprojv1b1.ArgoCDTLSPrivateKeySpec{
+			Algorithm: "ECDSA",
+			Size:      256,
+		}
+	})
+	assert.NilError(t, ValidateArgoCD(valid, false))
+
+	ed25519 := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.TLS.PrivateKey = &argoprojv1b1.ArgoCDTLSPrivateKeySpec{
+			Algorithm: "Ed25519",
+			Size:      4096,
+		}
+	})
+	assert.NilError(t, ValidateArgoCD(ed25519, false))
+
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.TLS.CA.Duration = &metav1.Duration{Duration: time.Minute}
+		a.Spec.TLS.PrivateKey = &argoprojv1b1.ArgoCDTLSPrivateKeySpec{
+			Size: 1024,
+		}
+	})
+
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.tls.ca.duration: Invalid value: \"1m0s\": must be at least 1h")
+	assert.ErrorContains(t, err, "spec.tls.privateKey.size: Unsupported value: 1024: supported values: \"2048\", \"3072\", \"4096\"")
+}
+
+func TestValidateArgoCD_repoAutoTLS(t *testing.T) {
+	for _, autoTLS := range []string{"", "openshift", "operator"} {
+		cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+			a.Spec.Repo.AutoTLS = autoTLS
+		})
+		assert.NilError(t, ValidateArgoCD(cr, false))
+	}
+
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Repo.AutoTLS = "cert-manager"
+	})
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.repo.autotls: Unsupported value: \"cert-manager\"")
+}
+
+func TestValidateArgoCD_ha(t *testing.T) {
+	replicas, quorum, proxies := int32(5), int32(3), int32(2)
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.HA.Replicas = &replicas
+		a.Spec.HA.SentinelQuorum = &quorum
+		a.Spec.HA.RedisProxyReplicas = &proxies
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	replicas, quorum, proxies = 2, 3, 0
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.ha.replicas: Invalid value: 2: must be at least 3")
+	assert.ErrorContains(t, err, "spec.ha.sentinelQuorum: Invalid value: 3: must be between 1 and the number of replicas (2)")
+	assert.ErrorContains(t, err, "spec.ha.redisProxyReplicas: Invalid value: 0: must be at least 1")
+
+	// The quorum is checked against the default replica count.
+	cr.Spec.HA.Replicas = nil
+	cr.Spec.HA.RedisProxyReplicas = nil
+	quorum = 4
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.ha.sentinelQuorum: Invalid value: 4: must be between 1 and the number of replicas (3)")
+}
+
+func TestValidateArgoCD_redisTLS(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Redis.EnableTLS = true
+	})
+
+	// The default Redis and Argo CD versions do not support TLS.
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.redis.version: Required value: must be set to 6.0.0 or later when Redis TLS is enabled")
+	assert.ErrorContains(t, err, "spec.version: Required value: must be set to 2.3.0 or later when Redis TLS is enabled")
+
+	cr.Spec.Redis.Version = "5.0.6-alpine"
+	cr.Spec.Version = "v2.0.0"
+	err = ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.redis.version: Invalid value: \"5.0.6-alpine\": must be 6.0.0 or later when Redis TLS is enabled")
+	assert.ErrorContains(t, err, "spec.version: Invalid value: \"v2.0.0\": must be 2.3.0 or later when Redis TLS is enabled")
+
+	cr.Spec.Redis.Version = "6.2.4-alpine"
+	cr.Spec.Version = "v2.3.0"
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	// Digests can't be compared, they are accepted.
+	cr.Spec.Redis.Version = "sha256:4be7fdb131e76a6c6231e820c60b8b12938cf1ff3d437da4871b9b2440f4e385"
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	// Only the Argo CD version is checked for an external Redis.
+	cr.Spec.Redis.Version = ""
+	cr.Spec.Redis.External = &argoprojv1b1.ArgoCDRedisExternalSpec{Host: "redis.example.com"}
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.Redis.EnableTLS = false
+	cr.Spec.Version = ""
+	assert.NilError(t, ValidateArgoCD(cr, false))
+}
+
+func TestValidateArgoCD_dexConnectors(t *testing.T) {
+	clientSecretRef := corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "dex-secrets"},
+		Key:                  "clientSecret",
+	}
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Dex.Connectors = []argoprojv1b1.ArgoCDDexConnectorSpec{
+			{
+				ID:     "github",
+				Name:   "GitHub",
+				GitHub: &argoprojv1b1.ArgoCDDexGitHubConnectorSpec{ClientID: "client-id", ClientSecretRef: clientSecretRef},
+			},
+			{
+				ID:   "ldap",
+				Name: "LDAP",
+				LDAP: &argoprojv1b1.ArgoCDDexLDAPConnectorSpec{
+					Host:       "ldap.example.com:636",
+					UserSearch: argoprojv1b1.ArgoCDDexLDAPUserSearchSpec{BaseDN: "ou=users,dc=example,dc=com", Username: "uid"},
+				},
+			},
+		}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.Dex.Config = &argoprojv1b1.ArgoCDDexConfig{
+		Connectors: []argoprojv1b1.ArgoCDDexConnector{{ID: "github", Name: "GitHub", Type: "github"}},
+	}
+	cr.Spec.Dex.Connectors = []argoprojv1b1.ArgoCDDexConnectorSpec{
+		{ID: "github", Name: "GitHub", OIDC: &argoprojv1b1.ArgoCDDexOIDCConnectorSpec{}},
+		{ID: "Okta_SSO", Name: "Okta"},
+		{
+			ID:        "azure",
+			Name:      "Azure",
+			Microsoft: &argoprojv1b1.ArgoCDDexMicrosoftConnectorSpec{ClientID: "client-id", ClientSecretRef: clientSecretRef},
+			SAML:      &argoprojv1b1.ArgoCDDexSAMLConnectorSpec{SSOURL: "https://login.example.com/saml"},
+		},
+	}
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.dex.connectors[0].id: Duplicate value: \"github\"")
+	assert.ErrorContains(t, err, "spec.dex.connectors[0].oidc.clientID: Required value")
+	assert.ErrorContains(t, err, "spec.dex.connectors[0].oidc.clientSecretRef.name: Required value")
+	assert.ErrorContains(t, err, "spec.dex.connectors[0].oidc.issuer: Required value")
+	assert.ErrorContains(t, err, "spec.dex.connectors[1].id: Invalid value: \"Okta_SSO\"")
+	assert.ErrorContains(t, err, "spec.dex.connectors[1]: Required value: one of github, gitlab, ldap, microsoft, oidc or saml must be set")
+	assert.ErrorContains(t, err, "spec.dex.connectors[2].saml: Forbidden: may not be set together with microsoft")
+}
+
+func TestValidateArgoCD_dexOpenShiftOAuth(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Dex.OpenShiftOAuth = true
+		a.Spec.Dex.OpenShiftOAuthCA = "ServiceCA"
+		a.Spec.Dex.OpenShiftOAuthIssuer = "https://api.example.com:6443"
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.Dex.OpenShiftOAuthCA = "None"
+	cr.Spec.Dex.OpenShiftOAuthIssuer = "http://api.example.com:6443"
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.dex.openShiftOAuthCA: Unsupported value: \"None\"")
+	assert.ErrorContains(t, err, "spec.dex.openShiftOAuthIssuer: Invalid value: \"http://api.example.com:6443\": must be an https URL")
+
+	// The typed connectors are used together with the OpenShift connector, which reserves its ID.
+	cr.Spec.Dex.OpenShiftOAuthCA = "ServiceCA"
+	cr.Spec.Dex.OpenShiftOAuthIssuer = "https://api.example.com:6443"
+	cr.Spec.Dex.Connectors = []argoprojv1b1.ArgoCDDexConnectorSpec{{
+		ID:   "openshift",
+		Name: "LDAP",
+		LDAP: &argoprojv1b1.ArgoCDDexLDAPConnectorSpec{
+			Host:       "ldap.example.com:636",
+			UserSearch: argoprojv1b1.ArgoCDDexLDAPUserSearchSpec{BaseDN: "ou=users,dc=example,dc=com", Username: "uid"},
+		},
+	}}
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.dex.connectors[0].id: Invalid value: \"openshift\": is reserved for the OpenShift connector when openShiftOAuth is enabled")
+
+	cr.Spec.Dex.Connectors[0].ID = "ldap"
+	assert.NilError(t, ValidateArgoCD(cr, false))
+}
+
+func TestValidateArgoCD_podDisruptionBudget(t *testing.T) {
+	maxUnavailable, minAvailable := intstr.FromInt(1), intstr.FromString("50%")
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Server.PodDisruptionBudget = &argoprojv1b1.ArgoCDPodDisruptionBudgetSpec{MinAvailable: &minAvailable}
+		a.Spec.ApplicationSet = &argoprojv1b1.ArgoCDApplicationSet{
+			PodDisruptionBudget: &argoprojv1b1.ArgoCDPodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable},
+		}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.Server.PodDisruptionBudget.MaxUnavailable = &maxUnavailable
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.server.podDisruptionBudget.minAvailable: Forbidden: may not be set together with maxUnavailable")
+}
+
+func TestValidateArgoCD_networkPolicy(t *testing.T) {
+	peers := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "monitoring"}},
+	}}
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.NetworkPolicy.Enabled = true
+		a.Spec.NetworkPolicy.ExtraPeers = map[string][]networkingv1.NetworkPolicyPeer{"repo-server": peers}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.NetworkPolicy.ExtraPeers["grafana"] = peers
+	assert.ErrorContains(t, ValidateArgoCD(cr, false), "spec.networkPolicy.extraPeers: Unsupported value: \"grafana\"")
+}
+
+func TestValidateArgoCD_users(t *testing.T) {
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Users = []argoprojv1b1.ArgoCDUserSpec{
+			{Name: "ci", Token: &argoprojv1b1.ArgoCDUserTokenSpec{}},
+			{Name: "alice", Capabilities: []string{"login", "apiKey"}, GeneratePassword: true},
+		}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.Users = []argoprojv1b1.ArgoCDUserSpec{
+		{Name: "admin"},
+		{Name: "ci"},
+		{Name: "ci"},
+		{Name: "CI_Bot"},
+		{Name: "alice", Capabilities: []string{"login", "sso"}, Token: &argoprojv1b1.ArgoCDUserTokenSpec{}},
+	}
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.users[0].name: Invalid value: \"admin\": is reserved for the admin account")
+	assert.ErrorContains(t, err, "spec.users[2].name: Duplicate value: \"ci\"")
+	assert.ErrorContains(t, err, "spec.users[3].name: Invalid value: \"CI_Bot\"")
+	assert.ErrorContains(t, err, "spec.users[4].capabilities[1]: Unsupported value: \"sso\"")
+	assert.ErrorContains(t, err, "spec.users[4].token: Forbidden: requires the apiKey capability")
+}
+
+func TestValidateArgoCD_repositories(t *testing.T) {
+	ref := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "git-creds"}, Key: "password"}
+	cr := makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.Repositories = []argoprojv1b1.ArgoCDRepositorySpec{
+			{URL: "https://github.com/argoproj/argocd-example-apps.git", Project: "team-a", UsernameSecret: ref, PasswordSecret: ref},
+			{URL: "https://argoproj.github.io/argo-helm", Type: "helm", Name: "argo"},
+		}
+		a.Spec.RepositoryCredentialTemplates = []argoprojv1b1.ArgoCDRepositorySpec{{
+			URL:       "https://github.com/argoproj",
+			GitHubApp: &argoprojv1b1.ArgoCDRepositoryGitHubAppSpec{ID: 1, InstallationID: 2, PrivateKeySecret: *ref},
+		}}
+	})
+	assert.NilError(t, ValidateArgoCD(cr, false))
+
+	cr.Spec.Repositories = []argoprojv1b1.ArgoCDRepositorySpec{
+		{URL: "https://argoproj.github.io/argo-helm", Type: "helm"},
+		{URL: "https://argoproj.github.io/argo-helm", Type: "oci", UsernameSecret: ref},
+		{URL: "git@github.com:argoproj/argo-cd.git", SSHPrivateKeySecret: &corev1.SecretKeySelector{}},
+	}
+	cr.Spec.RepositoryCredentialTemplates = []argoprojv1b1.ArgoCDRepositorySpec{{
+		URL:       "https://github.com/argoproj",
+		Project:   "team-a",
+		GitHubApp: &argoprojv1b1.ArgoCDRepositoryGitHubAppSpec{PrivateKeySecret: *ref},
+	}}
+	err := ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.repositories[0].name: Required value: is required for helm repositories")
+	assert.ErrorContains(t, err, "spec.repositories[1].url: Duplicate value: \"https://argoproj.github.io/argo-helm\"")
+	assert.ErrorContains(t, err, "spec.repositories[1].type: Unsupported value: \"oci\"")
+	assert.ErrorContains(t, err, "spec.repositories[1].passwordSecret: Required value: must be set with usernameSecret")
+	assert.ErrorContains(t, err, "spec.repositories[2].sshPrivateKeySecret.name: Required value")
+	assert.ErrorContains(t, err, "spec.repositoryCredentialTemplates[0].project: Forbidden: is only supported for repositories")
+	assert.ErrorContains(t, err, "spec.repositoryCredentialTemplates[0].githubApp.id: Invalid value: 0: must be greater than 0")
+
+	// The repositories written to the argocd-cm ConfigMap do not support the GitHub App and the project.
+	cr = makeTestArgoCD(func(a *argoprojv1b1.ArgoCD) {
+		a.Spec.InitialRepositories = []argoprojv1b1.ArgoCDRepositorySpec{
+			{URL: "https://github.com/argoproj/argocd-example-apps.git", Project: "team-a"},
+		}
+		a.Spec.RepositoryCredentials = []argoprojv1b1.ArgoCDRepositorySpec{{
+			URL:       "https://github.com/argoproj",
+			GitHubApp: &argoprojv1b1.ArgoCDRepositoryGitHubAppSpec{ID: 1, InstallationID: 2, PrivateKeySecret: *ref},
+		}}
+	})
+	err = ValidateArgoCD(cr, false)
+	assert.ErrorContains(t, err, "spec.initialRepositories[0].project: Forbidden: is only supported for repositories")
+	assert.ErrorContains(t, err, "spec.repositoryCredentials[0].githubApp: Forbidden: is only supported for repositories and repositoryCredentialTemplates")
+}
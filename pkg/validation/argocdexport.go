@@ -0,0 +1,65 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+// storageBackends are the supported values for the ArgoCDExport storage backend.
+var storageBackends = []string{
+	common.ArgoCDExportStorageBackendAWS,
+	common.ArgoCDExportStorageBackendAzure,
+	common.ArgoCDExportStorageBackendGCP,
+	common.ArgoCDExportStorageBackendLocal,
+}
+
+// ValidateArgoCDExport will validate the given ArgoCDExport and return an aggregate of all invalid fields, or nil
+// when the ArgoCDExport is valid.
+func ValidateArgoCDExport(cr *argoprojv1a1.ArgoCDExport) error {
+	if cr.Spec.Storage == nil {
+		return nil // The local backend is used by default.
+	}
+
+	allErrs := field.ErrorList{}
+	path := field.NewPath("spec", "storage")
+	backend := strings.ToLower(cr.Spec.Storage.Backend)
+
+	if backend != "" && !isStorageBackend(backend) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("backend"), cr.Spec.Storage.Backend, storageBackends))
+	}
+
+	if backend == common.ArgoCDExportStorageBackendAWS && cr.Spec.Storage.SecretName == "" {
+		allErrs = append(allErrs, field.Required(path.Child("secretName"),
+			"a Secret with the AWS credentials is required for the aws storage backend"))
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// isStorageBackend returns true if the given value is a supported storage backend.
+func isStorageBackend(backend string) bool {
+	for _, b := range storageBackends {
+		if backend == b {
+			return true
+		}
+	}
+	return false
+}
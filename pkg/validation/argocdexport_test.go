@@ -0,0 +1,79 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+)
+
+func makeTestArgoCDExport(storage *argoprojv1a1.ArgoCDExportStorageSpec) *argoprojv1a1.ArgoCDExport {
+	return &argoprojv1a1.ArgoCDExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd-export",
+			Namespace: "argocd",
+		},
+		Spec: argoprojv1a1.ArgoCDExportSpec{
+			Argocd:  "argocd",
+			Storage: storage,
+		},
+	}
+}
+
+func TestValidateArgoCDExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage *argoprojv1a1.ArgoCDExportStorageSpec
+		want    string
+	}{
+		{
+			name:    "default storage",
+			storage: nil,
+		},
+		{
+			name:    "local backend",
+			storage: &argoprojv1a1.ArgoCDExportStorageSpec{Backend: "local"},
+		},
+		{
+			name:    "aws backend with secret",
+			storage: &argoprojv1a1.ArgoCDExportStorageSpec{Backend: "aws", SecretName: "aws-creds"},
+		},
+		{
+			name:    "aws backend without secret",
+			storage: &argoprojv1a1.ArgoCDExportStorageSpec{Backend: "aws"},
+			want:    "spec.storage.secretName: Required value",
+		},
+		{
+			name:    "unknown backend",
+			storage: &argoprojv1a1.ArgoCDExportStorageSpec{Backend: "s3"},
+			want:    `spec.storage.backend: Unsupported value: "s3"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateArgoCDExport(makeTestArgoCDExport(test.storage))
+			if test.want == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.want)
+		})
+	}
+}
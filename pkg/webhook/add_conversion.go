@@ -0,0 +1,33 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+)
+
+func init() {
+	AddToManagerFuncs = append(AddToManagerFuncs, addConversionWebhook)
+}
+
+// addConversionWebhook registers the webhook that converts ArgoCD resources between the served API versions.
+// The conversion functions are found through the types registered with the Manager scheme.
+func addConversionWebhook(m manager.Manager) error {
+	m.GetWebhookServer().Register(common.ArgoCDConversionWebhookPath, &conversion.Webhook{})
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+	"github.com/argoproj-labs/argocd-operator/pkg/common"
+	"github.com/argoproj-labs/argocd-operator/pkg/controller/argocd"
+	"github.com/argoproj-labs/argocd-operator/pkg/validation"
+)
+
+func init() {
+	AddToManagerFuncs = append(AddToManagerFuncs, addValidatingWebhooks)
+}
+
+// addValidatingWebhooks registers the webhooks that reject invalid ArgoCD and ArgoCDExport resources.
+func addValidatingWebhooks(m manager.Manager) error {
+	m.GetWebhookServer().Register(common.ArgoCDValidatingWebhookPath, &webhook.Admission{Handler: &argoCDValidator{}})
+	m.GetWebhookServer().Register(common.ArgoCDExportValidatingWebhookPath, &webhook.Admission{Handler: &argoCDExportValidator{}})
+	return nil
+}
+
+// skipValidation returns true if an update should be allowed without validation. Resources that are being deleted,
+// or whose spec did not change, are always allowed so that resources created before the webhook was installed can
+// still have their metadata updated and their finalizers removed.
+func skipValidation(obj metav1.Object, specUnchanged bool) bool {
+	return obj.GetDeletionTimestamp() != nil || specUnchanged
+}
+
+// argoCDValidator validates ArgoCD resources on create and update.
+type argoCDValidator struct {
+	decoder *admission.Decoder
+}
+
+// Handle will deny the request when the ArgoCD in the request is not valid.
+func (v *argoCDValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &argoprojv1b1.ArgoCD{}
+	if err := v.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		old := &argoprojv1b1.ArgoCD{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// The original v1alpha1 string fields are kept in annotations, these are validated as part of the spec.
+		unchanged := reflect.DeepEqual(cr.Spec, old.Spec) && reflect.DeepEqual(cr.Annotations, old.Annotations)
+		if skipValidation(cr, unchanged) {
+			return admission.Allowed("")
+		}
+	}
+
+	if err := validation.ValidateArgoCD(cr, argocd.IsRouteAPIAvailable()); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder into the argoCDValidator.
+func (v *argoCDValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// argoCDExportValidator validates ArgoCDExport resources on create and update.
+type argoCDExportValidator struct {
+	decoder *admission.Decoder
+}
+
+// Handle will deny the request when the ArgoCDExport in the request is not valid.
+func (v *argoCDExportValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &argoprojv1a1.ArgoCDExport{}
+	if err := v.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		old := &argoprojv1a1.ArgoCDExport{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if skipValidation(cr, reflect.DeepEqual(cr.Spec, old.Spec)) {
+			return admission.Allowed("")
+		}
+	}
+
+	if err := validation.ValidateArgoCDExport(cr); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder into the argoCDExportValidator.
+func (v *argoCDExportValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
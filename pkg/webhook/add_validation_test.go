@@ -0,0 +1,148 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/argoproj-labs/argocd-operator/pkg/apis"
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1alpha1"
+	argoprojv1b1 "github.com/argoproj-labs/argocd-operator/pkg/apis/argoproj/v1beta1"
+)
+
+func makeTestDecoder(t *testing.T) *admission.Decoder {
+	s := scheme.Scheme
+	assert.NilError(t, apis.AddToScheme(s))
+
+	d, err := admission.NewDecoder(s)
+	assert.NilError(t, err)
+	return d
+}
+
+func makeTestRequest(t *testing.T, op admissionv1beta1.Operation, obj, old runtime.Object) admission.Request {
+	req := admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: op,
+		},
+	}
+
+	data, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	req.Object = runtime.RawExtension{Raw: data}
+
+	if old != nil {
+		data, err = json.Marshal(old)
+		assert.NilError(t, err)
+		req.OldObject = runtime.RawExtension{Raw: data}
+	}
+	return req
+}
+
+func makeTestArgoCD(policy string) *argoprojv1b1.ArgoCD {
+	return &argoprojv1b1.ArgoCD{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: argoprojv1b1.SchemeGroupVersion.String(),
+			Kind:       "ArgoCD",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd",
+			Namespace: "argocd",
+		},
+		Spec: argoprojv1b1.ArgoCDSpec{
+			RBAC: argoprojv1b1.ArgoCDRBACSpec{
+				Policy: &policy,
+			},
+		},
+	}
+}
+
+func TestArgoCDValidator_Handle(t *testing.T) {
+	v := &argoCDValidator{}
+	assert.NilError(t, v.InjectDecoder(makeTestDecoder(t)))
+
+	valid := makeTestArgoCD("g, admins, role:admin")
+	invalid := makeTestArgoCD("g, admins")
+
+	resp := v.Handle(context.TODO(), makeTestRequest(t, admissionv1beta1.Create, valid, nil))
+	assert.Assert(t, resp.Allowed)
+
+	resp = v.Handle(context.TODO(), makeTestRequest(t, admissionv1beta1.Create, invalid, nil))
+	assert.Assert(t, !resp.Allowed)
+	assert.Assert(t, resp.Result != nil)
+	assert.Equal(t, resp.Result.Reason, metav1.StatusReason(
+		"spec.rbac.policy: Invalid value: \"g, admins\": line 1: role bindings must be in the form 'g, subject, inherited-subject', found 2 fields"))
+
+	resp = v.Handle(context.TODO(), makeTestRequest(t, admissionv1beta1.Update, invalid, valid))
+	assert.Assert(t, !resp.Allowed)
+}
+
+func TestArgoCDValidator_Handle_unchanged(t *testing.T) {
+	v := &argoCDValidator{}
+	assert.NilError(t, v.InjectDecoder(makeTestDecoder(t)))
+
+	// Resources created before the webhook was installed must still be able to add and remove finalizers.
+	invalid := makeTestArgoCD("g, admins")
+	updated := invalid.DeepCopy()
+	updated.Finalizers = []string{"argoproj.io/finalizer"}
+
+	resp := v.Handle(context.TODO(), makeTestRequest(t, admissionv1beta1.Update, updated, invalid))
+	assert.Assert(t, resp.Allowed)
+
+	deleting := makeTestArgoCD("g, admins, role:admin, role:readonly")
+	now := metav1.NewTime(time.Now())
+	deleting.DeletionTimestamp = &now
+
+	resp = v.Handle(context.TODO(), makeTestRequest(t, admissionv1beta1.Update, deleting, invalid))
+	assert.Assert(t, resp.Allowed)
+}
+
+func TestArgoCDExportValidator_Handle(t *testing.T) {
+	v := &argoCDExportValidator{}
+	assert.NilError(t, v.InjectDecoder(makeTestDecoder(t)))
+
+	export := &argoprojv1a1.ArgoCDExport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: argoprojv1a1.SchemeGroupVersion.String(),
+			Kind:       "ArgoCDExport",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd-export",
+			Namespace: "argocd",
+		},
+		Spec: argoprojv1a1.ArgoCDExportSpec{
+			Argocd: "argocd",
+			Storage: &argoprojv1a1.ArgoCDExportStorageSpec{
+				Backend: "aws",
+			},
+		},
+	}
+
+	resp := v.Handle(context.TODO(), makeTestRequest(t, admissionv1beta1.Create, export, nil))
+	assert.Assert(t, !resp.Allowed)
+
+	export.Spec.Storage.SecretName = "aws-creds"
+	resp = v.Handle(context.TODO(), makeTestRequest(t, admissionv1beta1.Create, export, nil))
+	assert.Assert(t, resp.Allowed)
+}